@@ -21,6 +21,12 @@ variable "enable_ha" {
   default     = false
 }
 
+module "key_vault" {
+  source = "../../components/azurerm/key-vault"
+
+  name = "${var.namespace}-kv"
+}
+
 output "namespace" {
   value       = var.namespace
   description = "The ArgoCD namespace"
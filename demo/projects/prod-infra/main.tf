@@ -21,6 +21,10 @@ variable "project_name" {
   default     = "prod-infra"
 }
 
+module "argocd" {
+  source = "../../bases/k8s-argocd"
+}
+
 output "environment" {
   value       = var.environment
   description = "The environment"
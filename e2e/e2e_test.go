@@ -7,8 +7,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 )
 
 // skipIfNoTofu skips the test if tofu is not installed
@@ -471,6 +473,63 @@ func TestE2E_DescribeCommand_JSON(t *testing.T) {
 	}
 }
 
+func TestE2E_GraphCommand_JSON(t *testing.T) {
+	motfBinary := buildMotf(t)
+	demoPath := getDemoPath(t)
+
+	cmd := exec.Command(motfBinary, "graph", "key-vault", "--reverse", "--format", "json")
+	cmd.Dir = demoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf graph failed: %v\nOutput: %s", err, output)
+	}
+
+	var result struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+		Edges []struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"edges"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("graph --format json output is not valid JSON: %v\nOutput: %s", err, output)
+	}
+
+	names := make(map[string]bool)
+	for _, n := range result.Nodes {
+		names[n.Name] = true
+	}
+	for _, want := range []string{"key-vault", "k8s-argocd", "prod-infra"} {
+		if !names[want] {
+			t.Errorf("expected node %s in graph, got: %s", want, output)
+		}
+	}
+	if names["storage-account"] {
+		t.Errorf("storage-account does not depend on key-vault, got: %s", output)
+	}
+	if len(result.Edges) != 2 {
+		t.Errorf("expected 2 edges, got %d: %s", len(result.Edges), output)
+	}
+}
+
+func TestE2E_GraphCommand_DOT(t *testing.T) {
+	motfBinary := buildMotf(t)
+	demoPath := getDemoPath(t)
+
+	cmd := exec.Command(motfBinary, "graph")
+	cmd.Dir = demoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf graph failed: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(string(output), `"projects/prod-infra" -> "bases/k8s-argocd";`) {
+		t.Errorf("expected prod-infra -> k8s-argocd edge, got: %s", output)
+	}
+}
+
 func TestE2E_ListChangedCommand(t *testing.T) {
 	motfBinary := buildMotf(t)
 	tmpDir := setupCleanGitRepo(t)
@@ -593,6 +652,50 @@ func TestE2E_ListChangedCommand_WithSearch(t *testing.T) {
 	}
 }
 
+func TestE2E_ListChangedCommand_Dependents(t *testing.T) {
+	motfBinary := buildMotf(t)
+	tmpDir := setupGitRepoWithModules(t, []string{"storage-module", "network-module"})
+
+	// Create a project that sources storage-module through a local path
+	projectDir := filepath.Join(tmpDir, "projects", "prod")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatalf("failed to create project dir: %v", err)
+	}
+	content := "module \"storage\" {\n  source = \"../../components/storage-module\"\n}\n"
+	if err := os.WriteFile(filepath.Join(projectDir, "main.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write project: %v", err)
+	}
+	commitAll(t, tmpDir, "add project")
+
+	// Change only the component
+	addUncommittedFile(t, tmpDir, []string{"storage-module"}, "outputs.tf", "output \"test_%s\" { value = \"changed\" }\n")
+
+	// Without --dependents only the component is listed
+	cmd := exec.Command(motfBinary, "list", "--changed", "--ref", "HEAD", "--names")
+	cmd.Dir = tmpDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf list --changed failed: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(string(output), "prod") {
+		t.Errorf("expected prod to be excluded without --dependents, got: %s", output)
+	}
+
+	// With --dependents the project that sources it is included
+	cmd = exec.Command(motfBinary, "list", "--changed", "--ref", "HEAD", "--names", "--dependents")
+	cmd.Dir = tmpDir
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf list --changed --dependents failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(string(output), "storage-module") || !strings.Contains(string(output), "prod") {
+		t.Errorf("expected storage-module and prod in output, got: %s", output)
+	}
+	if strings.Contains(string(output), "network-module") {
+		t.Errorf("expected network-module to be excluded, got: %s", output)
+	}
+}
+
 func TestE2E_ArgsFlag(t *testing.T) {
 	motfBinary := buildMotf(t)
 	demoPath := getDemoPath(t)
@@ -865,6 +968,38 @@ func TestE2E_PlanNamingComponent(t *testing.T) {
 	}
 }
 
+// TestE2E_ApplyDestroyGuards tests that apply and destroy refuse unsafe runs before invoking terraform/tofu
+func TestE2E_ApplyDestroyGuards(t *testing.T) {
+	motfBinary := buildMotf(t)
+	demoPath := getDemoPath(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"apply all without yes", []string{"apply", "--all"}, "refusing to apply multiple modules without --yes"},
+		{"apply parallel without yes", []string{"apply", "storage-account", "--parallel"}, "refusing to apply with --parallel without --yes"},
+		{"destroy changed without yes", []string{"destroy", "--changed"}, "refusing to destroy multiple modules without --yes"},
+		{"destroy project without confirm", []string{"destroy", "prod-infra", "--yes"}, "--confirm prod-infra"},
+		{"apply missing plan file", []string{"apply", "storage-account", "--plan-file", "does-not-exist.tfplan"}, "plan file 'does-not-exist.tfplan' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(motfBinary, tt.args...)
+			cmd.Dir = demoPath
+			output, err := cmd.CombinedOutput()
+			if err == nil {
+				t.Fatalf("expected motf %v to fail\nOutput: %s", tt.args, output)
+			}
+			if !strings.Contains(string(output), tt.wantErr) {
+				t.Errorf("expected %q in output, got: %s", tt.wantErr, output)
+			}
+		})
+	}
+}
+
 // TestE2E_PlanWithInitFlag tests plan with -i flag to run init first
 func TestE2E_PlanWithInitFlag(t *testing.T) {
 	t.Cleanup(func() { cleanupTerraformFiles(t) })
@@ -973,6 +1108,187 @@ tasks:
 	}
 }
 
+// TestE2E_TaskRunMultipleModules tests running a task on several modules by name and with --all
+func TestE2E_TaskRunMultipleModules(t *testing.T) {
+	motfBinary := buildMotf(t)
+	tmpDir := t.TempDir()
+
+	createModules(t, tmpDir, []string{"alpha", "beta", "gamma"})
+
+	configContent := `binary: terraform
+tasks:
+  name:
+    shell: sh
+    command: echo "module=$MOTF_MODULE_NAME"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{"names", []string{"task", "-t", "name", "alpha", "gamma"}, []string{"module=alpha", "module=gamma"}, []string{"module=beta"}},
+		{"all", []string{"task", "-t", "name", "--all"}, []string{"module=alpha", "module=beta", "module=gamma"}, nil},
+		{"search", []string{"task", "-t", "name", "-s", "*eta"}, []string{"module=beta"}, []string{"module=alpha", "module=gamma"}},
+		{"type", []string{"task", "-t", "name", "--type", "base"}, []string{"No modules matched the selection"}, []string{"module="}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(motfBinary, tt.args...)
+			cmd.Dir = tmpDir
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("motf %v failed: %v\nOutput: %s", tt.args, err, output)
+			}
+
+			outputStr := string(output)
+			for _, want := range tt.want {
+				if !strings.Contains(outputStr, want) {
+					t.Errorf("expected %q in output, got: %s", want, outputStr)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(outputStr, notWant) {
+					t.Errorf("did not expect %q in output, got: %s", notWant, outputStr)
+				}
+			}
+		})
+	}
+}
+
+// TestE2E_ListTypeFilter tests filtering list output by module type
+func TestE2E_ListTypeFilter(t *testing.T) {
+	motfBinary := buildMotf(t)
+	demoPath := getDemoPath(t)
+
+	cmd := exec.Command(motfBinary, "list", "--type", "project", "--names")
+	cmd.Dir = demoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf list --type failed: %v\nOutput: %s", err, output)
+	}
+
+	outputStr := string(output)
+	if !strings.Contains(outputStr, "prod-infra") {
+		t.Errorf("expected 'prod-infra' in output, got: %s", outputStr)
+	}
+	if strings.Contains(outputStr, "storage-account") {
+		t.Errorf("did not expect components in output, got: %s", outputStr)
+	}
+}
+
+// TestE2E_ModuleMetadata tests --tag selection and per-module binary overrides from .motf.module.yml
+func TestE2E_ModuleMetadata(t *testing.T) {
+	motfBinary := buildMotf(t)
+	tmpDir := t.TempDir()
+
+	createModules(t, tmpDir, []string{"alpha", "beta"})
+
+	metadata := `tags: [tofu-ready]
+lifecycle: experimental
+binary: tofu
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "components", "alpha", ".motf.module.yml"), []byte(metadata), 0644); err != nil {
+		t.Fatalf("failed to write module metadata: %v", err)
+	}
+
+	configContent := `binary: terraform
+tasks:
+  binary:
+    shell: sh
+    command: echo "$MOTF_MODULE_NAME uses $MOTF_BINARY"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := exec.Command(motfBinary, "task", "-t", "binary", "alpha", "beta")
+	cmd.Dir = tmpDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf task failed: %v\nOutput: %s", err, output)
+	}
+	for _, want := range []string{"alpha uses tofu", "beta uses terraform"} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	cmd = exec.Command(motfBinary, "list", "--tag", "tofu-ready", "--json")
+	cmd.Dir = tmpDir
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf list --tag failed: %v\nOutput: %s", err, output)
+	}
+	outputStr := string(output)
+	if !strings.Contains(outputStr, `"lifecycle": "experimental"`) || strings.Contains(outputStr, "beta") {
+		t.Errorf("expected only alpha with its metadata, got: %s", outputStr)
+	}
+}
+
+// TestE2E_CustomModuleKinds tests discovery, list, and --changed with module kinds from .motf.yml
+func TestE2E_CustomModuleKinds(t *testing.T) {
+	motfBinary := buildMotf(t)
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+
+	for _, dir := range []string{"modules/network", "stacks/platform", "live/prod"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, dir, "main.tf"), []byte("# "+dir+"\n"), 0644); err != nil {
+			t.Fatalf("failed to write main.tf in %s: %v", dir, err)
+		}
+	}
+
+	configContent := `module_kinds:
+  - dir: modules
+    type: module
+    order: 1
+  - dir: stacks
+    type: stack
+    order: 2
+  - dir: live
+    type: live
+    order: 3
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	commitAll(t, tmpDir, "initial")
+
+	cmd := exec.Command(motfBinary, "list", "--json")
+	cmd.Dir = tmpDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf list failed: %v\nOutput: %s", err, output)
+	}
+	for _, want := range []string{`"type": "module"`, `"type": "stack"`, `"type": "live"`} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("expected %s in output, got: %s", want, output)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "stacks", "platform", "variables.tf"), []byte("# changed\n"), 0644); err != nil {
+		t.Fatalf("failed to modify module: %v", err)
+	}
+
+	cmd = exec.Command(motfBinary, "list", "--changed", "--ref", "HEAD", "--type", "stack", "--names")
+	cmd.Dir = tmpDir
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf list --changed failed: %v\nOutput: %s", err, output)
+	}
+	if strings.TrimSpace(string(output)) != "platform" {
+		t.Errorf("expected only 'platform' as changed stack, got: %s", output)
+	}
+}
+
 // TestE2E_TaskRunOnExample tests running a task on an example
 func TestE2E_TaskRunOnExample(t *testing.T) {
 	motfBinary := buildMotf(t)
@@ -1242,3 +1558,295 @@ func TestE2E_ParallelFlag_MaxParallel(t *testing.T) {
 		}
 	}
 }
+
+// TestE2E_DiscoveryRules verifies discovery patterns from .motf.yml and .motfignore
+func TestE2E_DiscoveryRules(t *testing.T) {
+	motfBinary := buildMotf(t)
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+
+	for _, dir := range []string{
+		"components/network",
+		"components/network/modules/subnet",
+		"components/vendor/acme",
+		"components/app/.terragrunt-cache/copy",
+	} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, dir, "main.tf"), []byte("# "+dir+"\n"), 0644); err != nil {
+			t.Fatalf("failed to write main.tf in %s: %v", dir, err)
+		}
+	}
+
+	configContent := `discovery:
+  include:
+    - components/network/modules
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motfignore"), []byte("vendor/\n.terragrunt-cache/\n"), 0644); err != nil {
+		t.Fatalf("failed to write .motfignore: %v", err)
+	}
+	commitAll(t, tmpDir, "initial")
+
+	cmd := exec.Command(motfBinary, "list", "--names")
+	cmd.Dir = tmpDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf list failed: %v\nOutput: %s", err, output)
+	}
+	if got := strings.Fields(string(output)); len(got) != 2 || got[0] != "network" || got[1] != "subnet" {
+		t.Errorf("expected only network and subnet, got: %s", output)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "components", "vendor", "acme", "variables.tf"), []byte("# changed\n"), 0644); err != nil {
+		t.Fatalf("failed to modify module: %v", err)
+	}
+
+	cmd = exec.Command(motfBinary, "list", "--changed", "--ref", "HEAD", "--names")
+	cmd.Dir = tmpDir
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("motf list --changed failed: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(string(output), "acme") {
+		t.Errorf("expected excluded module to be ignored by --changed, got: %s", output)
+	}
+}
+
+// TestE2E_RunReports tests that --report writes JUnit and JSON reports of a task run
+func TestE2E_RunReports(t *testing.T) {
+	motfBinary := buildMotf(t)
+	tmpDir := t.TempDir()
+
+	createModules(t, tmpDir, []string{"alpha", "beta"})
+
+	configContent := `binary: terraform
+tasks:
+  check:
+    shell: sh
+    command: |
+      if [ "$MOTF_MODULE_NAME" = "beta" ]; then
+        echo "beta is broken" >&2
+        exit 4
+      fi
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := exec.Command(motfBinary, "task", "-t", "check", "--all", "--report", "junit=reports/junit.xml", "--report", "json=reports/motf.json")
+	cmd.Dir = tmpDir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected task to fail for beta, got: %s", output)
+	}
+
+	junit, err := os.ReadFile(filepath.Join(tmpDir, "reports", "junit.xml"))
+	if err != nil {
+		t.Fatalf("expected JUnit report: %v\nOutput: %s", err, output)
+	}
+	for _, want := range []string{`<testsuite name="motf task check" tests="2" failures="1"`, `<testcase name="alpha"`, "beta is broken"} {
+		if !strings.Contains(string(junit), want) {
+			t.Errorf("expected %q in JUnit report, got: %s", want, junit)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "reports", "motf.json"))
+	if err != nil {
+		t.Fatalf("expected JSON report: %v", err)
+	}
+	var report struct {
+		Command string `json:"command"`
+		Modules []struct {
+			Name     string `json:"name"`
+			Status   string `json:"status"`
+			ExitCode *int   `json:"exit_code"`
+		} `json:"modules"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v\n%s", err, data)
+	}
+	if report.Command != "task check" || len(report.Modules) != 2 {
+		t.Fatalf("unexpected report: %s", data)
+	}
+	beta := report.Modules[1]
+	if beta.Name != "beta" || beta.Status != "failed" || beta.ExitCode == nil || *beta.ExitCode != 4 {
+		t.Errorf("expected beta to fail with exit code 4, got: %s", data)
+	}
+}
+
+func TestE2E_FailFast(t *testing.T) {
+	motfBinary := buildMotf(t)
+	tmpDir := t.TempDir()
+
+	createModules(t, tmpDir, []string{"alpha", "beta", "gamma"})
+
+	configContent := `binary: terraform
+tasks:
+  check:
+    shell: sh
+    command: |
+      echo "ran $MOTF_MODULE_NAME"
+      [ "$MOTF_MODULE_NAME" != "alpha" ]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := exec.Command(motfBinary, "task", "-t", "check", "--all", "--fail-fast")
+	cmd.Dir = tmpDir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected task to fail for alpha, got: %s", output)
+	}
+
+	out := string(output)
+	if !strings.Contains(out, "ran alpha") || strings.Contains(out, "ran beta") || strings.Contains(out, "ran gamma") {
+		t.Errorf("expected only alpha to run, got: %s", out)
+	}
+	for _, want := range []string{
+		"beta (components/beta): cancelled: not started, the run stopped after 1 failed module(s)",
+		"3 modules: 0 ok, 1 failed, 0 skipped, 2 cancelled",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %s", want, out)
+		}
+	}
+}
+
+func TestE2E_TaskTimeoutAndRetries(t *testing.T) {
+	motfBinary := buildMotf(t)
+	tmpDir := t.TempDir()
+
+	createModules(t, tmpDir, []string{"alpha", "beta", "gamma"})
+
+	configContent := `binary: terraform
+execution:
+  retries: 1
+  backoff: 10ms
+tasks:
+  flaky:
+    shell: sh
+    command: |
+      case "$MOTF_MODULE_NAME" in
+      alpha)
+        if [ ! -f .attempted ]; then
+          touch .attempted
+          echo "Error: TLS handshake timeout" >&2
+          exit 1
+        fi ;;
+      beta)
+        sleep 10 ;;
+      esac
+      echo "done $MOTF_MODULE_NAME"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := exec.Command(motfBinary, "task", "-t", "flaky", "--all", "-p", "--timeout", "1s")
+	cmd.Dir = tmpDir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected task to time out for beta, got: %s", output)
+	}
+
+	out := string(output)
+	for _, want := range []string{
+		`Retrying in 10ms (attempt 2 of 2): output matched "TLS handshake timeout"`,
+		"done alpha",
+		"beta (components/beta): timed out after 1s",
+		"MODULE  STATUS   DURATION  RETRIES  ERROR",
+		"3 modules: 2 ok, 1 failed, 0 skipped",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "done beta") {
+		t.Errorf("expected beta to be interrupted, got: %s", out)
+	}
+}
+
+func TestE2E_InterruptForwardsSIGINT(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported on Windows")
+	}
+	motfBinary := buildMotf(t)
+	tmpDir := t.TempDir()
+
+	createModules(t, tmpDir, []string{"alpha", "beta", "gamma"})
+
+	configContent := `binary: terraform
+tasks:
+  slow:
+    shell: sh
+    command: |
+      trap 'echo "stopping $MOTF_MODULE_NAME"; exit 130' INT
+      echo "started $MOTF_MODULE_NAME"
+      sleep 30
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	// A file rather than a buffer, so it can be read while motf runs
+	outPath := filepath.Join(t.TempDir(), "output")
+	outFile, err := os.Create(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = outFile.Close() }()
+	output := func() string {
+		data, _ := os.ReadFile(outPath)
+		return string(data)
+	}
+
+	cmd := exec.Command(motfBinary, "task", "-t", "slow", "--all", "-p", "--max-parallel", "2")
+	cmd.Dir = tmpDir
+	cmd.Stdout = outFile
+	cmd.Stderr = outFile
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start motf: %v", err)
+	}
+	waitErr := make(chan error, 1)
+	go func() { waitErr <- cmd.Wait() }()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for strings.Count(output(), "# started ") < 2 {
+		if time.Now().After(deadline) {
+			_ = cmd.Process.Kill()
+			t.Fatalf("timed out waiting for tasks to start, got: %s", output())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("failed to signal motf: %v", err)
+	}
+	select {
+	case err := <-waitErr:
+		if err == nil {
+			t.Errorf("expected motf to fail after an interrupt, got: %s", output())
+		}
+	case <-time.After(10 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatalf("motf did not stop after SIGINT, got: %s", output())
+	}
+
+	out := output()
+	if strings.Count(out, "# stopping ") != 2 {
+		t.Errorf("expected both running tasks to receive SIGINT, got: %s", out)
+	}
+	for _, want := range []string{
+		"Received SIGINT, interrupting running commands",
+		"Interrupted by SIGINT: 2 module(s) interrupted, 1 not started, 0 finished",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Package cache stores the output of successful commands run on a module,
+// keyed by a hash of everything the result depends on, so that unchanged
+// modules can be skipped and their output replayed.
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// entryFile is the name of the file holding an entry in its directory
+const entryFile = "entry.json"
+
+// outputsDir is the directory of an entry holding its output files
+const outputsDir = "outputs"
+
+// Store is a directory of cached results. Each entry lives in
+// <dir>/<first two characters of key>/<key>/.
+type Store struct {
+	Dir string
+}
+
+// New returns the store in dir
+func New(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// Entry is a cached successful run of a command on a module
+type Entry struct {
+	Command  string        `json:"command"`
+	Module   string        `json:"module"`
+	Created  time.Time     `json:"created"`
+	Duration time.Duration `json:"duration"`
+	Output   []Chunk       `json:"output"`
+	Outputs  []string      `json:"outputs,omitempty"` // output files, slash-separated and relative to the module
+
+	// Annotations are the CI annotations of the run, reported again on cache hits
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// Annotation is an error or warning attached to a file in the CI system's UI
+type Annotation struct {
+	Level   string `json:"level"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Col     int    `json:"col,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message"`
+}
+
+// Replay writes the recorded output of e to stdout and stderr, in the order
+// it was written
+func (e *Entry) Replay(stdout, stderr io.Writer) error {
+	for _, chunk := range e.Output {
+		w := stdout
+		if chunk.Stderr {
+			w = stderr
+		}
+		if _, err := io.WriteString(w, chunk.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// path returns the directory of the entry with key
+func (s *Store) path(key string) string {
+	return filepath.Join(s.Dir, key[:2], key)
+}
+
+// Get returns the entry with key, or nil if there is none
+func (s *Store) Get(key string) (*Entry, error) {
+	data, err := os.ReadFile(filepath.Join(s.path(key), entryFile)) //nolint:gosec // path is inside the cache directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		// A corrupt entry is a miss; it is replaced by the next Put
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// Put stores entry under key, together with its output files copied from
+// moduleDir. An existing entry with the same key is kept.
+func (s *Store) Put(key string, entry *Entry, moduleDir string) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.MkdirTemp(s.Dir, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	for _, rel := range entry.Outputs {
+		if err := copyFile(filepath.Join(moduleDir, filepath.FromSlash(rel)), filepath.Join(tmp, outputsDir, filepath.FromSlash(rel))); err != nil {
+			return fmt.Errorf("failed to store output file: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, entryFile), data, 0644); err != nil { //nolint:gosec // cache entries are not secret
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	final := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(final), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.Rename(tmp, final); err != nil {
+		if _, statErr := os.Stat(final); statErr == nil {
+			// Stored concurrently by another run
+			return nil
+		}
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+	return nil
+}
+
+// Restore copies the output files of the entry with key into moduleDir
+func (s *Store) Restore(key string, entry *Entry, moduleDir string) error {
+	for _, rel := range entry.Outputs {
+		if err := copyFile(filepath.Join(s.path(key), outputsDir, filepath.FromSlash(rel)), filepath.Join(moduleDir, filepath.FromSlash(rel))); err != nil {
+			return fmt.Errorf("failed to restore output file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Stats describes the entries of a store
+type Stats struct {
+	Entries   int
+	Bytes     int64
+	ByCommand map[string]int
+	Oldest    time.Time
+	Newest    time.Time
+}
+
+// Stats returns statistics about the entries in the store. A store whose
+// directory does not exist is empty.
+func (s *Store) Stats() (*Stats, error) {
+	stats := &Stats{ByCommand: make(map[string]int)}
+	err := filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == s.Dir {
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stats.Bytes += info.Size()
+		if d.Name() != entryFile {
+			return nil
+		}
+
+		data, err := os.ReadFile(path) //nolint:gosec // path is inside the cache directory
+		if err != nil {
+			return err
+		}
+		var entry Entry
+		if json.Unmarshal(data, &entry) != nil {
+			return nil
+		}
+		stats.Entries++
+		stats.ByCommand[entry.Command]++
+		if stats.Oldest.IsZero() || entry.Created.Before(stats.Oldest) {
+			stats.Oldest = entry.Created
+		}
+		if entry.Created.After(stats.Newest) {
+			stats.Newest = entry.Created
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+	return stats, nil
+}
+
+// Clean removes all entries from the store
+func (s *Store) Clean() error {
+	if err := os.RemoveAll(s.Dir); err != nil {
+		return fmt.Errorf("failed to clean cache: %w", err)
+	}
+	return nil
+}
+
+// copyFile copies the file src to dst, creating the directories of dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src) //nolint:gosec // src is an output file of a module or cache entry
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst) //nolint:gosec // dst is inside a module or cache entry
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFiles creates files with contents under dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// dirKey returns the key of the files in dir
+func dirKey(t *testing.T, dir string, include, exclude []string) string {
+	t.Helper()
+	key := NewKey()
+	key.Add("command", "validate")
+	if err := key.AddDir("module", dir, include, exclude); err != nil {
+		t.Fatalf("AddDir() returned error: %v", err)
+	}
+	return key.Sum()
+}
+
+func TestKey(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"main.tf": "resource {}", "README.md": "# docs", "tests/main_test.go": "package test"})
+	base := dirKey(t, dir, nil, nil)
+
+	if dirKey(t, dir, nil, nil) != base {
+		t.Error("expected the same files to give the same key")
+	}
+
+	writeFiles(t, dir, map[string]string{".terraform/modules/modules.json": "{}"})
+	if dirKey(t, dir, nil, nil) != base {
+		t.Error("expected .terraform to be left out of the key")
+	}
+
+	withOutputs := dirKey(t, dir, nil, []string{"README.md"})
+	writeFiles(t, dir, map[string]string{"README.md": "# regenerated"})
+	if dirKey(t, dir, nil, []string{"README.md"}) != withOutputs {
+		t.Error("expected excluded files to be left out of the key")
+	}
+	if dirKey(t, dir, nil, nil) == base {
+		t.Error("expected a changed file to change the key")
+	}
+
+	tfOnly := dirKey(t, dir, []string{"*.tf"}, nil)
+	writeFiles(t, dir, map[string]string{"tests/main_test.go": "package changed"})
+	if dirKey(t, dir, []string{"*.tf"}, nil) != tfOnly {
+		t.Error("expected only included files in the key")
+	}
+
+	withoutFile := NewKey()
+	if err := withoutFile.AddFile("modules", filepath.Join(dir, ".terraform", "missing.json")); err != nil {
+		t.Fatal(err)
+	}
+	withFile := NewKey()
+	if err := withFile.AddFile("modules", filepath.Join(dir, ".terraform", "modules", "modules.json")); err != nil {
+		t.Fatal(err)
+	}
+	if withoutFile.Sum() == withFile.Sum() {
+		t.Error("expected an added file to change the key")
+	}
+
+	a, b := NewKey(), NewKey()
+	a.Add("args", "-a b")
+	b.Add("args", "-a", "b")
+	if a.Sum() == b.Sum() {
+		t.Error("expected values to be delimited in the key")
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "cache"))
+	module := t.TempDir()
+	writeFiles(t, module, map[string]string{"README.md": "# generated", "docs/usage.md": "usage"})
+	key := dirKey(t, module, nil, nil)
+
+	if entry, err := store.Get(key); entry != nil || err != nil {
+		t.Fatalf("expected a miss in an empty store, got %+v, %v", entry, err)
+	}
+
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	entry := &Entry{
+		Command:  "task docs",
+		Module:   module,
+		Created:  created,
+		Duration: 2 * time.Second,
+		Output:   []Chunk{{Data: "generated\n"}, {Stderr: true, Data: "warning\n"}},
+		Outputs:  []string{"README.md", "docs/usage.md"},
+	}
+	if err := store.Put(key, entry, module); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+
+	got, err := store.Get(key)
+	if err != nil || got == nil {
+		t.Fatalf("expected a hit, got %+v, %v", got, err)
+	}
+	if got.Command != "task docs" || !got.Created.Equal(created) || len(got.Output) != 2 {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := got.Replay(&stdout, &stderr); err != nil {
+		t.Fatalf("Replay() returned error: %v", err)
+	}
+	if stdout.String() != "generated\n" || stderr.String() != "warning\n" {
+		t.Errorf("unexpected replay: stdout %q, stderr %q", stdout.String(), stderr.String())
+	}
+
+	target := t.TempDir()
+	if err := store.Restore(key, got, target); err != nil {
+		t.Fatalf("Restore() returned error: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(target, "docs", "usage.md")); err != nil || string(data) != "usage" {
+		t.Errorf("expected the output files to be restored, got %q, %v", data, err)
+	}
+
+	stats, err := store.Stats()
+	if err != nil {
+		t.Fatalf("Stats() returned error: %v", err)
+	}
+	if stats.Entries != 1 || stats.ByCommand["task docs"] != 1 || stats.Bytes == 0 || !stats.Oldest.Equal(created) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if err := store.Clean(); err != nil {
+		t.Fatalf("Clean() returned error: %v", err)
+	}
+	if stats, err := store.Stats(); err != nil || stats.Entries != 0 {
+		t.Errorf("expected an empty store after Clean(), got %+v, %v", stats, err)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var out bytes.Buffer
+	recorder := &Recorder{}
+	stdout, stderr := recorder.Writers(&out, &out)
+
+	_, _ = stdout.Write([]byte("a"))
+	_, _ = stdout.Write([]byte("b\n"))
+	_, _ = stderr.Write([]byte("error\n"))
+	_, _ = stdout.Write([]byte("c\n"))
+
+	if out.String() != "ab\nerror\nc\n" {
+		t.Errorf("expected writes to pass through, got %q", out.String())
+	}
+	want := []Chunk{{Data: "ab\n"}, {Stderr: true, Data: "error\n"}, {Data: "c\n"}}
+	got := recorder.Output()
+	if len(got) != len(want) {
+		t.Fatalf("Output() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Output()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
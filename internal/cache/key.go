@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+)
+
+// keyVersion changes whenever the way keys are computed changes
+const keyVersion = "motf-cache-v1"
+
+// skipDirs are never part of a key: terraform's working directory and
+// directories of version control and tools
+var skipDirs = map[string]bool{
+	".terraform":        true,
+	".terragrunt-cache": true,
+	".git":              true,
+}
+
+// Key builds a cache key from named values and the contents of directories
+type Key struct {
+	h hash.Hash
+}
+
+// NewKey returns an empty key
+func NewKey() *Key {
+	k := &Key{h: sha256.New()}
+	k.Add("version", keyVersion)
+	return k
+}
+
+// Add adds a named value to the key
+func (k *Key) Add(name string, values ...string) {
+	_, _ = fmt.Fprintf(k.h, "%s\x00%d\x00", name, len(values))
+	for _, v := range values {
+		_, _ = fmt.Fprintf(k.h, "%d\x00%s\x00", len(v), v)
+	}
+}
+
+// AddDir adds the files under dir to the key under name: their paths
+// relative to dir and their contents. With include patterns, only matching
+// files are added; files matching exclude patterns are left out. Patterns
+// are gitignore-style and relative to dir.
+func (k *Key) AddDir(name, dir string, include, exclude []string) error {
+	files, err := Files(dir, include, exclude)
+	if err != nil {
+		return err
+	}
+
+	k.Add(name, files...)
+	for _, rel := range files {
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(rel))) //nolint:gosec // files of a module being hashed
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", rel, err)
+		}
+		_, err = io.Copy(k.h, f)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// AddFile adds the contents of the file at path to the key under name, or
+// that it does not exist
+func (k *Key) AddFile(name, path string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // a file of a module being hashed
+	if os.IsNotExist(err) {
+		k.Add(name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	k.Add(name, string(data))
+	return nil
+}
+
+// Sum returns the key as a hex string
+func (k *Key) Sum() string {
+	return hex.EncodeToString(k.h.Sum(nil))
+}
+
+// Files returns the slash-separated paths of the regular files under dir,
+// relative to dir and sorted, that match include (all files when empty) and
+// do not match exclude
+func Files(dir string, include, exclude []string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if len(include) > 0 && !finder.MatchesPath(rel, include) {
+			return nil
+		}
+		if len(exclude) > 0 && finder.MatchesPath(rel, exclude) {
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files of %s: %w", dir, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
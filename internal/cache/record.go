@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Chunk is a piece of recorded output
+type Chunk struct {
+	Stderr bool   `json:"stderr,omitempty"`
+	Data   string `json:"data"`
+}
+
+// Recorder records the output of a command written to stdout and stderr,
+// keeping the order of writes across both
+type Recorder struct {
+	mu     sync.Mutex
+	chunks []*recordedChunk
+}
+
+// recordedChunk is a chunk being recorded; consecutive writes to the same
+// stream are appended to its buffer
+type recordedChunk struct {
+	stderr bool
+	data   bytes.Buffer
+}
+
+// Writers returns writers that write to stdout and stderr and record what
+// is written
+func (r *Recorder) Writers(stdout, stderr io.Writer) (io.Writer, io.Writer) {
+	return &recordingWriter{r: r, w: stdout}, &recordingWriter{r: r, w: stderr, stderr: true}
+}
+
+// Output returns the recorded output
+func (r *Recorder) Output() []Chunk {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	chunks := make([]Chunk, 0, len(r.chunks))
+	for _, c := range r.chunks {
+		chunks = append(chunks, Chunk{Stderr: c.stderr, Data: c.data.String()})
+	}
+	return chunks
+}
+
+// recordingWriter writes to w and records the writes in r
+type recordingWriter struct {
+	r      *Recorder
+	w      io.Writer
+	stderr bool
+}
+
+// Write implements io.Writer
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.r.mu.Lock()
+	n := len(w.r.chunks)
+	if n == 0 || w.r.chunks[n-1].stderr != w.stderr {
+		w.r.chunks = append(w.r.chunks, &recordedChunk{stderr: w.stderr})
+		n++
+	}
+	w.r.chunks[n-1].data.Write(p)
+	w.r.mu.Unlock()
+	return w.w.Write(p)
+}
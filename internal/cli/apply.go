@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// applyPlanFileFlag is a saved plan file (from 'motf plan --out') to apply, relative to each module directory
+var applyPlanFileFlag string
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply [module-name...]",
+	Short: "Run terraform/tofu apply on a module",
+	Long: `Run terraform/tofu apply on a module: a component, base, or project by default,
+or a module of any kind configured in module_kinds.
+
+Use the --example/-e flag to run apply on a specific example instead of the module itself.
+Use the --plan-file flag to apply a plan saved with 'motf plan --out'.
+
+Applying a single module prompts for approval unless --yes is given.
+Applying several modules (module names, --all, --type, --search, or --changed)
+or using --parallel is refused unless --yes is given, since terraform/tofu
+cannot prompt for each module.
+
+Examples:
+  motf apply storage-account                          # Apply storage-account (prompts for approval)
+  motf apply storage-account -e basic --yes           # Apply the 'basic' example without prompting
+  motf plan storage-account --out tfplan              # Save a plan...
+  motf apply storage-account --plan-file tfplan       # ...and apply exactly that plan
+  motf apply -i --changed --dag --yes                 # Apply changed modules in dependency order`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkBulkApproval("apply", args); err != nil {
+			return err
+		}
+		gitRoot, err := repoRoot()
+		if err != nil {
+			return err
+		}
+
+		if usesModuleSelection(args) {
+			modules, err := selectModules(args)
+			if err != nil {
+				return err
+			}
+			return runOnModuleSetWithPath(modules, func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error {
+				tfRunner, err := moduleRunner(ctx, gitRoot, moduleAbsPath)
+				if err != nil {
+					return err
+				}
+				if initFlag {
+					if err := tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
+						return err
+					}
+				}
+				extraArgs, err := applyArgs(moduleAbsPath)
+				if err != nil {
+					return err
+				}
+				return tfRunner.RunApplyWithOutput(moduleAbsPath, stdout, stderr, extraArgs...)
+			})
+		}
+
+		targetPath, err := resolveTargetWithExample(args, exampleFlag)
+		if err != nil {
+			return err
+		}
+
+		tfRunner, err := moduleRunner(runContext, gitRoot, targetPath)
+		if err != nil {
+			return err
+		}
+
+		// Run init first if flag is set
+		if initFlag {
+			if err := tfRunner.RunInit(targetPath); err != nil {
+				return err
+			}
+		}
+
+		extraArgs, err := applyArgs(targetPath)
+		if err != nil {
+			return err
+		}
+		return tfRunner.RunApply(targetPath, extraArgs...)
+	},
+}
+
+// applyArgs returns the extra arguments for terraform/tofu apply in moduleDir.
+// A saved plan file is passed last and must exist; otherwise --yes adds -auto-approve.
+func applyArgs(moduleDir string) ([]string, error) {
+	args := append([]string{}, argsFlag...)
+
+	if applyPlanFileFlag == "" {
+		if yesFlag {
+			args = append(args, "-auto-approve")
+		}
+		return args, nil
+	}
+
+	planPath := applyPlanFileFlag
+	if !filepath.IsAbs(planPath) {
+		planPath = filepath.Join(moduleDir, planPath)
+	}
+	if _, err := os.Stat(planPath); err != nil {
+		return nil, fmt.Errorf("plan file '%s' not found in %s (create it with 'motf plan --out %s')", applyPlanFileFlag, moduleDir, applyPlanFileFlag)
+	}
+
+	return append(args, applyPlanFileFlag), nil
+}
+
+// checkBulkApproval refuses to run action in parallel, or on modules selected
+// for the selection pipeline, unless --yes was given: terraform/tofu cannot
+// prompt for approval there. It runs before modules are selected, so a
+// refused run never depends on git; only a single module name is resolved,
+// to name the module in the error.
+func checkBulkApproval(action string, args []string) error {
+	if yesFlag {
+		return nil
+	}
+	if parallelFlag {
+		return fmt.Errorf("refusing to %s with --parallel without --yes", action)
+	}
+	if !usesModuleSelection(args) {
+		return nil
+	}
+	// A single module name without selector flags, made a selection by --report or --log-dir
+	if len(args) == 1 && !usesModuleSelection(nil) {
+		modules, err := selectModules(args)
+		if err != nil {
+			return err
+		}
+		if len(modules) == 1 {
+			return fmt.Errorf("refusing to %s %s without --yes: modules run with --report or --log-dir cannot prompt for approval", action, modules[0].Path)
+		}
+	}
+	return fmt.Errorf("refusing to %s multiple modules without --yes", action)
+}
+
+func init() {
+	applyCmd.Flags().BoolVarP(&yesFlag, "yes", "y", false, "Skip approval prompts (required with --parallel or multiple modules)")
+	applyCmd.Flags().StringVar(&applyPlanFileFlag, "plan-file", "", "Apply a saved plan file from 'motf plan --out' (relative to each module directory)")
+	applyCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
+	applyCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	applyCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	applyCmd.Flags().StringVar(&typeFlag, "type", "", "Only run on modules of this type, as configured in module_kinds (default: component, base, or project)")
+	applyCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Only run on modules matching a wildcard (e.g., *storage*)")
+	applyCmd.Flags().StringArrayVar(&tagFlag, "tag", []string{}, "Only run on modules with this tag (can be specified multiple times)")
+	applyCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	applyCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	applyCmd.Flags().BoolVar(&dependentsFlag, "dependents", false, "Also include modules that transitively depend on changed modules (requires --changed)")
+	applyCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
+	applyCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	applyCmd.Flags().BoolVar(&failFastFlag, "fail-fast", false, "Stop after the first failed module: cancel queued modules and interrupt running ones")
+	applyCmd.Flags().IntVar(&maxFailuresFlag, "max-failures", 0, "Stop after this many modules have failed (default: no limit)")
+	applyCmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Interrupt each command or task running longer than this, e.g. 20m (default: execution.timeout, no timeout)")
+	applyCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry commands failing with an error matching execution.retry_on up to this many times; apply itself only if execution.commands.apply.retries is set (default: execution.retries)")
+	applyCmd.Flags().StringVar(&outputModeFlag, "output-mode", "", "How to print output of multiple modules: stream, grouped, or quiet (default: stream)")
+	applyCmd.Flags().StringVar(&ciFlag, "ci", "", "Format output for a CI system: github, gitlab, or none (default: detected from GITHUB_ACTIONS/GITLAB_CI)")
+	applyCmd.Flags().BoolVar(&dagFlag, "dag", false, "Run modules in dependency order and skip dependents of failed modules")
+	applyCmd.Flags().StringArrayVar(&reportFlag, "report", []string{}, "Write a report of the run: junit=<path> or json=<path> (can be specified multiple times)")
+	applyCmd.Flags().StringVar(&logDirFlag, "log-dir", "", "Write each module's uncolored output to <dir>/<type>/<name>.log")
+	applyCmd.Flags().BoolVar(&noSummaryFlag, "no-summary", false, "Don't print the summary table after running on several modules")
+	rootCmd.AddCommand(applyCmd)
+}
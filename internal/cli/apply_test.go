@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyCmd_Flags(t *testing.T) {
+	tests := []struct {
+		name      string
+		shorthand string
+	}{
+		{"yes", "y"},
+		{"plan-file", ""},
+		{"init", "i"},
+		{"example", "e"},
+		{"changed", ""},
+		{"parallel", "p"},
+		{"dag", ""},
+		{"report", ""},
+		{"log-dir", ""},
+		{"no-summary", ""},
+		{"fail-fast", ""},
+		{"max-failures", ""},
+		{"timeout", ""},
+		{"retries", ""},
+		{"output-mode", ""},
+		{"ci", ""},
+	}
+
+	for _, tt := range tests {
+		flag := applyCmd.Flags().Lookup(tt.name)
+		if flag == nil {
+			t.Errorf("applyCmd should have --%s flag", tt.name)
+			continue
+		}
+		if flag.Shorthand != tt.shorthand {
+			t.Errorf("--%s: expected shorthand '%s', got '%s'", tt.name, tt.shorthand, flag.Shorthand)
+		}
+	}
+}
+
+func TestCheckBulkApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func()
+		args    []string
+		wantErr string
+	}{
+		{"single module", func() {}, []string{"storage-account"}, ""},
+		{"multiple modules", func() {}, []string{"storage-account", "key-vault"}, "refusing to apply multiple modules without --yes"},
+		{"changed", func() { changedFlag = true }, nil, "refusing to apply multiple modules without --yes"},
+		{"one module with report", func() { reportFlag = []string{"json=report.json"} }, []string{"storage-account"}, "refusing to apply " + filepath.Join("components", "storage-account") + " without --yes: modules run with"},
+		{"one module with a selector", func() { typeFlag = TypeComponent }, []string{"storage-account"}, "refusing to apply multiple modules without --yes"},
+		{"parallel", func() { parallelFlag = true }, []string{"storage-account"}, "refusing to apply with --parallel without --yes"},
+		{"multiple modules with yes", func() { yesFlag = true }, []string{"storage-account", "key-vault"}, ""},
+		{"parallel with yes", func() { parallelFlag = true; allFlag = true; yesFlag = true }, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			setupSelectionTree(t)
+			tt.setup()
+
+			err := checkBulkApproval("apply", tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyCmd_SingleModuleWithReport(t *testing.T) {
+	resetFlags(t)
+	setupSelectionTree(t)
+	reportFlag = []string{"json=report.json"}
+
+	err := applyCmd.RunE(applyCmd, []string{"storage-account"})
+	if err == nil || !strings.Contains(err.Error(), "refusing to apply "+filepath.Join("components", "storage-account")+" without --yes") {
+		t.Fatalf("expected the single module to be named in the error, got %v", err)
+	}
+}
+
+func TestApplyArgs(t *testing.T) {
+	moduleDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(moduleDir, "tfplan"), []byte("plan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		setup func()
+		want  []string
+	}{
+		{"no flags", func() {}, []string{}},
+		{"yes adds auto-approve", func() { yesFlag = true }, []string{"-auto-approve"}},
+		{"extra args first", func() { argsFlag = []string{"-lock=false"}; yesFlag = true }, []string{"-lock=false", "-auto-approve"}},
+		{"plan file passed last", func() { argsFlag = []string{"-lock=false"}; applyPlanFileFlag = "tfplan" }, []string{"-lock=false", "tfplan"}},
+		{"plan file ignores yes", func() { yesFlag = true; applyPlanFileFlag = "tfplan" }, []string{"tfplan"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			tt.setup()
+
+			got, err := applyArgs(moduleDir)
+			if err != nil {
+				t.Fatalf("applyArgs returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyArgs_MissingPlanFile(t *testing.T) {
+	resetFlags(t)
+	applyPlanFileFlag = "tfplan"
+
+	_, err := applyArgs(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "motf plan --out tfplan") {
+		t.Fatalf("expected missing plan file error, got %v", err)
+	}
+}
+
+func TestApplyCmd_RefusesBulkWithoutYes(t *testing.T) {
+	resetFlags(t)
+	setupSelectionTree(t)
+	allFlag = true
+
+	err := applyCmd.RunE(applyCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Fatalf("expected --yes error, got %v", err)
+	}
+}
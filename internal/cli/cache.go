@@ -0,0 +1,358 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/cache"
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+// cacheFlag enables (or with --cache=false disables) the result cache
+var cacheFlag bool
+
+// modulePathRunner runs a command on the module at moduleAbsPath
+type modulePathRunner func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error
+
+// cacheSpec describes what the result of a command depends on, besides the
+// files of the module and its local dependencies, the binary and cache.env
+type cacheSpec struct {
+	command string   // recorded in entries, e.g. "validate" or "task lint"
+	args    []string // flags, args and settings that change the result
+	inputs  []string // module files the result depends on; all when empty
+	outputs []string // files the command generates, restored on cache hits
+}
+
+// checkCacheFlag rejects --cache on a run that is not a module selection,
+// as only selections are cached
+func checkCacheFlag() error {
+	if cacheFlag {
+		return fmt.Errorf("--cache requires a module selection: pass module names, --all, --changed, --type, --search, or --tag (not --path or --example)")
+	}
+	return nil
+}
+
+// applyCacheFlag enables or disables the cache of c when --cache was given
+func applyCacheFlag(cmd *cobra.Command, c *config.Config) {
+	if !cmd.Flags().Changed("cache") {
+		return
+	}
+	if c.Cache == nil {
+		c.Cache = &config.CacheConfig{}
+	}
+	c.Cache.Enabled = cacheFlag
+}
+
+// cacheStore returns the cache in cache.dir, resolved relative to the config
+// file, or in the motf data directory. It returns nil if there is neither.
+func cacheStore() (*cache.Store, error) {
+	if cfg.Cache != nil && cfg.Cache.Dir != "" {
+		dir := cfg.Cache.Dir
+		if !filepath.IsAbs(dir) && cfg.ConfigPath != "" {
+			dir = filepath.Join(filepath.Dir(cfg.ConfigPath), dir)
+		}
+		return cache.New(dir), nil
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return nil, err
+	}
+	dir := motfDataDir(basePath)
+	if dir == "" {
+		return nil, nil
+	}
+	return cache.New(filepath.Join(dir, "cache")), nil
+}
+
+// withCache returns fn with its results cached, when the cache is enabled.
+// Modules with a cached result are skipped and have their output replayed
+// and output files restored; successful results are stored. Cache errors
+// never fail a module, it then runs without the cache.
+func withCache(spec cacheSpec, fn modulePathRunner) modulePathRunner {
+	if cfg == nil || cfg.Cache == nil || !cfg.Cache.Enabled {
+		return fn
+	}
+	store, err := cacheStore()
+	if err != nil || store == nil {
+		return fn
+	}
+
+	return func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error {
+		key, err := moduleCacheKey(spec, moduleAbsPath)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "Not using the cache: %v\n", err)
+			return fn(ctx, moduleAbsPath, stdout, stderr)
+		}
+
+		entry, err := store.Get(key)
+		if err == nil && entry != nil {
+			err = store.Restore(key, entry, moduleAbsPath)
+			if err == nil {
+				_, _ = fmt.Fprintf(stderr, "Cache hit: replaying output of the run at %s (took %s)\n", entry.Created.Local().Format("2006-01-02 15:04:05"), formatSeconds(entry.Duration))
+				annotationsFrom(ctx).add(annotationsFromCache(entry.Annotations)...)
+				return entry.Replay(stdout, stderr)
+			}
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "Not using the cache: %v\n", err)
+		}
+
+		recorder := &cache.Recorder{}
+		recStdout, recStderr := recorder.Writers(stdout, stderr)
+		// Annotations are recorded per module to be stored with its result
+		moduleCtx, annotations := withAnnotations(ctx)
+		start := time.Now()
+		err = fn(moduleCtx, moduleAbsPath, recStdout, recStderr)
+		annotationsFrom(ctx).add(annotations.all()...)
+		if err != nil {
+			return err
+		}
+
+		entry = &cache.Entry{
+			Command:  spec.command,
+			Module:   moduleAbsPath,
+			Created:  time.Now(),
+			Duration: time.Since(start),
+			Output:   recorder.Output(),
+
+			Annotations: annotationsToCache(annotations.all()),
+		}
+		if len(spec.outputs) > 0 {
+			if entry.Outputs, err = cache.Files(moduleAbsPath, spec.outputs, nil); err != nil {
+				_, _ = fmt.Fprintf(stderr, "Not caching the result: %v\n", err)
+				return nil
+			}
+		}
+		if err := store.Put(key, entry, moduleAbsPath); err != nil {
+			_, _ = fmt.Fprintf(stderr, "Not caching the result: %v\n", err)
+		}
+		return nil
+	}
+}
+
+// annotationsToCache converts annotations to their form in cache entries
+func annotationsToCache(annotations []ciAnnotation) []cache.Annotation {
+	var cached []cache.Annotation
+	for _, a := range annotations {
+		cached = append(cached, cache.Annotation{Level: a.level, File: a.file, Line: a.line, Col: a.col, Title: a.title, Message: a.message})
+	}
+	return cached
+}
+
+// annotationsFromCache converts annotations of a cache entry back
+func annotationsFromCache(cached []cache.Annotation) []ciAnnotation {
+	var annotations []ciAnnotation
+	for _, a := range cached {
+		annotations = append(annotations, ciAnnotation{level: a.Level, file: a.File, line: a.Line, col: a.Col, title: a.Title, message: a.Message})
+	}
+	return annotations
+}
+
+// moduleCacheKey returns the cache key of running spec on the module at dir
+func moduleCacheKey(spec cacheSpec, dir string) (string, error) {
+	mc, err := config.LoadModuleConfig(moduleConfigDir(dir))
+	if err != nil {
+		return "", err
+	}
+	moduleCfg := cfg.ForModule(mc)
+
+	key := cache.NewKey()
+	key.Add("command", spec.command)
+	key.Add("args", spec.args...)
+	key.Add("binary", moduleCfg.Binary, binaryVersion(moduleCfg.Binary))
+	if moduleCfg.Test != nil {
+		key.Add("test", moduleCfg.Test.Engine, moduleCfg.Test.Args)
+		if spec.command == "test" && moduleCfg.Test.Engine == "terratest" {
+			key.Add("go", binaryVersion("go"))
+		}
+	}
+	for _, name := range tasks.HookNames() {
+		for _, hook := range cfg.Hooks[name] {
+			key.Add("hook "+name, hook.Shell, hook.Command)
+		}
+	}
+	for _, name := range cfg.Cache.Env {
+		value, ok := os.LookupEnv(name)
+		key.Add("env "+name, value, fmt.Sprint(ok))
+	}
+
+	if err := key.AddDir("module", dir, spec.inputs, spec.outputs); err != nil {
+		return "", err
+	}
+	// What init installed: the provider lock file, even when inputs leave it
+	// out, and the module versions in .terraform/, which is not hashed
+	for _, rel := range []string{".terraform.lock.hcl", ".terraform/modules/modules.json"} {
+		if err := key.AddFile("installed "+rel, filepath.Join(dir, filepath.FromSlash(rel))); err != nil {
+			return "", err
+		}
+	}
+	for _, dep := range localDependencies(dir) {
+		rel, err := filepath.Rel(dir, dep)
+		if err != nil {
+			rel = dep
+		}
+		if err := key.AddDir("dependency "+filepath.ToSlash(rel), dep, nil, nil); err != nil {
+			return "", err
+		}
+	}
+	return key.Sum(), nil
+}
+
+// localDependencies returns the directories of the local modules dir calls,
+// directly or transitively, except those inside dir
+func localDependencies(dir string) []string {
+	seen := map[string]bool{dir: true}
+	var deps []string
+	queue := []string{dir}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		// Parse errors are reported by the command itself; use what could be parsed
+		calls, _ := terraform.LoadLocalModuleCalls(current)
+		for _, call := range calls {
+			if seen[call] {
+				continue
+			}
+			seen[call] = true
+			queue = append(queue, call)
+			if !strings.HasPrefix(call, dir+string(filepath.Separator)) {
+				deps = append(deps, call)
+			}
+		}
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// binaryVersions caches the output of '<binary> version' per binary
+var binaryVersions sync.Map
+
+// binaryVersion returns the first line of '<binary> version', or the error
+// running it, so that results are not shared across versions
+func binaryVersion(binary string) string {
+	if v, ok := binaryVersions.Load(binary); ok {
+		return v.(string)
+	}
+
+	cmd := exec.Command(binary, "version") //nolint:gosec // binary is the validated terraform/tofu binary or go
+	cmd.Env = append(os.Environ(), "CHECKPOINT_DISABLE=1")
+	out, err := cmd.Output()
+	version, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if err != nil {
+		version = "error: " + err.Error()
+	}
+	binaryVersions.Store(binary, version)
+	return version
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of command and task results",
+	Long: `Manage the cache of command and task results.
+
+With --cache or 'cache.enabled: true' in .motf.yml, validate, test, and task
+skip modules whose files, local dependencies, installed providers and modules,
+command, args, binary version, and cache.env variables are unchanged since a
+successful run, and replay its output instead.
+
+The cache lives in .git/motf/cache/ (or cache.dir in .motf.yml).`,
+	Example: `  motf cache stats   # Show what is cached
+  motf cache clean   # Remove all cached results`,
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove all cached results",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := cacheStore()
+		if err != nil {
+			return err
+		}
+		if store == nil {
+			fmt.Println("No cache directory (not in a git repository and XDG_CACHE_HOME is not set)")
+			return nil
+		}
+		stats, err := store.Stats()
+		if err != nil {
+			return err
+		}
+		if err := store.Clean(); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d cached result(s) (%s) from %s\n", stats.Entries, formatBytes(stats.Bytes), store.Dir)
+		return nil
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show what is cached",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := cacheStore()
+		if err != nil {
+			return err
+		}
+		if store == nil {
+			fmt.Println("No cache directory (not in a git repository and XDG_CACHE_HOME is not set)")
+			return nil
+		}
+		stats, err := store.Stats()
+		if err != nil {
+			return err
+		}
+
+		enabled := cfg.Cache != nil && cfg.Cache.Enabled
+		fmt.Printf("Cache:   %s\n", store.Dir)
+		fmt.Printf("Enabled: %s\n", formatBool(enabled))
+		fmt.Printf("Results: %d (%s)\n", stats.Entries, formatBytes(stats.Bytes))
+		if stats.Entries == 0 {
+			return nil
+		}
+
+		commands := make([]string, 0, len(stats.ByCommand))
+		for command := range stats.ByCommand {
+			commands = append(commands, command)
+		}
+		sort.Strings(commands)
+		for _, command := range commands {
+			fmt.Printf("  %-20s %d\n", command, stats.ByCommand[command])
+		}
+		fmt.Printf("Oldest:  %s\n", stats.Oldest.Local().Format("2006-01-02 15:04:05"))
+		fmt.Printf("Newest:  %s\n", stats.Newest.Local().Format("2006-01-02 15:04:05"))
+		return nil
+	},
+}
+
+// formatBytes formats a size in bytes, e.g. "3.4 MB"
+func formatBytes(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheCleanCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/spf13/cobra"
+)
+
+// runDocsTask runs the docs task on all modules and returns its stderr
+func runDocsTask(t *testing.T) string {
+	t.Helper()
+	taskFlag = "docs"
+	allFlag = true
+	var runErr error
+	var stderr string
+	_ = captureOutput(t, &os.Stdout, func() {
+		stderr = captureOutput(t, &os.Stderr, func() {
+			runErr = taskCmd.RunE(taskCmd, nil)
+		})
+	})
+	if runErr != nil {
+		t.Fatalf("expected the task to succeed, got %v\n%s", runErr, stderr)
+	}
+	return stderr
+}
+
+func TestTaskCmd_Cache(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	createTerraformModule(t, tmpDir, "components/naming")
+	if err := os.WriteFile(filepath.Join(tmpDir, "components", "storage", "main.tf"), []byte("module \"naming\" {\n  source = \"../naming\"\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runs := filepath.Join(t.TempDir(), "runs")
+	withConfig(t, &config.Config{
+		Root:   tmpDir,
+		Binary: "terraform",
+		Cache:  &config.CacheConfig{Enabled: true, Dir: filepath.Join(t.TempDir(), "cache")},
+		Tasks: map[string]*tasks.TaskConfig{
+			"docs": {Command: "echo \"$MOTF_MODULE_NAME\" >> " + runs + " && echo generated > README.md", Outputs: []string{"README.md"}},
+		},
+	})
+	withFakeRunner(t, "#!/bin/sh\n")
+
+	countRuns := func() int {
+		data, _ := os.ReadFile(runs)
+		return strings.Count(string(data), "\n")
+	}
+
+	runDocsTask(t)
+	if countRuns() != 2 {
+		t.Fatalf("expected the task to run on both modules, ran %d times", countRuns())
+	}
+
+	readme := filepath.Join(tmpDir, "components", "storage", "README.md")
+	if err := os.Remove(readme); err != nil {
+		t.Fatal(err)
+	}
+	stderr := runDocsTask(t)
+	if countRuns() != 2 {
+		t.Errorf("expected cached results to be replayed, ran %d times", countRuns())
+	}
+	if !strings.Contains(stderr, "Cache hit: replaying output of the run at") {
+		t.Errorf("expected cache hits to be reported, got: %s", stderr)
+	}
+	if data, err := os.ReadFile(readme); err != nil || string(data) != "generated\n" {
+		t.Errorf("expected the output file to be restored, got %q, %v", data, err)
+	}
+
+	// A change in a local dependency invalidates the modules calling it
+	if err := os.WriteFile(filepath.Join(tmpDir, "components", "naming", "variables.tf"), []byte("variable \"prefix\" {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runDocsTask(t)
+	if countRuns() != 4 {
+		t.Errorf("expected both modules to run again after naming changed, ran %d times", countRuns())
+	}
+
+	cacheFlag = false
+	applyCacheFlag(cacheFlagCmd(t), cfg)
+	runDocsTask(t)
+	if countRuns() != 6 {
+		t.Errorf("expected --cache=false to bypass the cache, ran %d times", countRuns())
+	}
+}
+
+func TestValidate_CacheKeepsAnnotations(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/outdated")
+	withConfig(t, &config.Config{
+		Root:   tmpDir,
+		Binary: "terraform",
+		Cache:  &config.CacheConfig{Enabled: true, Dir: filepath.Join(t.TempDir(), "cache")},
+	})
+	withFakeRunner(t, fakeCIBinary)
+	withWorkingDir(t, tmpDir)
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+	ciProvider = CIGitHub
+	allFlag = true
+
+	want := "::warning file=components/outdated/main.tf,line=2,col=3,title=Deprecated attribute::Deprecated attribute\n"
+	for _, run := range []string{"first run", "cache hit"} {
+		var runErr error
+		var stderr string
+		stdout := captureOutput(t, &os.Stdout, func() {
+			stderr = captureOutput(t, &os.Stderr, func() {
+				runErr = valCmd.RunE(valCmd, nil)
+			})
+		})
+		if runErr != nil {
+			t.Fatalf("%s: expected validate to succeed, got %v", run, runErr)
+		}
+		if !strings.Contains(stdout, want) {
+			t.Errorf("%s: expected annotation %q, got: %s", run, want, stdout)
+		}
+		if run == "cache hit" && !strings.Contains(stderr, "Cache hit") {
+			t.Errorf("expected the second run to be a cache hit, got: %s", stderr)
+		}
+	}
+}
+
+func TestModuleCacheKey_InstalledModules(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Cache: &config.CacheConfig{Enabled: true}})
+
+	dir := filepath.Join(tmpDir, "components", "storage")
+	spec := cacheSpec{command: "validate", inputs: []string{"*.tf"}}
+	key := func() string {
+		t.Helper()
+		k, err := moduleCacheKey(spec, dir)
+		if err != nil {
+			t.Fatalf("moduleCacheKey() returned error: %v", err)
+		}
+		return k
+	}
+
+	base := key()
+	files := map[string]string{
+		".terraform.lock.hcl":             "provider \"registry.terraform.io/hashicorp/azurerm\" {}\n",
+		".terraform/modules/modules.json": `{"Modules":[]}`,
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if next := key(); next == base {
+			t.Errorf("expected %s to change the key", rel)
+		} else {
+			base = next
+		}
+	}
+}
+
+func TestCacheFlag_RequiresSelection(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	withFakeRunner(t, "#!/bin/sh\n")
+
+	pathFlag = filepath.Join(tmpDir, "components", "storage")
+	cacheFlag = true
+	err := valCmd.RunE(valCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--cache requires a module selection") {
+		t.Errorf("expected --cache with --path to be rejected, got %v", err)
+	}
+}
+
+func TestCacheCommands(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	withConfig(t, &config.Config{
+		Root:   tmpDir,
+		Binary: "terraform",
+		Cache:  &config.CacheConfig{Enabled: true, Dir: cacheDir},
+		Tasks:  map[string]*tasks.TaskConfig{"docs": {Command: "echo docs"}},
+	})
+	withFakeRunner(t, "#!/bin/sh\n")
+	runDocsTask(t)
+
+	stdout := captureOutput(t, &os.Stdout, func() {
+		if err := cacheStatsCmd.RunE(cacheStatsCmd, nil); err != nil {
+			t.Errorf("cache stats returned error: %v", err)
+		}
+	})
+	for _, want := range []string{"Cache:   " + cacheDir, "Enabled: Yes", "Results: 1 (", "task docs            1"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in stats, got:\n%s", want, stdout)
+		}
+	}
+
+	stdout = captureOutput(t, &os.Stdout, func() {
+		if err := cacheCleanCmd.RunE(cacheCleanCmd, nil); err != nil {
+			t.Errorf("cache clean returned error: %v", err)
+		}
+	})
+	if !strings.HasPrefix(stdout, "Removed 1 cached result(s)") {
+		t.Errorf("unexpected clean output: %s", stdout)
+	}
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+		t.Errorf("expected the cache directory to be removed, got %v", err)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{0: "0 B", 999: "999 B", 1000: "1.0 kB", 3_400_000: "3.4 MB"}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// cacheFlagCmd returns a command on which --cache was given
+func cacheFlagCmd(t *testing.T) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().BoolVar(&cacheFlag, "cache", false, "")
+	if err := cmd.Flags().Set("cache", "false"); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
@@ -1,6 +1,10 @@
 package cli
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
 
 func TestFmtCmd_HasChangedFlags(t *testing.T) {
 	if fmtCmd.Flags().Lookup("changed") == nil {
@@ -46,3 +50,11 @@ func TestTestCmd_HasChangedFlags(t *testing.T) {
 		t.Fatal("testCmd should have --ref flag")
 	}
 }
+
+func TestRunCommands_HaveDependentsFlag(t *testing.T) {
+	for _, cmd := range []*cobra.Command{fmtCmd, valCmd, initCmd, planCmd, testCmd, taskCmd, listCmd} {
+		if cmd.Flags().Lookup("dependents") == nil {
+			t.Errorf("%s should have --dependents flag", cmd.Name())
+		}
+	}
+}
@@ -2,65 +2,19 @@ package cli
 
 import (
 	"fmt"
-	"io"
 	"path/filepath"
 	"sort"
 	"strings"
 
-	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
 	"github.com/TechnicallyJoe/terraform-motf/internal/git"
 )
 
-// runOnChangedModules detects changed modules and runs fn on each module.
-// When parallelFlag is set, modules are processed concurrently.
-// parallelFlag is a package-level CLI flag set by command-line arguments.
-// It is a no-op (success) when no changed modules are found.
-//
-// The function signature for fn receives stdout/stderr writers to support
-// prefixed output in parallel mode.
-func runOnChangedModules(fn func(mod ModuleInfo, stdout, stderr io.Writer) error) error {
-	if pathFlag != "" {
-		return fmt.Errorf("--changed cannot be used with --path")
-	}
-	if exampleFlag != "" {
-		return fmt.Errorf("--changed cannot be used with --example")
-	}
-
-	modules, err := detectChangedModules(refFlag)
-	if err != nil {
-		return err
-	}
-	if len(modules) == 0 {
-		fmt.Println("No changed modules found")
-		return nil
-	}
-
-	var parallelismCfg *config.ParallelismConfig
-	if cfg != nil {
-		parallelismCfg = cfg.Parallelism
-	}
-
-	return RunOnModulesParallel(modules, parallelismCfg, fn)
-}
-
-// runOnChangedModulesWithPath is a convenience wrapper for commands that need
-// the module's absolute path. It wraps fn to provide the path from ModuleInfo.
-func runOnChangedModulesWithPath(fn func(moduleAbsPath string, stdout, stderr io.Writer) error) error {
-	basePath, err := getBasePath()
-	if err != nil {
-		return err
-	}
-
-	return runOnChangedModules(func(mod ModuleInfo, stdout, stderr io.Writer) error {
-		moduleAbsPath := filepath.Join(basePath, mod.Path)
-		return fn(moduleAbsPath, stdout, stderr)
-	})
-}
-
 // detectChangedModules returns modules that have changed compared to baseRef.
 // If baseRef is empty, it auto-detects the default branch by checking origin/HEAD,
 // then falling back to origin/main or origin/master.
+// When dependentsFlag is set, modules that transitively depend on a changed
+// module through local module calls are included as well.
 func detectChangedModules(baseRef string) ([]ModuleInfo, error) {
 	// Get the git repository root
 	repoRoot, err := git.GetRepoRoot()
@@ -101,7 +55,7 @@ func detectChangedModules(baseRef string) ([]ModuleInfo, error) {
 
 	// Adjust module dirs to be relative to repo root
 	var adjustedModuleDirs []string
-	for _, dir := range ModuleDirs {
+	for _, dir := range moduleDirs() {
 		if relBasePath != "" && relBasePath != "." {
 			adjustedModuleDirs = append(adjustedModuleDirs, filepath.ToSlash(filepath.Join(relBasePath, dir)))
 		} else {
@@ -115,15 +69,25 @@ func detectChangedModules(baseRef string) ([]ModuleInfo, error) {
 		return nil, nil
 	}
 
+	index, err := moduleIndex(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to index modules: %w", err)
+	}
+
 	// Convert paths to module info with validation
-	modules := resolveChangedModules(basePath, repoRoot, changedModulePaths)
+	modules := resolveChangedModules(basePath, repoRoot, changedModulePaths, index)
+
+	if dependentsFlag {
+		return includeDependents(basePath, modules)
+	}
 
 	return modules, nil
 }
 
-// resolveChangedModules validates that changed paths are actual modules with .tf files
-// and returns module info for each
-func resolveChangedModules(basePath, repoRoot string, changedPaths []string) []ModuleInfo {
+// resolveChangedModules maps changed paths to the indexed modules containing them
+// and returns module info for each. Changes inside directories skipped during
+// discovery count towards the module containing them, or are ignored if there is none.
+func resolveChangedModules(basePath, repoRoot string, changedPaths []string, index *finder.Index) []ModuleInfo {
 	var modules []ModuleInfo
 	seen := make(map[string]bool)
 
@@ -131,11 +95,11 @@ func resolveChangedModules(basePath, repoRoot string, changedPaths []string) []M
 		// Convert the path (relative to repo root) to absolute
 		absPath := filepath.Join(repoRoot, modulePath)
 
-		// Check if this directory contains terraform files
-		if !finder.HasTerraformFiles(absPath) {
+		// Check if this directory is a module
+		if !index.Has(absPath) {
 			// The changed file might be in a subdirectory (like tests/ or examples/)
 			// Walk up to find the actual module
-			absPath = findParentModule(absPath, basePath)
+			absPath = findParentModule(index, absPath, basePath)
 			if absPath == "" {
 				continue
 			}
@@ -177,11 +141,11 @@ func resolveChangedModules(basePath, repoRoot string, changedPaths []string) []M
 	return modules
 }
 
-// findParentModule walks up the directory tree to find a parent that contains .tf files
-func findParentModule(startPath, stopPath string) string {
+// findParentModule walks up the directory tree to find a parent that is an indexed module
+func findParentModule(index *finder.Index, startPath, stopPath string) string {
 	current := startPath
 	for {
-		if finder.HasTerraformFiles(current) {
+		if index.Has(current) {
 			return current
 		}
 
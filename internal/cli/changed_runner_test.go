@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
 )
 
 func TestFindParentModule(t *testing.T) {
@@ -29,6 +30,11 @@ func TestFindParentModule(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	index, err := finder.DefaultRules().BuildIndex([]string{filepath.Join(tmpDir, "components")})
+	if err != nil {
+		t.Fatalf("BuildIndex returned error: %v", err)
+	}
+
 	tests := []struct {
 		name      string
 		startPath string
@@ -57,7 +63,7 @@ func TestFindParentModule(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := findParentModule(tt.startPath, tt.stopPath)
+			got := findParentModule(index, tt.startPath, tt.stopPath)
 			if got != tt.want {
 				t.Errorf("findParentModule() = %v, want %v", got, tt.want)
 			}
@@ -86,6 +92,11 @@ func TestResolveChangedModules(t *testing.T) {
 	// Set up config
 	withConfig(t, &config.Config{Root: "", Binary: "terraform"})
 
+	index, err := moduleIndex(tmpDir)
+	if err != nil {
+		t.Fatalf("moduleIndex returned error: %v", err)
+	}
+
 	tests := []struct {
 		name         string
 		changedPaths []string
@@ -110,7 +121,7 @@ func TestResolveChangedModules(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			modules := resolveChangedModules(tmpDir, tmpDir, tt.changedPaths)
+			modules := resolveChangedModules(tmpDir, tmpDir, tt.changedPaths, index)
 
 			var gotNames []string
 			for _, m := range modules {
@@ -130,3 +141,30 @@ func TestResolveChangedModules(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveChangedModules_DiscoveryRules(t *testing.T) {
+	tmpDir := setupDiscoveryTree(t)
+	createTerraformModule(t, tmpDir, "components/network/examples/basic")
+
+	index, err := moduleIndex(tmpDir)
+	if err != nil {
+		t.Fatalf("moduleIndex returned error: %v", err)
+	}
+
+	modules := resolveChangedModules(tmpDir, tmpDir, []string{
+		"components/network/examples/basic",
+		"components/network/modules/subnet",
+		"components/vendor/acme",
+		"components/legacy/old-app",
+	}, index)
+
+	// The example counts towards its module; excluded modules are dropped
+	var got []string
+	for _, m := range modules {
+		got = append(got, m.Path)
+	}
+	want := []string{"components/network", "components/network/modules/subnet"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("resolveChangedModules() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+// CI systems supported by --ci
+const (
+	CIGitHub = "github"
+	CIGitLab = "gitlab"
+	CINone   = "none"
+)
+
+// ciFlag selects the CI system to format output for; empty means auto-detect
+var ciFlag string
+
+// ciProvider is the CI system output is formatted for, resolved from --ci and
+// the environment in PersistentPreRunE. Empty when not running in CI mode.
+var ciProvider string
+
+// resolveCIProvider returns the CI system for the --ci value, detecting it from
+// GITHUB_ACTIONS or GITLAB_CI when flag is empty
+func resolveCIProvider(flag string) (string, error) {
+	switch flag {
+	case CIGitHub, CIGitLab:
+		return flag, nil
+	case CINone:
+		return "", nil
+	case "":
+		if os.Getenv("GITHUB_ACTIONS") == "true" {
+			return CIGitHub, nil
+		}
+		if os.Getenv("GITLAB_CI") == "true" {
+			return CIGitLab, nil
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("invalid --ci '%s': must be '%s', '%s', or '%s'", flag, CIGitHub, CIGitLab, CINone)
+}
+
+// ciGroup returns the lines opening and closing a collapsible log section for
+// mod in the given CI system, or empty strings if it has none. GitLab sections
+// of failed modules start expanded.
+func ciGroup(provider string, mod ModuleInfo, status string) (start, end string) {
+	title := mod.Name + " (" + mod.Path + ")"
+	switch provider {
+	case CIGitHub:
+		return "::group::" + title + "\n", "::endgroup::\n"
+	case CIGitLab:
+		id := gitlabSectionID(mod.Path)
+		now := time.Now().Unix()
+		collapsed := status != StatusFailed
+		start = fmt.Sprintf("\033[0Ksection_start:%d:%s[collapsed=%t]\r\033[0K%s\n", now, id, collapsed, title)
+		end = fmt.Sprintf("\033[0Ksection_end:%d:%s\r\033[0K\n", now, id)
+		return start, end
+	}
+	return "", ""
+}
+
+// gitlabSectionPattern matches characters not allowed in GitLab section names
+var gitlabSectionPattern = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// gitlabSectionID returns a GitLab section name for a module path
+func gitlabSectionID(path string) string {
+	return "motf_" + gitlabSectionPattern.ReplaceAllString(filepath.ToSlash(path), "_")
+}
+
+// ciAnnotation is an error or warning attached to a file in the CI system's UI
+type ciAnnotation struct {
+	level   string // "error" or "warning"
+	file    string // relative to the repository root
+	line    int
+	col     int
+	title   string
+	message string
+}
+
+// annotationList collects the annotations of all modules of a run. They are
+// printed after the run, because workflow commands must start at the beginning
+// of a line and module output is prefixed. It is safe for concurrent use.
+type annotationList struct {
+	mu    sync.Mutex
+	items []ciAnnotation
+}
+
+// annotationsKey is the context key of the annotationList of a run
+type annotationsKey struct{}
+
+// withAnnotations returns a copy of ctx that collects annotations in the
+// returned list
+func withAnnotations(ctx context.Context) (context.Context, *annotationList) {
+	l := &annotationList{}
+	return context.WithValue(ctx, annotationsKey{}, l), l
+}
+
+// annotationsFrom returns the annotationList of ctx, or nil
+func annotationsFrom(ctx context.Context) *annotationList {
+	l, _ := ctx.Value(annotationsKey{}).(*annotationList)
+	return l
+}
+
+// add appends annotations to the list; it does nothing on a nil list
+func (l *annotationList) add(annotations ...ciAnnotation) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = append(l.items, annotations...)
+}
+
+// all returns the annotations collected so far
+func (l *annotationList) all() []ciAnnotation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]ciAnnotation(nil), l.items...)
+}
+
+// printGitHubAnnotations writes annotations as GitHub Actions workflow commands
+func printGitHubAnnotations(w io.Writer, annotations []ciAnnotation) {
+	for _, a := range annotations {
+		props := []string{"file=" + escapeGitHubProperty(a.file)}
+		if a.line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", a.line))
+		}
+		if a.col > 0 {
+			props = append(props, fmt.Sprintf("col=%d", a.col))
+		}
+		if a.title != "" {
+			props = append(props, "title="+escapeGitHubProperty(a.title))
+		}
+		_, _ = fmt.Fprintf(w, "::%s %s::%s\n", a.level, strings.Join(props, ","), escapeGitHubData(a.message))
+	}
+}
+
+// escapeGitHubData escapes the message of a workflow command
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// annotationRoot returns the directory annotation files are relative to: the
+// git root, or the working directory outside a repository
+func annotationRoot(gitRoot string) string {
+	if gitRoot != "" {
+		return gitRoot
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return wd
+}
+
+// annotationFile returns the path of file in the module at moduleAbsPath,
+// relative to root
+func annotationFile(root, moduleAbsPath, file string) string {
+	if root == "" {
+		return filepath.ToSlash(file)
+	}
+	rel, err := filepath.Rel(root, filepath.Join(moduleAbsPath, file))
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// validateForCI runs validate -json in a module, writes its diagnostics to
+// stderr in a readable form, and records them as annotations of the run of
+// ctx, with files relative to root
+func validateForCI(ctx context.Context, tfRunner *terraform.Runner, root, moduleAbsPath string, stdout, stderr io.Writer) error {
+	annotations := annotationsFrom(ctx)
+	result, err := tfRunner.RunValidateJSON(moduleAbsPath, stdout, stderr, argsFlag...)
+	if result == nil {
+		return err
+	}
+
+	for _, d := range result.Diagnostics {
+		label := "Error"
+		level := "error"
+		if d.Severity == "warning" {
+			label = "Warning"
+			level = "warning"
+		}
+		_, _ = fmt.Fprintf(stderr, "%s: %s\n", label, d.Summary)
+
+		annotation := ciAnnotation{level: level, title: d.Summary, message: d.Detail}
+		if annotation.message == "" {
+			annotation.message = d.Summary
+		}
+		if d.Range != nil {
+			_, _ = fmt.Fprintf(stderr, "  on %s line %d\n", d.Range.Filename, d.Range.Start.Line)
+			annotation.file = annotationFile(root, moduleAbsPath, d.Range.Filename)
+			annotation.line = d.Range.Start.Line
+			annotation.col = d.Range.Start.Column
+		} else {
+			annotation.file = annotationFile(root, moduleAbsPath, ".")
+		}
+		if d.Detail != "" {
+			_, _ = fmt.Fprintf(stderr, "  %s\n", strings.ReplaceAll(d.Detail, "\n", "\n  "))
+		}
+		annotations.add(annotation)
+	}
+
+	if err == nil && result.Valid {
+		_, _ = fmt.Fprintln(stdout, "Success! The configuration is valid.")
+	}
+	return err
+}
+
+// isFmtCheck reports whether args make fmt only check formatting
+func isFmtCheck(args []string) bool {
+	return slices.Contains(args, "-check") || slices.Contains(args, "--check")
+}
+
+// fmtCheckForCI runs fmt -check -diff in a module and records every
+// unformatted hunk as an annotation of the run of ctx, with files relative to
+// root. The diff is still printed to stdout.
+func fmtCheckForCI(ctx context.Context, tfRunner *terraform.Runner, root, moduleAbsPath string, stdout, stderr io.Writer) error {
+	annotations := annotationsFrom(ctx)
+	args := argsFlag
+	if !slices.Contains(args, "-diff") && !slices.Contains(args, "--diff") {
+		args = append(slices.Clone(args), "-diff")
+	}
+
+	var diff strings.Builder
+	err := tfRunner.RunFmtWithOutput(moduleAbsPath, io.MultiWriter(stdout, &diff), stderr, args...)
+
+	for _, d := range terraform.ParseFmtDiff([]byte(diff.String())) {
+		file := annotationFile(root, moduleAbsPath, d.File)
+		lines := d.Lines
+		if len(lines) == 0 {
+			lines = []int{0}
+		}
+		for _, line := range lines {
+			annotations.add(ciAnnotation{
+				level:   "error",
+				file:    file,
+				line:    line,
+				title:   "File is not formatted",
+				message: fmt.Sprintf("Run '%s fmt' to fix the formatting of %s", tfRunner.Binary(), d.File),
+			})
+		}
+	}
+	return err
+}
+
+// writeGitHubStepSummary appends a Markdown summary of the run to the file in
+// $GITHUB_STEP_SUMMARY, if set
+func writeGitHubStepSummary(command string, results []moduleResult, wall time.Duration) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // path is provided by GitHub Actions
+	if err != nil {
+		return fmt.Errorf("failed to open job summary: %w", err)
+	}
+	printMarkdownSummary(f, command, results, wall)
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write job summary: %w", err)
+	}
+	return nil
+}
+
+// printMarkdownSummary writes the run summary as a Markdown table
+func printMarkdownSummary(w io.Writer, command string, results []moduleResult, wall time.Duration) {
+	_, _ = fmt.Fprintf(w, "### motf %s\n\n", command)
+	_, _ = fmt.Fprintln(w, "| Module | Path | Status | Duration | Error |")
+	_, _ = fmt.Fprintln(w, "|--------|------|--------|----------|-------|")
+	for _, r := range sortedForSummary(results) {
+		duration := "-"
+		if r.started() {
+			duration = formatSeconds(r.Duration)
+		}
+		errLine := strings.ReplaceAll(firstErrorLine(r), "|", "\\|")
+		_, _ = fmt.Fprintf(w, "| %s | `%s` | %s | %s | %s |\n", r.Module.Name, r.Module.Path, summaryStatusLabel(r.Status), duration, errLine)
+	}
+	_, _ = fmt.Fprintf(w, "\n%s\n\n", summaryTotals(results, wall))
+}
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// fakeCIBinary fails validate -json with a diagnostic in "broken", passes it
+// with a warning in "outdated", reports an unformatted file for fmt -check -diff
+// in "messy", and succeeds otherwise
+const fakeCIBinary = `#!/bin/sh
+case "$1 $(basename "$PWD")" in
+"validate broken")
+  echo '{"valid":false,"error_count":1,"warning_count":0,"diagnostics":[{"severity":"error","summary":"Unsupported argument","detail":"An argument named \"foo\" is not expected here.","range":{"filename":"main.tf","start":{"line":3,"column":5}}}]}'
+  exit 1 ;;
+"validate outdated")
+  echo '{"valid":true,"error_count":0,"warning_count":1,"diagnostics":[{"severity":"warning","summary":"Deprecated attribute","detail":"","range":{"filename":"main.tf","start":{"line":2,"column":3}}}]}' ;;
+"validate "*)
+  echo '{"valid":true,"error_count":0,"warning_count":0,"diagnostics":[]}' ;;
+"fmt messy")
+  printf 'main.tf\n--- old/main.tf\n+++ new/main.tf\n@@ -1,2 +1,2 @@\n-a=1\n+a = 1\n'
+  exit 3 ;;
+esac
+exit 0
+`
+
+func TestResolveCIProvider(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("GITLAB_CI", "")
+
+	tests := []struct {
+		name   string
+		flag   string
+		env    string
+		want   string
+		errMsg string
+	}{
+		{"not in CI", "", "", "", ""},
+		{"GitHub Actions detected", "", "GITHUB_ACTIONS", CIGitHub, ""},
+		{"GitLab CI detected", "", "GITLAB_CI", CIGitLab, ""},
+		{"explicit gitlab", CIGitLab, "", CIGitLab, ""},
+		{"none disables detection", CINone, "GITHUB_ACTIONS", "", ""},
+		{"invalid", "jenkins", "", "", "invalid --ci 'jenkins'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv(tt.env, "true")
+			}
+			got, err := resolveCIProvider(tt.flag)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveCIProvider(%q) = %q, %v, want %q", tt.flag, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCIGroup(t *testing.T) {
+	mod := ModuleInfo{Name: "storage-account", Path: "components/azurerm/storage-account"}
+
+	start, end := ciGroup(CIGitHub, mod, StatusPassed)
+	if start != "::group::storage-account (components/azurerm/storage-account)\n" || end != "::endgroup::\n" {
+		t.Errorf("unexpected GitHub group: %q %q", start, end)
+	}
+
+	start, end = ciGroup(CIGitLab, mod, StatusPassed)
+	if !strings.Contains(start, ":motf_components_azurerm_storage-account[collapsed=true]\r") || !strings.Contains(end, "section_end:") {
+		t.Errorf("unexpected GitLab section: %q %q", start, end)
+	}
+	if start, _ = ciGroup(CIGitLab, mod, StatusFailed); !strings.Contains(start, "[collapsed=false]") {
+		t.Errorf("expected failed module section to be expanded, got %q", start)
+	}
+
+	if start, end = ciGroup("", mod, StatusPassed); start != "" || end != "" {
+		t.Errorf("expected no group outside CI, got %q %q", start, end)
+	}
+}
+
+func TestPrintGitHubAnnotations(t *testing.T) {
+	var buf bytes.Buffer
+	printGitHubAnnotations(&buf, []ciAnnotation{
+		{level: "error", file: "components/a/main.tf", line: 3, col: 5, title: "Bad: value, here", message: "line one\nline two 100%"},
+		{level: "warning", file: "components/a", message: "no location"},
+	})
+
+	want := "::error file=components/a/main.tf,line=3,col=5,title=Bad%3A value%2C here::line one%0Aline two 100%25\n" +
+		"::warning file=components/a::no location\n"
+	if buf.String() != want {
+		t.Errorf("printGitHubAnnotations() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestRunWithOutput_CIGroups(t *testing.T) {
+	var out bytes.Buffer
+	mod := ModuleInfo{Name: "a", Path: "components/a"}
+	opts := runOptions{out: &out, errOut: &out, mode: OutputModeGrouped, ci: CIGitHub}
+
+	runWithOutput(mod, 1, 0, opts, &sync.Mutex{}, func(writers *prefixedWriterPair) moduleResult {
+		_, _ = writers.stdout.Write([]byte("hello\n"))
+		_ = writers.Flush()
+		return moduleResult{Module: mod, Status: StatusPassed}
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || lines[0] != "::group::a (components/a)" || !strings.Contains(lines[1], "hello") || lines[2] != "::endgroup::" {
+		t.Errorf("expected output wrapped in a group, got: %q", out.String())
+	}
+}
+
+func TestAnnotationFile(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "repo")
+	module := filepath.Join(root, "components", "a")
+	if got := annotationFile(root, module, "main.tf"); got != "components/a/main.tf" {
+		t.Errorf("annotationFile() = %q, want components/a/main.tf", got)
+	}
+	if got := annotationFile("", module, "main.tf"); got != "main.tf" {
+		t.Errorf("annotationFile() without a root = %q, want main.tf", got)
+	}
+}
+
+func TestWithAnnotations(t *testing.T) {
+	// Outside a run, annotations are dropped instead of leaking into the next run
+	annotationsFrom(context.Background()).add(ciAnnotation{level: "error", file: "main.tf"})
+
+	ctx, annotations := withAnnotations(context.Background())
+	annotationsFrom(ctx).add(ciAnnotation{level: "error", file: "main.tf"})
+	if got := annotations.all(); len(got) != 1 || got[0].file != "main.tf" {
+		t.Errorf("annotations = %v, want the one added to the run", got)
+	}
+
+	_, next := withAnnotations(context.Background())
+	if got := next.all(); len(got) != 0 {
+		t.Errorf("annotations of a new run = %v, want none", got)
+	}
+}
+
+func TestValidate_GitHubAnnotations(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/broken")
+	createTerraformModule(t, tmpDir, "components/fine")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	withFakeRunner(t, fakeCIBinary)
+	withWorkingDir(t, tmpDir)
+
+	stepSummary := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", stepSummary)
+	ciProvider = CIGitHub
+	commandName = "val"
+	allFlag = true
+
+	var runErr error
+	stdout := captureOutput(t, &os.Stdout, func() {
+		_ = captureOutput(t, &os.Stderr, func() {
+			runErr = valCmd.RunE(valCmd, nil)
+		})
+	})
+	if runErr == nil {
+		t.Fatal("expected validate to fail for broken")
+	}
+
+	want := "::error file=components/broken/main.tf,line=3,col=5,title=Unsupported argument::An argument named \"foo\" is not expected here.\n"
+	if !strings.Contains(stdout, want) {
+		t.Errorf("expected annotation %q in output, got: %s", want, stdout)
+	}
+	if !strings.Contains(stdout, "::group::broken (components/broken)") || !strings.Contains(stdout, "::group::fine (components/fine)") {
+		t.Errorf("expected a group per module, got: %s", stdout)
+	}
+
+	summary, err := os.ReadFile(stepSummary)
+	if err != nil {
+		t.Fatalf("expected job summary: %v", err)
+	}
+	for _, want := range []string{"### motf val", "| broken | `components/broken` | failed |", "| fine | `components/fine` | ok |", "2 modules: 1 ok, 1 failed"} {
+		if !strings.Contains(string(summary), want) {
+			t.Errorf("expected %q in job summary, got:\n%s", want, summary)
+		}
+	}
+}
+
+func TestFmtCheck_GitHubAnnotations(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/messy")
+	createTerraformModule(t, tmpDir, "components/tidy")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	withFakeRunner(t, fakeCIBinary)
+	withWorkingDir(t, tmpDir)
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+
+	ciProvider = CIGitHub
+	argsFlag = []string{"-check"}
+	allFlag = true
+
+	var runErr error
+	stdout := captureOutput(t, &os.Stdout, func() {
+		_ = captureOutput(t, &os.Stderr, func() {
+			runErr = fmtCmd.RunE(fmtCmd, nil)
+		})
+	})
+	if runErr == nil {
+		t.Fatal("expected fmt -check to fail for messy")
+	}
+	if !strings.Contains(stdout, "fmt -check -diff") {
+		t.Errorf("expected -diff to be added, got: %s", stdout)
+	}
+	if !strings.Contains(stdout, "::error file=components/messy/main.tf,line=1,title=File is not formatted::") {
+		t.Errorf("expected formatting annotation, got: %s", stdout)
+	}
+	if strings.Contains(stdout, "tidy/main.tf") {
+		t.Errorf("expected no annotation for a formatted module, got: %s", stdout)
+	}
+}
+
+func TestPrintMarkdownSummary(t *testing.T) {
+	var buf bytes.Buffer
+	printMarkdownSummary(&buf, "plan", []moduleResult{
+		{Module: ModuleInfo{Name: "a", Path: "components/a"}, Status: StatusPassed, Duration: time.Second},
+		{Module: ModuleInfo{Name: "b", Path: "components/b"}, Status: StatusFailed, Duration: time.Second, StderrTail: "Error: a | b", Err: io.EOF},
+	}, 2*time.Second)
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "### motf plan\n") {
+		t.Errorf("expected heading, got:\n%s", out)
+	}
+	// Failed modules come first and pipes are escaped
+	if !strings.Contains(out, "-|\n| b | `components/b` | failed | 1.0s | Error: a \\| b |\n| a |") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
 	"github.com/spf13/cobra"
 )
 
@@ -29,6 +30,30 @@ var configCmd = &cobra.Command{
 		fmt.Println("\nParallelism:")
 		fmt.Printf("  max_jobs: %d\n", cfg.Parallelism.GetMaxJobs())
 
+		fmt.Println("\nModule kinds:")
+		for _, kind := range moduleKinds() {
+			fmt.Printf("  - %-15s %s (order %d)\n", kind.Type, kind.Dir, kind.Order)
+		}
+
+		if cfg.Discovery != nil && (len(cfg.Discovery.Exclude) > 0 || len(cfg.Discovery.Include) > 0) {
+			fmt.Println("\nDiscovery:")
+			for _, p := range cfg.Discovery.Exclude {
+				fmt.Printf("  exclude: %s\n", p)
+			}
+			for _, p := range cfg.Discovery.Include {
+				fmt.Printf("  include: %s\n", p)
+			}
+		}
+
+		if len(cfg.Hooks) > 0 {
+			fmt.Println("\nHooks:")
+			for _, name := range tasks.HookNames() {
+				for _, hook := range cfg.Hooks[name] {
+					fmt.Printf("  %-15s %s\n", name+":", hook.Command)
+				}
+			}
+		}
+
 		if len(cfg.Tasks) > 0 {
 			fmt.Println("\nTasks:")
 
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/graph"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+// buildModuleGraph parses the local module calls of every module and returns a
+// dependency graph keyed by ModuleInfo.Path. An edge from A to B means module A
+// sources module B. Calls into a submodule (e.g. storage-account/modules/x) are
+// attributed to the module that owns it. Calls to directories that are not part
+// of modules are ignored.
+//
+// Modules with syntax errors contribute whatever calls could be parsed; the
+// error itself is left for the terraform command to report.
+func buildModuleGraph(basePath string, modules []ModuleInfo) *graph.Graph {
+	g := graph.New()
+
+	byAbsPath := make(map[string]string, len(modules))
+	for _, mod := range modules {
+		byAbsPath[filepath.Join(basePath, mod.Path)] = mod.Path
+		g.AddNode(mod.Path)
+	}
+
+	for _, mod := range modules {
+		absPath := filepath.Join(basePath, mod.Path)
+		calls, _ := terraform.LoadLocalModuleCalls(absPath)
+
+		for _, target := range calls {
+			if dep := owningModule(target, basePath, byAbsPath); dep != "" {
+				g.AddEdge(mod.Path, dep)
+			}
+		}
+	}
+
+	return g
+}
+
+// owningModule walks up from target until it finds a known module directory.
+// Returns the module's relative path, or empty string if target is not inside a module.
+func owningModule(target, basePath string, byAbsPath map[string]string) string {
+	current := target
+	for {
+		if relPath, ok := byAbsPath[current]; ok {
+			return relPath
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current || parent == basePath || !strings.HasPrefix(parent, basePath) {
+			return ""
+		}
+		current = parent
+	}
+}
+
+// includeDependents returns changed plus every module that transitively depends
+// on one of them through local module calls. The result is sorted by path.
+func includeDependents(basePath string, changed []ModuleInfo) ([]ModuleInfo, error) {
+	if len(changed) == 0 {
+		return changed, nil
+	}
+
+	allModules, err := collectModules(basePath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	g := buildModuleGraph(basePath, allModules)
+
+	result := append([]ModuleInfo(nil), changed...)
+	seen := make(map[string]bool, len(changed))
+	ids := make([]string, 0, len(changed))
+	for _, mod := range changed {
+		seen[mod.Path] = true
+		ids = append(ids, mod.Path)
+	}
+
+	dependents := make(map[string]bool)
+	for _, id := range g.TransitiveDependents(ids...) {
+		dependents[id] = true
+	}
+
+	for _, mod := range allModules {
+		if dependents[mod.Path] && !seen[mod.Path] {
+			seen[mod.Path] = true
+			result = append(result, mod)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result, nil
+}
+
+// moduleDependencies returns, for each module, the paths of the other given
+// modules it transitively depends on. Dependencies are followed through
+// modules outside the given set, so a project still waits for a component
+// it reaches through an unselected base.
+func moduleDependencies(basePath string, modules []ModuleInfo) (map[string][]string, error) {
+	allModules, err := collectModules(basePath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	g := buildModuleGraph(basePath, allModules)
+
+	inSet := make(map[string]bool, len(modules))
+	for _, mod := range modules {
+		inSet[mod.Path] = true
+	}
+
+	deps := make(map[string][]string, len(modules))
+	for _, mod := range modules {
+		for _, dep := range g.TransitiveDependencies(mod.Path) {
+			if inSet[dep] && dep != mod.Path {
+				deps[mod.Path] = append(deps[mod.Path], dep)
+			}
+		}
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// writeModuleCall writes a module block with a local source into the module at modulePath.
+func writeModuleCall(t *testing.T, modulePath, name, source string) {
+	t.Helper()
+	content := "module \"" + name + "\" {\n  source = \"" + source + "\"\n}\n"
+	if err := os.WriteFile(filepath.Join(modulePath, name+".tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write module call: %v", err)
+	}
+}
+
+// setupDependencyTree creates project -> base -> component, plus an unrelated component.
+func setupDependencyTree(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	createTerraformModule(t, tmpDir, "components/storage")
+	createTerraformModule(t, tmpDir, "components/unrelated")
+	createTerraformModule(t, tmpDir, "components/storage/modules/container")
+	base := createTerraformModule(t, tmpDir, "bases/app")
+	project := createTerraformModule(t, tmpDir, "projects/prod")
+
+	writeModuleCall(t, base, "storage", "../../components/storage")
+	writeModuleCall(t, project, "app", "../../bases/app")
+
+	return tmpDir
+}
+
+func TestBuildModuleGraph(t *testing.T) {
+	tmpDir := setupDependencyTree(t)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	modules, err := collectModules(tmpDir, "")
+	if err != nil {
+		t.Fatalf("collectModules returned error: %v", err)
+	}
+
+	g := buildModuleGraph(tmpDir, modules)
+
+	basePath := filepath.Join("bases", "app")
+	projectPath := filepath.Join("projects", "prod")
+	storagePath := filepath.Join("components", "storage")
+
+	if got, want := g.Dependencies(projectPath), []string{basePath}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies(%s) = %v, want %v", projectPath, got, want)
+	}
+	if got, want := g.Dependencies(basePath), []string{storagePath}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies(%s) = %v, want %v", basePath, got, want)
+	}
+}
+
+func TestBuildModuleGraph_SubmoduleCallAttributedToOwner(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	createTerraformModule(t, tmpDir, "components/storage/modules/container")
+	base := createTerraformModule(t, tmpDir, "bases/app")
+	writeModuleCall(t, base, "container", "../../components/storage/modules/container")
+
+	modules := []ModuleInfo{
+		{Name: "storage", Type: TypeComponent, Path: filepath.Join("components", "storage")},
+		{Name: "app", Type: TypeBase, Path: filepath.Join("bases", "app")},
+	}
+
+	g := buildModuleGraph(tmpDir, modules)
+
+	want := []string{filepath.Join("components", "storage")}
+	if got := g.Dependencies(filepath.Join("bases", "app")); !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies(bases/app) = %v, want %v", got, want)
+	}
+}
+
+func TestBuildModuleGraph_ToleratesSyntaxErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	base := createTerraformModule(t, tmpDir, "bases/app")
+	writeModuleCall(t, base, "storage", "../../components/storage")
+	if err := os.WriteFile(filepath.Join(base, "broken.tf"), []byte("output \"x\" {"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modules := []ModuleInfo{
+		{Name: "storage", Type: TypeComponent, Path: filepath.Join("components", "storage")},
+		{Name: "app", Type: TypeBase, Path: filepath.Join("bases", "app")},
+	}
+
+	g := buildModuleGraph(tmpDir, modules)
+
+	want := []string{filepath.Join("components", "storage")}
+	if got := g.Dependencies(filepath.Join("bases", "app")); !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies(bases/app) = %v, want %v", got, want)
+	}
+}
+
+func TestIncludeDependents(t *testing.T) {
+	tmpDir := setupDependencyTree(t)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	changed := []ModuleInfo{
+		{Name: "storage", Type: TypeComponent, Path: filepath.Join("components", "storage")},
+	}
+
+	modules, err := includeDependents(tmpDir, changed)
+	if err != nil {
+		t.Fatalf("includeDependents returned error: %v", err)
+	}
+
+	var gotNames []string
+	for _, m := range modules {
+		gotNames = append(gotNames, m.Name)
+	}
+
+	// Sorted by path: bases/app, components/storage, projects/prod
+	want := []string{"app", "storage", "prod"}
+	if !reflect.DeepEqual(gotNames, want) {
+		t.Errorf("includeDependents() names = %v, want %v", gotNames, want)
+	}
+}
+
+func TestIncludeDependents_Empty(t *testing.T) {
+	modules, err := includeDependents(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("includeDependents returned error: %v", err)
+	}
+	if len(modules) != 0 {
+		t.Errorf("expected no modules, got %v", modules)
+	}
+}
+
+func TestModuleDependencies_FollowsUnselectedModules(t *testing.T) {
+	tmpDir := setupDependencyTree(t)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	storagePath := filepath.Join("components", "storage")
+	projectPath := filepath.Join("projects", "prod")
+
+	// bases/app is not selected, but prod still depends on storage through it
+	modules := []ModuleInfo{
+		{Name: "prod", Type: TypeProject, Path: projectPath},
+		{Name: "storage", Type: TypeComponent, Path: storagePath},
+	}
+
+	deps, err := moduleDependencies(tmpDir, modules)
+	if err != nil {
+		t.Fatalf("moduleDependencies returned error: %v", err)
+	}
+
+	if got, want := deps[projectPath], []string{storagePath}; !reflect.DeepEqual(got, want) {
+		t.Errorf("deps[%s] = %v, want %v", projectPath, got, want)
+	}
+	if len(deps[storagePath]) != 0 {
+		t.Errorf("storage should have no dependencies, got %v", deps[storagePath])
+	}
+}
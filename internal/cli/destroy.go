@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/spf13/cobra"
+)
+
+// destroyConfirmFlag lists project module names the user confirmed for destruction
+var destroyConfirmFlag []string
+
+// destroyCmd represents the destroy command
+var destroyCmd = &cobra.Command{
+	Use:   "destroy [module-name...]",
+	Short: "Run terraform/tofu destroy on a module",
+	Long: `Run terraform/tofu destroy on a module: a component, base, or project by default,
+or a module of any kind configured in module_kinds.
+
+Use the --example/-e flag to destroy a specific example instead of the module itself,
+e.g. to clean up after a test run.
+
+Destroying a single module prompts for approval unless --yes is given.
+Destroying several modules (module names, --all, --type, --search, or --changed)
+or using --parallel is refused unless --yes is given.
+
+Project modules (the highest module kind when module_kinds is configured) are
+never destroyed unless each one is named with --confirm, even when --yes is
+given. Examples of projects do not need confirmation.
+
+Examples:
+  motf destroy storage-account -e basic               # Destroy the 'basic' example (prompts for approval)
+  motf destroy storage-account -e basic --yes         # Destroy the 'basic' example without prompting
+  motf destroy --type component --yes                 # Destroy all components
+  motf destroy prod-infra --confirm prod-infra        # Destroy a project module`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkBulkApproval("destroy", args); err != nil {
+			return err
+		}
+		gitRoot, err := repoRoot()
+		if err != nil {
+			return err
+		}
+
+		if usesModuleSelection(args) {
+			modules, err := selectModules(args)
+			if err != nil {
+				return err
+			}
+			if err := checkProjectDestroy(modules); err != nil {
+				return err
+			}
+			return runOnModuleSetWithPath(modules, func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error {
+				tfRunner, err := moduleRunner(ctx, gitRoot, moduleAbsPath)
+				if err != nil {
+					return err
+				}
+				if initFlag {
+					if err := tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
+						return err
+					}
+				}
+				return tfRunner.RunDestroyWithOutput(moduleAbsPath, stdout, stderr, destroyArgs()...)
+			})
+		}
+
+		targetPath, err := resolveTargetWithExample(args, exampleFlag)
+		if err != nil {
+			return err
+		}
+
+		tfRunner, err := moduleRunner(runContext, gitRoot, targetPath)
+		if err != nil {
+			return err
+		}
+
+		if exampleFlag == "" {
+			basePath, err := getBasePath()
+			if err != nil {
+				return err
+			}
+			relPath, err := filepath.Rel(basePath, targetPath)
+			if err != nil {
+				return err
+			}
+			target := ModuleInfo{Name: filepath.Base(targetPath), Type: getModuleType(targetPath), Path: relPath}
+			if err := checkProjectDestroy([]ModuleInfo{target}); err != nil {
+				return err
+			}
+		}
+
+		// Run init first if flag is set
+		if initFlag {
+			if err := tfRunner.RunInit(targetPath); err != nil {
+				return err
+			}
+		}
+
+		return tfRunner.RunDestroy(targetPath, destroyArgs()...)
+	},
+}
+
+// destroyArgs returns the extra arguments for terraform/tofu destroy; --yes adds -auto-approve
+func destroyArgs() []string {
+	args := append([]string{}, argsFlag...)
+	if yesFlag {
+		args = append(args, "-auto-approve")
+	}
+	return args
+}
+
+// checkProjectDestroy refuses to destroy project modules (the highest module
+// kind) that were not named with --confirm. Confirmed names are resolved like
+// module name arguments, so a name shared by several projects must be
+// qualified (e.g. --confirm azurerm/prod) and confirms only that project.
+func checkProjectDestroy(modules []ModuleInfo) error {
+	projectType := topModuleType()
+	var projects []string
+	for _, mod := range modules {
+		if mod.Type == projectType {
+			path := mod.Path
+			if path == "" {
+				path = mod.Name
+			}
+			projects = append(projects, path)
+		}
+	}
+	if len(projects) == 0 {
+		return nil
+	}
+
+	confirmed, err := confirmedModulePaths()
+	if err != nil {
+		return err
+	}
+	var unconfirmed []string
+	for _, path := range projects {
+		if !confirmed[path] {
+			unconfirmed = append(unconfirmed, path)
+		}
+	}
+	if len(unconfirmed) == 0 {
+		return nil
+	}
+
+	names := qualifiedModuleNames(unconfirmed)
+	confirmArgs := make([]string, 0, len(names))
+	for _, name := range names {
+		confirmArgs = append(confirmArgs, "--confirm "+name)
+	}
+	return fmt.Errorf("refusing to destroy %s module(s) %s without confirmation (pass %s)",
+		projectType, strings.Join(names, ", "), strings.Join(confirmArgs, " "))
+}
+
+// qualifiedModuleNames returns the shortest names telling each of paths, relative
+// to the base path, apart from all discovered modules
+func qualifiedModuleNames(paths []string) []string {
+	all := append([]string{}, paths...)
+	if basePath, err := getBasePath(); err == nil {
+		if index, err := moduleIndex(basePath); err == nil {
+			for _, kind := range moduleKinds() {
+				for _, mod := range index.ModulesIn(filepath.Join(basePath, kind.Dir)) {
+					relPath, err := filepath.Rel(basePath, mod.Path)
+					if err == nil && !slices.Contains(paths, relPath) {
+						all = append(all, relPath)
+					}
+				}
+			}
+		}
+	}
+	return finder.QualifiedNames(all)[:len(paths)]
+}
+
+// confirmedModulePaths resolves the --confirm values to module paths relative
+// to the base path
+func confirmedModulePaths() (map[string]bool, error) {
+	confirmed := make(map[string]bool, len(destroyConfirmFlag))
+	if len(destroyConfirmFlag) == 0 {
+		return confirmed, nil
+	}
+	basePath, err := getBasePath()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range destroyConfirmFlag {
+		modulePath, err := findModuleInAllDirs(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --confirm '%s': %w", name, err)
+		}
+		relPath, err := filepath.Rel(basePath, modulePath)
+		if err != nil {
+			return nil, err
+		}
+		confirmed[relPath] = true
+	}
+	return confirmed, nil
+}
+
+func init() {
+	destroyCmd.Flags().BoolVarP(&yesFlag, "yes", "y", false, "Skip approval prompts (required with --parallel or multiple modules)")
+	destroyCmd.Flags().StringArrayVar(&destroyConfirmFlag, "confirm", []string{}, "Confirm destroying the named project module, qualified if the name is shared (can be specified multiple times)")
+	destroyCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
+	destroyCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	destroyCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	destroyCmd.Flags().StringVar(&typeFlag, "type", "", "Only run on modules of this type, as configured in module_kinds (default: component, base, or project)")
+	destroyCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Only run on modules matching a wildcard (e.g., *storage*)")
+	destroyCmd.Flags().StringArrayVar(&tagFlag, "tag", []string{}, "Only run on modules with this tag (can be specified multiple times)")
+	destroyCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	destroyCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	destroyCmd.Flags().BoolVar(&dependentsFlag, "dependents", false, "Also include modules that transitively depend on changed modules (requires --changed)")
+	destroyCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
+	destroyCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	destroyCmd.Flags().BoolVar(&failFastFlag, "fail-fast", false, "Stop after the first failed module: cancel queued modules and interrupt running ones")
+	destroyCmd.Flags().IntVar(&maxFailuresFlag, "max-failures", 0, "Stop after this many modules have failed (default: no limit)")
+	destroyCmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Interrupt each command or task running longer than this, e.g. 20m (default: execution.timeout, no timeout)")
+	destroyCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry commands failing with an error matching execution.retry_on up to this many times; destroy itself only if execution.commands.destroy.retries is set (default: execution.retries)")
+	destroyCmd.Flags().StringVar(&outputModeFlag, "output-mode", "", "How to print output of multiple modules: stream, grouped, or quiet (default: stream)")
+	destroyCmd.Flags().StringVar(&ciFlag, "ci", "", "Format output for a CI system: github, gitlab, or none (default: detected from GITHUB_ACTIONS/GITLAB_CI)")
+	destroyCmd.Flags().StringArrayVar(&reportFlag, "report", []string{}, "Write a report of the run: junit=<path> or json=<path> (can be specified multiple times)")
+	destroyCmd.Flags().StringVar(&logDirFlag, "log-dir", "", "Write each module's uncolored output to <dir>/<type>/<name>.log")
+	destroyCmd.Flags().BoolVar(&noSummaryFlag, "no-summary", false, "Don't print the summary table after running on several modules")
+	rootCmd.AddCommand(destroyCmd)
+}
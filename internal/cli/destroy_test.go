@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestDestroyCmd_Flags(t *testing.T) {
+	tests := []struct {
+		name      string
+		shorthand string
+	}{
+		{"yes", "y"},
+		{"confirm", ""},
+		{"init", "i"},
+		{"example", "e"},
+		{"changed", ""},
+		{"parallel", "p"},
+	}
+
+	for _, tt := range tests {
+		flag := destroyCmd.Flags().Lookup(tt.name)
+		if flag == nil {
+			t.Errorf("destroyCmd should have --%s flag", tt.name)
+			continue
+		}
+		if flag.Shorthand != tt.shorthand {
+			t.Errorf("--%s: expected shorthand '%s', got '%s'", tt.name, tt.shorthand, flag.Shorthand)
+		}
+	}
+}
+
+func TestDestroyArgs(t *testing.T) {
+	resetFlags(t)
+	argsFlag = []string{"-lock=false"}
+
+	if got, want := destroyArgs(), []string{"-lock=false"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("destroyArgs() = %v, want %v", got, want)
+	}
+
+	yesFlag = true
+	if got, want := destroyArgs(), []string{"-lock=false", "-auto-approve"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("destroyArgs() with --yes = %v, want %v", got, want)
+	}
+}
+
+func TestCheckProjectDestroy(t *testing.T) {
+	modules := []ModuleInfo{
+		{Name: "storage-account", Type: TypeComponent, Path: "components/storage-account"},
+		{Name: "prod", Type: TypeProject, Path: "projects/prod"},
+		{Name: "dev", Type: TypeProject, Path: "projects/dev"},
+	}
+
+	tests := []struct {
+		name    string
+		confirm []string
+		wantErr string
+	}{
+		{"none confirmed", nil, "refusing to destroy project module(s) prod, dev"},
+		{"one confirmed", []string{"prod"}, "pass --confirm dev)"},
+		{"all confirmed", []string{"prod", "dev"}, ""},
+		{"qualified", []string{"projects/prod", "project:dev"}, ""},
+		{"unknown", []string{"prod", "staging"}, "invalid --confirm 'staging': module 'staging' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			tmpDir := setupSelectionTree(t)
+			createTerraformModule(t, tmpDir, "projects/dev")
+			destroyConfirmFlag = tt.confirm
+
+			err := checkProjectDestroy(modules)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckProjectDestroy_SharedName(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "projects/azurerm/prod")
+	createTerraformModule(t, tmpDir, "projects/aws/prod")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	modules := []ModuleInfo{
+		{Name: "prod", Type: TypeProject, Path: filepath.Join("projects", "aws", "prod")},
+		{Name: "prod", Type: TypeProject, Path: filepath.Join("projects", "azurerm", "prod")},
+	}
+
+	destroyConfirmFlag = []string{"prod"}
+	if err := checkProjectDestroy(modules); err == nil || !strings.Contains(err.Error(), "name clash detected") {
+		t.Fatalf("expected a shared name not to confirm either project, got %v", err)
+	}
+
+	destroyConfirmFlag = []string{"azurerm/prod"}
+	err := checkProjectDestroy(modules)
+	if err == nil || !strings.Contains(err.Error(), "refusing to destroy project module(s) aws/prod without confirmation (pass --confirm aws/prod)") {
+		t.Fatalf("expected only azurerm/prod to be confirmed, got %v", err)
+	}
+}
+
+func TestDestroyCmd_RefusesProjectWithoutConfirm(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func()
+		args  []string
+	}{
+		{"single project", func() {}, []string{"prod"}},
+		{"single project with yes", func() { yesFlag = true }, []string{"prod"}},
+		{"selection with project", func() { yesFlag = true; allFlag = true }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			setupSelectionTree(t)
+			tt.setup()
+
+			err := destroyCmd.RunE(destroyCmd, tt.args)
+			if err == nil || !strings.Contains(err.Error(), "--confirm prod") {
+				t.Fatalf("expected --confirm error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+// Drift check modes
+const (
+	DriftModeRefreshOnly = "refresh-only"
+	DriftModePlan        = "plan"
+)
+
+var (
+	driftModeFlag        string // How drift is detected: refresh-only or plan
+	driftJSONFlag        bool   // Output results as JSON
+	driftFailOnErrorFlag bool   // Also exit non-zero when a module errored
+)
+
+// driftCmd represents the drift command
+var driftCmd = &cobra.Command{
+	Use:   "drift [module-name...]",
+	Short: "Detect drift in project modules",
+	Long: `Detect drift between real infrastructure and project modules.
+
+Runs 'plan -detailed-exitcode -refresh-only' on every project module and classifies
+each one by exit code:
+
+  clean    no differences (exit code 0)
+  drifted  differences found (exit code 2)
+  errored  the plan failed (any other exit code)
+
+Modules not checked because the run stopped (--fail-fast, --max-failures, or a
+signal) are reported as cancelled.
+
+Use --mode plan to run a normal plan instead, which also reports configuration
+changes that have not been applied yet.
+
+Modules can be selected like other run commands (module names, --type, --search,
+--tag, --changed); without a selection all project modules (the highest module
+kind when module_kinds is configured) are checked.
+
+motf exits non-zero when drift is found or the run stopped early. Errored modules
+are reported but do not fail the run unless --fail-on-error is set.`,
+	Example: `  motf drift                            # Check all projects
+  motf drift -i -p                      # Init first and check projects in parallel
+  motf drift --json                     # Results as JSON (plan output goes to stderr)
+  motf drift --mode plan                # Use a normal plan instead of -refresh-only
+  motf drift prod-infra                 # Check a single module
+  motf drift --type base                # Check bases instead of projects`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runDrift,
+}
+
+func init() {
+	driftCmd.Flags().StringVar(&driftModeFlag, "mode", DriftModeRefreshOnly, "Drift check mode: refresh-only or plan")
+	driftCmd.Flags().BoolVar(&driftJSONFlag, "json", false, "Output results in JSON format")
+	driftCmd.Flags().BoolVar(&driftFailOnErrorFlag, "fail-on-error", false, "Also exit non-zero when a module errored")
+	driftCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
+	driftCmd.Flags().StringVar(&typeFlag, "type", "", "Only check modules of this type (default: the highest module kind, project unless module_kinds is configured)")
+	driftCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Only check modules matching a wildcard (e.g., *prod*)")
+	driftCmd.Flags().StringArrayVar(&tagFlag, "tag", []string{}, "Only check modules with this tag (can be specified multiple times)")
+	driftCmd.Flags().BoolVar(&changedFlag, "changed", false, "Only check modules changed compared to --ref")
+	driftCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	driftCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
+	driftCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	driftCmd.Flags().BoolVar(&failFastFlag, "fail-fast", false, "Stop after the first errored module: cancel queued modules and interrupt running ones")
+	driftCmd.Flags().IntVar(&maxFailuresFlag, "max-failures", 0, "Stop after this many modules have errored (default: no limit)")
+	driftCmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Interrupt each command or task running longer than this, e.g. 20m (default: execution.timeout, no timeout)")
+	driftCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry commands failing with an error matching execution.retry_on up to this many times (default: execution.retries)")
+	driftCmd.Flags().StringVar(&outputModeFlag, "output-mode", "", "How to print output of multiple modules: stream, grouped, or quiet (default: stream)")
+	driftCmd.Flags().StringVar(&ciFlag, "ci", "", "Format output for a CI system: github, gitlab, or none (default: detected from GITHUB_ACTIONS/GITLAB_CI)")
+	rootCmd.AddCommand(driftCmd)
+}
+
+// driftResult is the drift check result of one module
+type driftResult struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func runDrift(cmd *cobra.Command, args []string) error {
+	if driftModeFlag != DriftModeRefreshOnly && driftModeFlag != DriftModePlan {
+		return fmt.Errorf("invalid mode '%s': must be '%s' or '%s'", driftModeFlag, DriftModeRefreshOnly, DriftModePlan)
+	}
+
+	if _, err := resolveMaxFailures(); err != nil {
+		return err
+	}
+
+	modules, err := selectDriftModules(args)
+	if err != nil {
+		return err
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	gitRoot, err := repoRoot()
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	var results []driftResult
+
+	var parallelismCfg *config.ParallelismConfig
+	if cfg != nil {
+		parallelismCfg = cfg.Parallelism
+	}
+
+	// Errored modules count as failures of the run, for --fail-fast and
+	// --max-failures, but every module outcome is recorded as a result, so the
+	// drift table replaces the run summary and decides the exit code. The flags
+	// that could fail the run itself are checked above.
+	_ = runAndReport(modules, parallelismCfg, false, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+		// Keep stdout clean for JSON output
+		if driftJSONFlag {
+			stdout = stderr
+		}
+
+		moduleAbsPath := filepath.Join(basePath, mod.Path)
+		status, checkErr := checkModuleDrift(ctx, gitRoot, moduleAbsPath, stdout, stderr)
+		result := driftResult{Name: mod.Name, Path: mod.Path, Status: status}
+		if checkErr != nil {
+			result.Error = checkErr.Error()
+			if ctx.Err() != nil {
+				result.Status = StatusCancelled
+			} else {
+				_, _ = fmt.Fprintf(stderr, "Drift check failed: %v\n", checkErr)
+			}
+		}
+
+		mu.Lock()
+		results = append(results, result)
+		mu.Unlock()
+		return checkErr
+	})
+	results = append(results, cancelledDriftResults(modules, results)...)
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+
+	if driftJSONFlag {
+		if err := printDriftJSON(cmd.OutOrStdout(), results); err != nil {
+			return err
+		}
+	} else {
+		printDriftTable(cmd.OutOrStdout(), results)
+	}
+
+	// A failing drift check is a result, not a usage error
+	cmd.SilenceUsage = true
+	return driftExitError(results)
+}
+
+// selectDriftModules selects modules like other run commands, defaulting to
+// project modules (the highest module kind) when neither module names nor
+// --type are given.
+func selectDriftModules(args []string) ([]ModuleInfo, error) {
+	modules, err := selectModules(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 && typeFlag == "" {
+		modules = filterModules(modules, topModuleType(), "", nil)
+	}
+	return modules, nil
+}
+
+// checkModuleDrift runs init (if requested) and the drift check in moduleAbsPath
+func checkModuleDrift(ctx context.Context, gitRoot, moduleAbsPath string, stdout, stderr io.Writer) (string, error) {
+	tfRunner, err := moduleRunner(ctx, gitRoot, moduleAbsPath)
+	if err != nil {
+		return terraform.DriftErrored, err
+	}
+	if initFlag {
+		if err := tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
+			return terraform.DriftErrored, fmt.Errorf("init failed: %w", err)
+		}
+	}
+	return tfRunner.RunDriftCheckWithOutput(moduleAbsPath, driftModeFlag == DriftModeRefreshOnly, stdout, stderr, argsFlag...)
+}
+
+// cancelledDriftResults returns cancelled results for the modules without a
+// result, which were not started because the run stopped
+func cancelledDriftResults(modules []ModuleInfo, results []driftResult) []driftResult {
+	checked := make(map[string]bool, len(results))
+	for _, r := range results {
+		checked[r.Path] = true
+	}
+	var cancelled []driftResult
+	for _, mod := range modules {
+		if !checked[mod.Path] {
+			cancelled = append(cancelled, driftResult{Name: mod.Name, Path: mod.Path, Status: StatusCancelled})
+		}
+	}
+	return cancelled
+}
+
+// driftCounts returns the number of clean, drifted, errored, and cancelled results
+func driftCounts(results []driftResult) (clean, drifted, errored, cancelled int) {
+	for _, r := range results {
+		switch r.Status {
+		case terraform.DriftClean:
+			clean++
+		case terraform.DriftDrifted:
+			drifted++
+		case StatusCancelled:
+			cancelled++
+		default:
+			errored++
+		}
+	}
+	return clean, drifted, errored, cancelled
+}
+
+// driftExitError returns an error when drift was found, when the run stopped
+// before checking every module, or when a module errored and --fail-on-error
+// is set.
+func driftExitError(results []driftResult) error {
+	_, drifted, errored, cancelled := driftCounts(results)
+	if drifted > 0 {
+		return fmt.Errorf("drift detected in %d module(s)", drifted)
+	}
+	if cancelled > 0 {
+		return fmt.Errorf("drift check cancelled in %d module(s)", cancelled)
+	}
+	if driftFailOnErrorFlag && errored > 0 {
+		return fmt.Errorf("drift check failed in %d module(s)", errored)
+	}
+	return nil
+}
+
+// printDriftTable writes drift results as a table followed by a one-line summary
+func printDriftTable(w io.Writer, results []driftResult) {
+	if len(results) == 0 {
+		_, _ = fmt.Fprintln(w, "No modules to check")
+		return
+	}
+
+	nameWidth := len("MODULE")
+	pathWidth := len("PATH")
+	for _, r := range results {
+		if len(r.Name) > nameWidth {
+			nameWidth = len(r.Name)
+		}
+		if len(r.Path) > pathWidth {
+			pathWidth = len(r.Path)
+		}
+	}
+
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintf(w, "%-*s  %-*s  %s\n", nameWidth, "MODULE", pathWidth, "PATH", "STATUS")
+	for _, r := range results {
+		_, _ = fmt.Fprintf(w, "%-*s  %-*s  %s\n", nameWidth, r.Name, pathWidth, r.Path, r.Status)
+	}
+
+	clean, drifted, errored, cancelled := driftCounts(results)
+	_, _ = fmt.Fprintf(w, "\n%d clean, %d drifted, %d errored", clean, drifted, errored)
+	if cancelled > 0 {
+		_, _ = fmt.Fprintf(w, ", %d cancelled", cancelled)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// printDriftJSON writes drift results as indented JSON
+func printDriftJSON(w io.Writer, results []driftResult) error {
+	if results == nil {
+		results = []driftResult{}
+	}
+	output, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+// fakeDriftBinary exits 2 (drift) in "prod", 1 (error) in "broken", and 0 elsewhere
+const fakeDriftBinary = `#!/bin/sh
+case "$(basename "$PWD")" in
+prod) exit 2 ;;
+broken) echo "boom" >&2; exit 1 ;;
+esac
+exit 0
+`
+
+// setupDriftTree creates three projects and a component
+func setupDriftTree(t *testing.T) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "projects/prod")
+	createTerraformModule(t, tmpDir, "projects/dev")
+	createTerraformModule(t, tmpDir, "projects/broken")
+	createTerraformModule(t, tmpDir, "components/storage")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+}
+
+func TestDriftCmd_Flags(t *testing.T) {
+	for _, name := range []string{"mode", "json", "fail-on-error", "init", "type", "search", "changed", "parallel", "max-parallel", "fail-fast", "max-failures"} {
+		if driftCmd.Flags().Lookup(name) == nil {
+			t.Errorf("drift command should have --%s flag", name)
+		}
+	}
+	if def := driftCmd.Flags().Lookup("mode").DefValue; def != DriftModeRefreshOnly {
+		t.Errorf("--mode default = %q, want %q", def, DriftModeRefreshOnly)
+	}
+}
+
+func TestSelectDriftModules_DefaultsToProjects(t *testing.T) {
+	resetFlags(t)
+	setupDriftTree(t)
+
+	modules, err := selectDriftModules(nil)
+	if err != nil {
+		t.Fatalf("selectDriftModules returned error: %v", err)
+	}
+	if got, want := moduleNames(modules), []string{"broken", "dev", "prod"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("selectDriftModules() = %v, want %v", got, want)
+	}
+
+	typeFlag = TypeComponent
+	modules, err = selectDriftModules(nil)
+	if err != nil {
+		t.Fatalf("selectDriftModules returned error: %v", err)
+	}
+	if got, want := moduleNames(modules), []string{"storage"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("selectDriftModules() with --type = %v, want %v", got, want)
+	}
+}
+
+func TestRunDrift_ClassifiesModules(t *testing.T) {
+	resetFlags(t)
+	setupDriftTree(t)
+	withFakeRunner(t, fakeDriftBinary)
+	driftJSONFlag = true
+	parallelFlag = true
+
+	var buf bytes.Buffer
+	driftCmd.SetOut(&buf)
+	t.Cleanup(func() { driftCmd.SetOut(nil) })
+
+	err := runDrift(driftCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "drift detected in 1 module(s)") {
+		t.Fatalf("expected drift error, got %v", err)
+	}
+
+	var results []driftResult
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	got := map[string]string{}
+	for _, r := range results {
+		got[r.Name] = r.Status
+	}
+	want := map[string]string{"broken": terraform.DriftErrored, "dev": terraform.DriftClean, "prod": terraform.DriftDrifted}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+	if results[0].Name != "broken" || results[0].Error == "" {
+		t.Errorf("expected errored module to carry an error, got %+v", results[0])
+	}
+}
+
+func TestRunDrift_ErrorsOnlyFailWithFlag(t *testing.T) {
+	resetFlags(t)
+	setupDriftTree(t)
+	withFakeRunner(t, fakeDriftBinary)
+
+	var buf bytes.Buffer
+	driftCmd.SetOut(&buf)
+	t.Cleanup(func() { driftCmd.SetOut(nil) })
+
+	// "broken" errors but has no drift
+	if err := runDrift(driftCmd, []string{"broken", "dev"}); err != nil {
+		t.Fatalf("expected no error without --fail-on-error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "1 clean, 0 drifted, 1 errored") {
+		t.Errorf("expected summary line in output, got: %s", buf.String())
+	}
+
+	driftFailOnErrorFlag = true
+	if err := runDrift(driftCmd, []string{"broken", "dev"}); err == nil {
+		t.Fatal("expected error with --fail-on-error")
+	}
+}
+
+func TestRunDrift_FailFast(t *testing.T) {
+	resetFlags(t)
+	setupDriftTree(t)
+	withFakeRunner(t, fakeDriftBinary)
+
+	var buf bytes.Buffer
+	driftCmd.SetOut(&buf)
+	t.Cleanup(func() { driftCmd.SetOut(nil) })
+
+	// "broken" is checked first; the rest of the run is cancelled
+	failFastFlag = true
+	var err error
+	_ = captureOutput(t, &os.Stderr, func() {
+		err = runDrift(driftCmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "drift check cancelled in 2 module(s)") {
+		t.Fatalf("expected the cancelled modules to fail the run, got %v", err)
+	}
+	for _, want := range []string{"broken  projects/broken  errored", "dev     projects/dev     cancelled", "prod    projects/prod    cancelled", "0 clean, 0 drifted, 1 errored, 2 cancelled"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got: %s", want, buf.String())
+		}
+	}
+}
+
+func TestRunDrift_InvalidMode(t *testing.T) {
+	resetFlags(t)
+	driftModeFlag = "apply"
+
+	if err := runDrift(driftCmd, nil); err == nil || !strings.Contains(err.Error(), "invalid mode") {
+		t.Fatalf("expected invalid mode error, got %v", err)
+	}
+}
+
+func TestPrintDriftTable(t *testing.T) {
+	results := []driftResult{
+		{Name: "dev", Path: "projects/dev", Status: terraform.DriftClean},
+		{Name: "prod", Path: "projects/prod", Status: terraform.DriftDrifted},
+	}
+
+	var buf bytes.Buffer
+	printDriftTable(&buf, results)
+	out := buf.String()
+
+	for _, want := range []string{"MODULE  PATH           STATUS", "prod    projects/prod  drifted", "1 clean, 1 drifted, 0 errored"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table output missing %q:\n%s", want, out)
+		}
+	}
+}
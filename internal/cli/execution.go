@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	timeoutFlag time.Duration // Timeout of each terraform/tofu command or task; overrides execution settings
+	retriesFlag int           // Retries of commands failing with a retryable error; overrides execution settings
+)
+
+// applyExecutionFlags validates --timeout and --retries and, when set, makes
+// them override the execution settings of every command and task in c
+func applyExecutionFlags(cmd *cobra.Command, c *config.Config) error {
+	if timeoutFlag < 0 {
+		return fmt.Errorf("invalid --timeout %s: must not be negative", timeoutFlag)
+	}
+	if retriesFlag < 0 {
+		return fmt.Errorf("invalid --retries %d: must be 0 or greater", retriesFlag)
+	}
+
+	timeoutSet, retriesSet := cmd.Flags().Changed("timeout"), cmd.Flags().Changed("retries")
+	if !timeoutSet && !retriesSet {
+		return nil
+	}
+
+	if c.Execution == nil {
+		c.Execution = &config.ExecutionConfig{}
+	}
+	override := &config.CommandExecution{}
+	if timeoutSet {
+		timeout := timeoutFlag
+		override.Timeout = &timeout
+	}
+	if retriesSet {
+		retries := retriesFlag
+		override.Retries = &retries
+	}
+	c.Execution.Override = override
+	return nil
+}
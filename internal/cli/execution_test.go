@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+// fakeFlakyBinary fails init with a provider download error the first time it
+// runs in "flaky" and succeeds otherwise
+const fakeFlakyBinary = `#!/bin/sh
+if [ "$(basename "$PWD")" = "flaky" ] && [ ! -f .attempted ]; then
+  touch .attempted
+  echo "Error: Failed to install provider" >&2
+  exit 1
+fi
+exit 0
+`
+
+// executionFlagsCmd returns a command with the --timeout and --retries flags
+func executionFlagsCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "")
+	cmd.Flags().IntVar(&retriesFlag, "retries", 0, "")
+	return cmd
+}
+
+func TestApplyExecutionFlags(t *testing.T) {
+	resetFlags(t)
+
+	c := &config.Config{}
+	if err := applyExecutionFlags(executionFlagsCmd(), c); err != nil || c.Execution != nil {
+		t.Fatalf("expected no change without flags, got %+v, %v", c.Execution, err)
+	}
+
+	cmd := executionFlagsCmd()
+	if err := cmd.Flags().Set("timeout", "5m"); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyExecutionFlags(cmd, c); err != nil {
+		t.Fatalf("applyExecutionFlags returned error: %v", err)
+	}
+	if o := c.Execution.Override; o == nil || o.Timeout == nil || *o.Timeout != 5*time.Minute || o.Retries != nil {
+		t.Errorf("expected only the timeout to be overridden, got %+v", o)
+	}
+
+	cmd = executionFlagsCmd()
+	if err := cmd.Flags().Set("retries", "-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyExecutionFlags(cmd, c); err == nil || !strings.Contains(err.Error(), "invalid --retries -1") {
+		t.Errorf("expected invalid --retries error, got %v", err)
+	}
+}
+
+func TestInit_RetriesFlakyModule(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/flaky")
+	createTerraformModule(t, tmpDir, "components/stable")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	binary := filepath.Join(t.TempDir(), "fake-terraform")
+	if err := os.WriteFile(binary, []byte(fakeFlakyBinary), 0755); err != nil {
+		t.Fatal(err)
+	}
+	original := runner
+	runner = terraform.NewRunner(&config.Config{Binary: binary, Execution: &config.ExecutionConfig{Retries: 1, Backoff: time.Millisecond}})
+	t.Cleanup(func() { runner = original })
+
+	allFlag = true
+	var runErr error
+	stderr := captureOutput(t, &os.Stderr, func() {
+		_ = captureOutput(t, &os.Stdout, func() {
+			runErr = initCmd.RunE(initCmd, nil)
+		})
+	})
+	if runErr != nil {
+		t.Fatalf("expected the retried module to succeed, got %v", runErr)
+	}
+	if !strings.Contains(stderr, `Retrying in 1ms (attempt 2 of 2): output matched "Failed to install provider"`) {
+		t.Errorf("expected the retry to be announced, got: %s", stderr)
+	}
+	if !strings.Contains(stderr, "MODULE  STATUS   DURATION  RETRIES  ERROR") {
+		t.Errorf("expected a RETRIES column in the summary, got: %s", stderr)
+	}
+}
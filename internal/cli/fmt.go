@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"io"
 
 	"github.com/spf13/cobra"
@@ -8,29 +9,47 @@ import (
 
 // fmtCmd represents the fmt command
 var fmtCmd = &cobra.Command{
-	Use:   "fmt [module-name]",
-	Short: "Run terraform/tofu fmt on a component, base, or project",
-	Long: `Run terraform/tofu fmt on a component, base, or project.
+	Use:   "fmt [module-name...]",
+	Short: "Run terraform/tofu fmt on a module",
+	Long: `Run terraform/tofu fmt on a module: a component, base, or project by default,
+or a module of any kind configured in module_kinds.
 
 Use the --example/-e flag to run fmt on a specific example instead of the module itself.
 
+Several modules can be selected at once by passing multiple module names or
+using --all, --type, --search, --tag, or --changed. Selected modules run through the
+same pipeline as --changed (supporting --parallel and --dag).
+
 Examples:
   motf fmt storage-account              # Run fmt on storage-account module
   motf fmt storage-account -e basic     # Run fmt on the 'basic' example
-  motf fmt -i storage-account -e basic  # Run init then fmt on the 'basic' example`,
-	Args: cobra.MaximumNArgs(1),
+  motf fmt -i storage-account -e basic  # Run init then fmt on the 'basic' example
+  motf fmt storage-account key-vault    # Run fmt on several modules
+  motf fmt --all -a -check              # Check formatting of all modules
+  motf fmt --type component -p          # Run fmt on all components in parallel`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if changedFlag {
-			if len(args) > 0 {
-				return cobra.MaximumNArgs(0)(cmd, args)
-			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
+		gitRoot, err := repoRoot()
+		if err != nil {
+			return err
+		}
+		ciRoot := annotationRoot(gitRoot)
+
+		if usesModuleSelection(args) {
+			return runOnSelectedModulesWithPath(args, func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error {
+				tfRunner, err := moduleRunner(ctx, gitRoot, moduleAbsPath)
+				if err != nil {
+					return err
+				}
 				if initFlag {
-					if err := runner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
+					if err := tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
 						return err
 					}
 				}
-				return runner.RunFmtWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
+				if ciProvider == CIGitHub && isFmtCheck(argsFlag) {
+					return fmtCheckForCI(ctx, tfRunner, ciRoot, moduleAbsPath, stdout, stderr)
+				}
+				return tfRunner.RunFmtWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
 			})
 		}
 
@@ -39,23 +58,43 @@ Examples:
 			return err
 		}
 
+		tfRunner, err := moduleRunner(runContext, gitRoot, targetPath)
+		if err != nil {
+			return err
+		}
+
 		// Run init first if flag is set
 		if initFlag {
-			if err := runner.RunInit(targetPath); err != nil {
+			if err := tfRunner.RunInit(targetPath); err != nil {
 				return err
 			}
 		}
 
-		return runner.RunFmt(targetPath, argsFlag...)
+		return tfRunner.RunFmt(targetPath, argsFlag...)
 	},
 }
 
 func init() {
 	fmtCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
 	fmtCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	fmtCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	fmtCmd.Flags().StringVar(&typeFlag, "type", "", "Only run on modules of this type, as configured in module_kinds (default: component, base, or project)")
+	fmtCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Only run on modules matching a wildcard (e.g., *storage*)")
+	fmtCmd.Flags().StringArrayVar(&tagFlag, "tag", []string{}, "Only run on modules with this tag (can be specified multiple times)")
 	fmtCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
 	fmtCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	fmtCmd.Flags().BoolVar(&dependentsFlag, "dependents", false, "Also include modules that transitively depend on changed modules (requires --changed)")
 	fmtCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
 	fmtCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	fmtCmd.Flags().BoolVar(&failFastFlag, "fail-fast", false, "Stop after the first failed module: cancel queued modules and interrupt running ones")
+	fmtCmd.Flags().IntVar(&maxFailuresFlag, "max-failures", 0, "Stop after this many modules have failed (default: no limit)")
+	fmtCmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Interrupt each command or task running longer than this, e.g. 20m (default: execution.timeout, no timeout)")
+	fmtCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry commands failing with an error matching execution.retry_on up to this many times (default: execution.retries)")
+	fmtCmd.Flags().StringVar(&outputModeFlag, "output-mode", "", "How to print output of multiple modules: stream, grouped, or quiet (default: stream)")
+	fmtCmd.Flags().StringVar(&ciFlag, "ci", "", "Format output for a CI system: github, gitlab, or none (default: detected from GITHUB_ACTIONS/GITLAB_CI)")
+	fmtCmd.Flags().BoolVar(&dagFlag, "dag", false, "Run modules in dependency order and skip dependents of failed modules")
+	fmtCmd.Flags().StringArrayVar(&reportFlag, "report", []string{}, "Write a report of the run: junit=<path> or json=<path> (can be specified multiple times)")
+	fmtCmd.Flags().StringVar(&logDirFlag, "log-dir", "", "Write each module's uncolored output to <dir>/<type>/<name>.log")
+	fmtCmd.Flags().BoolVar(&noSummaryFlag, "no-summary", false, "Don't print the summary table after running on several modules")
 	rootCmd.AddCommand(fmtCmd)
 }
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
 	"github.com/TechnicallyJoe/terraform-motf/internal/spacelift"
 	"github.com/spf13/cobra"
@@ -18,10 +19,14 @@ var getJsonFlag bool
 // getCmd represents the get command
 var getCmd = &cobra.Command{
 	Use:   "get [module-name]",
-	Short: "Get details about a component, base, or project",
+	Short: "Get details about a module",
 	Long: `Get detailed information about a module including its type, path,
 whether it has submodules, tests, examples, and its Spacelift registry version.
 
+Metadata from the module's .motf.module.yml (description, lifecycle, owners,
+tags) is shown together with the binary and test engine used for the module,
+which reflect any per-module overrides.
+
 Use the --json flag to output in JSON format for scripting.
 
 Examples:
@@ -49,6 +54,12 @@ type ModuleDetails struct {
 	Examples         []ItemInfo `json:"examples,omitempty"`
 	Tests            []ItemInfo `json:"tests,omitempty"`
 	SpaceliftVersion string     `json:"spacelift_version,omitempty"`
+	Description      string     `json:"description,omitempty"`
+	Lifecycle        string     `json:"lifecycle,omitempty"`
+	Owners           []string   `json:"owners,omitempty"`
+	Tags             []string   `json:"tags,omitempty"`
+	Binary           string     `json:"binary"`
+	TestEngine       string     `json:"test_engine,omitempty"`
 }
 
 // ItemInfo contains information about an example
@@ -115,7 +126,18 @@ func getModuleDetails(modulePath string) (*ModuleDetails, error) {
 	// Get Spacelift version
 	spaceliftVersion := spacelift.ReadModuleVersion(modulePath)
 
-	return &ModuleDetails{
+	// Get metadata and the effective binary/test settings from .motf.module.yml
+	moduleCfg, err := config.LoadModuleConfig(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	effective := cfg.ForModule(moduleCfg)
+	var testEngine string
+	if effective.Test != nil {
+		testEngine = effective.Test.Engine
+	}
+
+	details := &ModuleDetails{
 		Name:             name,
 		Type:             modType,
 		Path:             relativePath,
@@ -126,7 +148,17 @@ func getModuleDetails(modulePath string) (*ModuleDetails, error) {
 		Examples:         examples,
 		Tests:            tests,
 		SpaceliftVersion: spaceliftVersion,
-	}, nil
+		Binary:           effective.Binary,
+		TestEngine:       testEngine,
+	}
+	if moduleCfg != nil {
+		details.Description = moduleCfg.Description
+		details.Lifecycle = moduleCfg.Lifecycle
+		details.Owners = moduleCfg.Owners
+		details.Tags = moduleCfg.Tags
+	}
+
+	return details, nil
 }
 
 // dirHasContent checks if a directory exists and has at least one entry
@@ -203,6 +235,12 @@ func printModuleDetails(details *ModuleDetails) {
 	fmt.Printf("Type:                  %s\n", formatType(details.Type))
 	fmt.Printf("Path:                  %s\n", details.Path)
 	fmt.Printf("Spacelift Version:     %s\n", details.SpaceliftVersion)
+	fmt.Printf("Description:           %s\n", details.Description)
+	fmt.Printf("Lifecycle:             %s\n", details.Lifecycle)
+	fmt.Printf("Owners:                %s\n", strings.Join(details.Owners, ", "))
+	fmt.Printf("Tags:                  %s\n", strings.Join(details.Tags, ", "))
+	fmt.Printf("Binary:                %s\n", details.Binary)
+	fmt.Printf("Test Engine:           %s\n", details.TestEngine)
 	fmt.Printf("Has Submodules:        %s\n", formatBool(details.HasSubmodules))
 	fmt.Printf("Has Tests:             %s\n", formatBool(details.HasTests))
 	fmt.Printf("Has Examples:          %s\n", formatBool(details.HasExamples))
@@ -448,6 +448,49 @@ func TestGetModuleDetails_BaseType(t *testing.T) {
 	}
 }
 
+func TestGetModuleDetails_WithMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg = &config.Config{Root: "", Binary: "terraform", Test: &config.TestConfig{Engine: "terratest"}}
+
+	modulePath := filepath.Join(tmpDir, DirComponents, "storage-account")
+	if err := os.MkdirAll(modulePath, 0755); err != nil {
+		t.Fatalf("failed to create module directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(modulePath, "main.tf"), []byte("# terraform"), 0644); err != nil {
+		t.Fatalf("failed to create main.tf: %v", err)
+	}
+	writeModuleMetadata(t, modulePath, `description: Storage account
+lifecycle: deprecated
+owners: ["@storage-team"]
+tags: [storage]
+binary: tofu
+test:
+  engine: tofu
+`)
+
+	originalWd, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer func() { _ = os.Chdir(originalWd) }()
+
+	details, err := getModuleDetails(modulePath)
+	if err != nil {
+		t.Fatalf("getModuleDetails returned error: %v", err)
+	}
+
+	if details.Description != "Storage account" || details.Lifecycle != config.LifecycleDeprecated {
+		t.Errorf("unexpected description/lifecycle: %q/%q", details.Description, details.Lifecycle)
+	}
+	if len(details.Owners) != 1 || len(details.Tags) != 1 {
+		t.Errorf("unexpected owners/tags: %v/%v", details.Owners, details.Tags)
+	}
+	if details.Binary != "tofu" || details.TestEngine != "tofu" {
+		t.Errorf("expected overridden binary and test engine, got %q/%q", details.Binary, details.TestEngine)
+	}
+}
+
 func TestGetModuleDetails_ProjectType(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -0,0 +1,349 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/TechnicallyJoe/terraform-motf/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+// Graph output formats
+const (
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+	GraphFormatJSON    = "json"
+)
+
+var (
+	graphFormatFlag  string // Output format for graph command
+	graphReverseFlag bool   // Show dependents instead of dependencies of the focused module
+)
+
+// graphCmd represents the graph command
+var graphCmd = &cobra.Command{
+	Use:   "graph [module-name]",
+	Short: "Render the module dependency graph",
+	Long: `Render the dependency graph between modules (components, bases, and projects
+by default; see module_kinds in .motf.yml).
+
+Dependencies are discovered by parsing local module blocks (source = "../..").
+An edge A -> B means module A sources module B. Edges that skip a layer
+(e.g. a project sourcing a component directly instead of through a base)
+are highlighted.
+
+When a module name is given, only that module and everything it depends on
+is shown. Use --reverse to show everything that depends on it instead.`,
+	Example: `  motf graph                              # Full graph in DOT format
+  motf graph --format mermaid             # Full graph as a Mermaid diagram
+  motf graph --format json                # Full graph as JSON
+  motf graph storage-account              # storage-account and its dependencies
+  motf graph storage-account --reverse    # Everything that depends on storage-account
+  motf graph -s *azurerm*                 # Only modules matching a wildcard
+  motf graph --changed --dependents       # Changed modules and their dependents
+  motf graph | dot -Tsvg > graph.svg      # Render with graphviz`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGraph,
+}
+
+func init() {
+	graphCmd.Flags().StringVarP(&graphFormatFlag, "format", "f", GraphFormatDOT, "Output format: dot, mermaid, or json")
+	graphCmd.Flags().BoolVar(&graphReverseFlag, "reverse", false, "Show modules that depend on the given module instead of its dependencies")
+	graphCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Filter modules using wildcards (e.g., *storage*)")
+	graphCmd.Flags().BoolVar(&changedFlag, "changed", false, "Only include modules changed compared to --ref")
+	graphCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	graphCmd.Flags().BoolVar(&dependentsFlag, "dependents", false, "Also include modules that transitively depend on changed modules (requires --changed)")
+	rootCmd.AddCommand(graphCmd)
+}
+
+// graphEdge is a dependency between two modules, identified by their paths
+type graphEdge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	SkipsLayer bool   `json:"skips_layer,omitempty"`
+}
+
+// moduleGraphView is the subset of the dependency graph selected for output
+type moduleGraphView struct {
+	Nodes []ModuleInfo `json:"nodes"`
+	Edges []graphEdge  `json:"edges"`
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	switch graphFormatFlag {
+	case GraphFormatDOT, GraphFormatMermaid, GraphFormatJSON:
+	default:
+		return fmt.Errorf("invalid format '%s': must be '%s', '%s', or '%s'", graphFormatFlag, GraphFormatDOT, GraphFormatMermaid, GraphFormatJSON)
+	}
+
+	focused := len(args) > 0 || pathFlag != ""
+	if graphReverseFlag && !focused {
+		return fmt.Errorf("--reverse requires a module name or --path")
+	}
+	if focused && changedFlag {
+		return fmt.Errorf("--changed cannot be used with a module name or --path")
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	allModules, err := collectModules(basePath, "")
+	if err != nil {
+		return err
+	}
+	sortModules(allModules)
+
+	g := buildModuleGraph(basePath, allModules)
+
+	var selected map[string]bool
+	switch {
+	case focused:
+		selected, err = selectFocusedModules(args, basePath, g)
+	case changedFlag:
+		selected, err = selectChangedModules()
+	default:
+		selected = make(map[string]bool, len(allModules))
+		for _, mod := range allModules {
+			selected[mod.Path] = true
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	view := buildGraphView(allModules, g, selected, searchFlag)
+
+	out := cmd.OutOrStdout()
+	switch graphFormatFlag {
+	case GraphFormatMermaid:
+		return renderMermaid(out, view)
+	case GraphFormatJSON:
+		return renderGraphJSON(out, view)
+	default:
+		return renderDOT(out, view)
+	}
+}
+
+// selectFocusedModules returns the focused module plus its transitive
+// dependencies, or its transitive dependents when graphReverseFlag is set.
+func selectFocusedModules(args []string, basePath string, g *graph.Graph) (map[string]bool, error) {
+	targetPath, err := resolveTargetPath(args)
+	if err != nil {
+		return nil, err
+	}
+
+	relPath, err := filepath.Rel(basePath, targetPath)
+	if err != nil || !g.HasNode(relPath) {
+		return nil, fmt.Errorf("%s is not a module in %s", targetPath, joinWithOr(moduleDirs()))
+	}
+
+	related := g.TransitiveDependencies(relPath)
+	if graphReverseFlag {
+		related = g.TransitiveDependents(relPath)
+	}
+
+	selected := map[string]bool{relPath: true}
+	for _, id := range related {
+		selected[id] = true
+	}
+	return selected, nil
+}
+
+// selectChangedModules returns the paths of changed modules (and their
+// dependents when --dependents is set).
+func selectChangedModules() (map[string]bool, error) {
+	modules, err := detectChangedModules(refFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(modules))
+	for _, mod := range modules {
+		selected[mod.Path] = true
+	}
+	return selected, nil
+}
+
+// buildGraphView restricts the graph to the selected modules matching searchFilter.
+// Only edges between two kept modules are included.
+func buildGraphView(allModules []ModuleInfo, g *graph.Graph, selected map[string]bool, searchFilter string) *moduleGraphView {
+	view := &moduleGraphView{Nodes: []ModuleInfo{}, Edges: []graphEdge{}}
+	kept := make(map[string]ModuleInfo)
+
+	for _, mod := range allModules {
+		if !selected[mod.Path] {
+			continue
+		}
+		if searchFilter != "" && !finder.MatchesWildcard(mod.Name, searchFilter) {
+			continue
+		}
+		kept[mod.Path] = mod
+		view.Nodes = append(view.Nodes, mod)
+	}
+
+	for _, mod := range view.Nodes {
+		for _, dep := range g.Dependencies(mod.Path) {
+			depMod, ok := kept[dep]
+			if !ok {
+				continue
+			}
+			view.Edges = append(view.Edges, graphEdge{
+				From:       mod.Path,
+				To:         dep,
+				SkipsLayer: skipsLayer(mod.Type, depMod.Type),
+			})
+		}
+	}
+
+	sort.Slice(view.Edges, func(i, j int) bool {
+		if view.Edges[i].From != view.Edges[j].From {
+			return view.Edges[i].From < view.Edges[j].From
+		}
+		return view.Edges[i].To < view.Edges[j].To
+	})
+
+	return view
+}
+
+// skipsLayer reports whether a module of type fromType sourcing a module of
+// type toType bypasses an intermediate layer (e.g. project -> component).
+func skipsLayer(fromType, toType string) bool {
+	fromOrder, fromOk := moduleTypeOrder(fromType)
+	toOrder, toOk := moduleTypeOrder(toType)
+	if !fromOk || !toOk {
+		return false
+	}
+	return fromOrder-toOrder > 1
+}
+
+// renderDOT writes the graph in Graphviz DOT format, clustering nodes by type
+func renderDOT(w io.Writer, view *moduleGraphView) error {
+	var b strings.Builder
+
+	b.WriteString("digraph motf {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for _, group := range groupNodesByType(view.Nodes) {
+		if group.typeName != "" {
+			fmt.Fprintf(&b, "\n  subgraph %q {\n", "cluster_"+group.typeName)
+			fmt.Fprintf(&b, "    label=%q;\n", group.typeName)
+			for _, mod := range group.modules {
+				fmt.Fprintf(&b, "    %q [label=%q];\n", mod.Path, mod.Name)
+			}
+			b.WriteString("  }\n")
+			continue
+		}
+		b.WriteString("\n")
+		for _, mod := range group.modules {
+			fmt.Fprintf(&b, "  %q [label=%q];\n", mod.Path, mod.Name)
+		}
+	}
+
+	if len(view.Edges) > 0 {
+		b.WriteString("\n")
+	}
+	for _, edge := range view.Edges {
+		if edge.SkipsLayer {
+			fmt.Fprintf(&b, "  %q -> %q [style=dashed, color=red];\n", edge.From, edge.To)
+		} else {
+			fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderMermaid writes the graph as a Mermaid flowchart, grouping nodes by type
+func renderMermaid(w io.Writer, view *moduleGraphView) error {
+	var b strings.Builder
+
+	// Mermaid node IDs must be simple identifiers, so map paths to indexes
+	ids := make(map[string]string, len(view.Nodes))
+	for i, mod := range view.Nodes {
+		ids[mod.Path] = fmt.Sprintf("m%d", i)
+	}
+
+	b.WriteString("graph LR\n")
+
+	for _, group := range groupNodesByType(view.Nodes) {
+		indent := "  "
+		if group.typeName != "" {
+			fmt.Fprintf(&b, "  subgraph %s\n", group.typeName)
+			indent = "    "
+		}
+		for _, mod := range group.modules {
+			fmt.Fprintf(&b, "%s%s[\"%s\"]\n", indent, ids[mod.Path], mod.Name)
+		}
+		if group.typeName != "" {
+			b.WriteString("  end\n")
+		}
+	}
+
+	for _, edge := range view.Edges {
+		arrow := "-->"
+		if edge.SkipsLayer {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderGraphJSON writes the graph as indented JSON
+func renderGraphJSON(w io.Writer, view *moduleGraphView) error {
+	output, err := json.MarshalIndent(view, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
+
+// nodeGroup is a set of modules sharing a type
+type nodeGroup struct {
+	typeName string
+	modules  []ModuleInfo
+}
+
+// groupNodesByType groups modules by type in module kind order, with untyped modules last
+func groupNodesByType(modules []ModuleInfo) []nodeGroup {
+	byType := make(map[string][]ModuleInfo)
+	for _, mod := range modules {
+		byType[mod.Type] = append(byType[mod.Type], mod)
+	}
+
+	typeNames := make([]string, 0, len(byType))
+	for typeName := range byType {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Slice(typeNames, func(i, j int) bool {
+		oi, iKnown := moduleTypeOrder(typeNames[i])
+		oj, jKnown := moduleTypeOrder(typeNames[j])
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return typeNames[i] < typeNames[j]
+	})
+
+	groups := make([]nodeGroup, 0, len(typeNames))
+	for _, typeName := range typeNames {
+		groups = append(groups, nodeGroup{typeName: typeName, modules: byType[typeName]})
+	}
+	return groups
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/graph"
+)
+
+func resetGraphFlags(t *testing.T) {
+	t.Helper()
+	resetFlags(t)
+	t.Cleanup(func() {
+		graphFormatFlag = GraphFormatDOT
+		graphReverseFlag = false
+	})
+}
+
+// testGraphView returns a view with project -> base -> component and a skip-layer edge project -> component
+func testGraphView() *moduleGraphView {
+	modules := []ModuleInfo{
+		{Name: "storage", Type: TypeComponent, Path: "components/storage"},
+		{Name: "app", Type: TypeBase, Path: "bases/app"},
+		{Name: "prod", Type: TypeProject, Path: "projects/prod"},
+	}
+
+	g := graph.New()
+	for _, mod := range modules {
+		g.AddNode(mod.Path)
+	}
+	g.AddEdge("bases/app", "components/storage")
+	g.AddEdge("projects/prod", "bases/app")
+	g.AddEdge("projects/prod", "components/storage")
+
+	selected := map[string]bool{"components/storage": true, "bases/app": true, "projects/prod": true}
+	return buildGraphView(modules, g, selected, "")
+}
+
+func TestGraphCmd_Flags(t *testing.T) {
+	format := graphCmd.Flags().Lookup("format")
+	if format == nil {
+		t.Fatal("graph command should have --format flag")
+	}
+	if format.DefValue != GraphFormatDOT {
+		t.Errorf("--format default = %q, want %q", format.DefValue, GraphFormatDOT)
+	}
+
+	for _, name := range []string{"reverse", "search", "changed", "ref", "dependents"} {
+		if graphCmd.Flags().Lookup(name) == nil {
+			t.Errorf("graph command should have --%s flag", name)
+		}
+	}
+}
+
+func TestSkipsLayer(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{TypeProject, TypeComponent, true},
+		{TypeProject, TypeBase, false},
+		{TypeBase, TypeComponent, false},
+		{TypeComponent, TypeComponent, false},
+		{"", TypeComponent, false},
+	}
+
+	for _, tt := range tests {
+		if got := skipsLayer(tt.from, tt.to); got != tt.want {
+			t.Errorf("skipsLayer(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestBuildGraphView(t *testing.T) {
+	view := testGraphView()
+
+	if len(view.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(view.Nodes))
+	}
+	if len(view.Edges) != 3 {
+		t.Fatalf("expected 3 edges, got %d: %v", len(view.Edges), view.Edges)
+	}
+
+	for _, edge := range view.Edges {
+		wantSkip := edge.From == "projects/prod" && edge.To == "components/storage"
+		if edge.SkipsLayer != wantSkip {
+			t.Errorf("edge %s -> %s SkipsLayer = %v, want %v", edge.From, edge.To, edge.SkipsLayer, wantSkip)
+		}
+	}
+}
+
+func TestBuildGraphView_SearchDropsEdges(t *testing.T) {
+	modules := []ModuleInfo{
+		{Name: "storage", Type: TypeComponent, Path: "components/storage"},
+		{Name: "app", Type: TypeBase, Path: "bases/app"},
+	}
+	g := graph.New()
+	g.AddEdge("bases/app", "components/storage")
+
+	selected := map[string]bool{"components/storage": true, "bases/app": true}
+	view := buildGraphView(modules, g, selected, "app")
+
+	if len(view.Nodes) != 1 || view.Nodes[0].Name != "app" {
+		t.Errorf("expected only 'app' node, got %v", view.Nodes)
+	}
+	if len(view.Edges) != 0 {
+		t.Errorf("expected no edges when dependency is filtered out, got %v", view.Edges)
+	}
+}
+
+func TestRenderDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderDOT(&buf, testGraphView()); err != nil {
+		t.Fatalf("renderDOT returned error: %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"digraph motf {",
+		`subgraph "cluster_component"`,
+		`"components/storage" [label="storage"];`,
+		`"bases/app" -> "components/storage";`,
+		`"projects/prod" -> "components/storage" [style=dashed, color=red];`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+
+	// Component cluster should come before project cluster
+	if strings.Index(out, "cluster_component") > strings.Index(out, "cluster_project") {
+		t.Errorf("expected component cluster before project cluster:\n%s", out)
+	}
+}
+
+func TestRenderMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderMermaid(&buf, testGraphView()); err != nil {
+		t.Fatalf("renderMermaid returned error: %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"graph LR",
+		"subgraph component",
+		`m0["storage"]`,
+		"m1 --> m0",
+		"m2 -.-> m0",
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderGraphJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderGraphJSON(&buf, testGraphView()); err != nil {
+		t.Fatalf("renderGraphJSON returned error: %v", err)
+	}
+
+	var view moduleGraphView
+	if err := json.Unmarshal(buf.Bytes(), &view); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(view.Nodes) != 3 || len(view.Edges) != 3 {
+		t.Errorf("expected 3 nodes and 3 edges, got %d and %d", len(view.Nodes), len(view.Edges))
+	}
+}
+
+func TestRunGraph_FocusedReverse(t *testing.T) {
+	resetGraphFlags(t)
+	tmpDir := setupDependencyTree(t)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	graphFormatFlag = GraphFormatJSON
+	graphReverseFlag = true
+
+	var buf bytes.Buffer
+	graphCmd.SetOut(&buf)
+	t.Cleanup(func() { graphCmd.SetOut(nil) })
+
+	if err := runGraph(graphCmd, []string{"storage"}); err != nil {
+		t.Fatalf("runGraph returned error: %v", err)
+	}
+
+	var view moduleGraphView
+	if err := json.Unmarshal(buf.Bytes(), &view); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	var names []string
+	for _, n := range view.Nodes {
+		names = append(names, n.Name)
+	}
+	// storage plus everything depending on it, sorted by path; "unrelated" is excluded
+	want := []string{"app", "storage", "prod"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("nodes = %v, want %v", names, want)
+	}
+	if len(view.Edges) != 2 {
+		t.Errorf("expected 2 edges, got %v", view.Edges)
+	}
+	if view.Edges[0].From != filepath.Join("bases", "app") {
+		t.Errorf("first edge should start at bases/app, got %v", view.Edges[0])
+	}
+}
+
+func TestRunGraph_InvalidFormat(t *testing.T) {
+	resetGraphFlags(t)
+	graphFormatFlag = "svg"
+
+	if err := runGraph(graphCmd, nil); err == nil {
+		t.Fatal("expected error for invalid format")
+	}
+}
+
+func TestRunGraph_ReverseRequiresModule(t *testing.T) {
+	resetGraphFlags(t)
+	graphReverseFlag = true
+
+	err := runGraph(graphCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--reverse") {
+		t.Fatalf("expected --reverse error, got %v", err)
+	}
+}
+
+func TestRunGraph_FocusOutsideModuleKinds(t *testing.T) {
+	resetGraphFlags(t)
+	resetFlags(t)
+	tmpDir := setupCustomKindsTree(t)
+	pathFlag = filepath.Join(tmpDir, "components", "ignored")
+
+	err := runGraph(graphCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "is not a module in modules, stacks, or live") {
+		t.Fatalf("expected the configured module kind directories in the error, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -88,20 +89,6 @@ func getBasePath() (string, error) {
 	return filepath.Join(wd, cfg.Root), nil
 }
 
-// getModuleType determines the module type based on its path
-func getModuleType(path string) string {
-	switch {
-	case strings.Contains(path, "/"+DirComponents+"/") || strings.Contains(path, "\\"+DirComponents+"\\"):
-		return TypeComponent
-	case strings.Contains(path, "/"+DirBases+"/") || strings.Contains(path, "\\"+DirBases+"\\"):
-		return TypeBase
-	case strings.Contains(path, "/"+DirProjects+"/") || strings.Contains(path, "\\"+DirProjects+"\\"):
-		return TypeProject
-	default:
-		return ""
-	}
-}
-
 // resolveTargetPath resolves the target path based on args and flags
 func resolveTargetPath(args []string) (string, error) {
 	// Check if both module name and --path are specified
@@ -142,48 +129,101 @@ func resolveExplicitPath(path string) (string, error) {
 	return absPath, nil
 }
 
-// findModuleInAllDirs searches for a module across all three directories (components, bases, projects)
+// discoveryRules returns the module discovery rules for basePath, built from the
+// discovery section of .motf.yml and basePath/.motfignore
+func discoveryRules(basePath string) (*finder.Rules, error) {
+	var exclude, include []string
+	if cfg != nil && cfg.Discovery != nil {
+		exclude, include = cfg.Discovery.Exclude, cfg.Discovery.Include
+	}
+	return finder.NewRules(basePath, exclude, include)
+}
+
+// findModuleInAllDirs searches for a module across the directories of all module kinds.
+// The name may be qualified with parent directories (azurerm/storage-account)
+// and prefixed with a module type (component:storage-account).
 func findModuleInAllDirs(moduleName string) (string, error) {
 	basePath, err := getBasePath()
 	if err != nil {
 		return "", err
 	}
 
-	var allMatches []string
-
-	for _, moduleDir := range ModuleDirs {
-		searchPath := filepath.Join(basePath, moduleDir)
+	index, err := moduleIndex(basePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to search for module: %w", err)
+	}
 
-		// Skip if directory doesn't exist
-		if _, err := os.Stat(searchPath); os.IsNotExist(err) {
-			continue
+	// Restrict the search to one module kind for type-prefixed names
+	name := moduleName
+	kinds := moduleKinds()
+	if moduleType, rest, ok := strings.Cut(moduleName, ":"); ok {
+		if err := validateModuleType(moduleType); err != nil {
+			return "", fmt.Errorf("invalid module name '%s': %w", moduleName, err)
 		}
+		order, _ := moduleTypeOrder(moduleType)
+		name, kinds = rest, kinds[order:order+1]
+	}
 
-		// Find the module
-		matches, err := finder.FindModule(searchPath, moduleName)
-		if err != nil {
-			return "", fmt.Errorf("failed to search for module in %s: %w", moduleDir, err)
-		}
+	var dirs []string
+	searchPaths := make(map[string]bool)
+	for _, kind := range kinds {
+		dirs = append(dirs, kind.Dir)
+		searchPaths[filepath.Join(basePath, kind.Dir)] = true
+	}
 
-		allMatches = append(allMatches, matches...)
+	var allMatches []string
+	for _, mod := range index.Lookup(name) {
+		if searchPaths[mod.SearchPath] {
+			allMatches = append(allMatches, mod.Path)
+		}
 	}
 
 	if len(allMatches) == 0 {
-		return "", fmt.Errorf("module '%s' not found in components, bases, or projects", moduleName)
+		msg := fmt.Sprintf("module '%s' not found in %s", moduleName, joinWithOr(dirs))
+		if suggestions := suggestModuleNames(basePath, name, index, dirs); len(suggestions) > 0 {
+			msg += "\n\nDid you mean:\n  " + strings.Join(suggestions, "\n  ")
+		}
+		return "", errors.New(msg)
 	}
 
 	if len(allMatches) > 1 {
 		// Name clash detected across multiple directories
+		qualified := finder.QualifiedNames(allMatches)
 		var paths string
 		for i, match := range allMatches {
-			paths += fmt.Sprintf("\n  %d. %s", i+1, match)
+			paths += fmt.Sprintf("\n  %d. %s (%s)", i+1, match, qualified[i])
 		}
-		return "", fmt.Errorf("multiple modules named '%s' found - name clash detected:%s\n\nPlease use --path to specify the exact path", moduleName, paths)
+		return "", fmt.Errorf("multiple modules named '%s' found - name clash detected:%s\n\nPlease use a qualified name (e.g. %s) or --path to specify the exact module", moduleName, paths, qualified[0])
 	}
 
 	return allMatches[0], nil
 }
 
+// suggestModuleNames returns the names of modules in dirs that are close to
+// name. Qualified names are compared with as many trailing path components.
+func suggestModuleNames(basePath, name string, index *finder.Index, dirs []string) []string {
+	depth := strings.Count(strings.Trim(filepath.ToSlash(name), "/"), "/") + 1
+
+	var modules []finder.IndexedModule
+	for _, dir := range dirs {
+		modules = append(modules, index.ModulesIn(filepath.Join(basePath, dir))...)
+	}
+
+	var candidates []string
+	for _, mod := range modules {
+		relPath, err := filepath.Rel(basePath, mod.Path)
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(filepath.ToSlash(relPath), "/")
+		if len(parts) < depth {
+			continue
+		}
+		candidates = append(candidates, strings.Join(parts[len(parts)-depth:], "/"))
+	}
+	return finder.Suggest(name, candidates, 3)
+}
+
 // resolveTargetWithExample resolves the target path, optionally switching to an example directory
 func resolveTargetWithExample(args []string, exampleName string) (string, error) {
 	modulePath, err := resolveTargetPath(args)
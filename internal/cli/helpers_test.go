@@ -3,6 +3,7 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
@@ -206,7 +207,7 @@ func TestFindModuleInAllDirs_ModuleNotFound(t *testing.T) {
 	withConfig(t, &config.Config{Root: "", Binary: "terraform"})
 	withWorkingDir(t, tmpDir)
 
-	for _, dir := range ModuleDirs {
+	for _, dir := range moduleDirs() {
 		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
 			t.Fatalf("failed to create %s directory: %v", dir, err)
 		}
@@ -260,6 +261,86 @@ func TestFindModuleInAllDirs_NameClash(t *testing.T) {
 	}
 }
 
+func TestFindModuleInAllDirs_QualifiedNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: "", Binary: "terraform"})
+	withWorkingDir(t, tmpDir)
+
+	azurerm := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "naming"))
+	aws := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "aws", "naming"))
+	base := createTerraformModule(t, tmpDir, filepath.Join(DirBases, "storage-account"))
+	component := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "storage-account"))
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"azurerm/naming", azurerm},
+		{"aws/naming", aws},
+		{"components/aws/naming", aws},
+		{"component:storage-account", component},
+		{"base:storage-account", base},
+		{"component:azurerm/storage-account", component},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findModuleInAllDirs(tt.name)
+			if err != nil {
+				t.Fatalf("findModuleInAllDirs returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("findModuleInAllDirs(%q) = %s, want %s", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	// A clash lists the qualified name of each module
+	_, err := findModuleInAllDirs("naming")
+	if err == nil || !strings.Contains(err.Error(), "(aws/naming)") || !strings.Contains(err.Error(), "(azurerm/naming)") {
+		t.Errorf("expected clash error with qualified names, got %v", err)
+	}
+
+	// Partial path components do not match
+	if _, err := findModuleInAllDirs("rm/naming"); err == nil {
+		t.Error("expected error for a partial path component")
+	}
+
+	_, err = findModuleInAllDirs("project:naming")
+	if err == nil || !strings.Contains(err.Error(), "not found in projects") {
+		t.Errorf("expected not found error for the project kind only, got %v", err)
+	}
+
+	_, err = findModuleInAllDirs("stack:naming")
+	if err == nil || !strings.Contains(err.Error(), "invalid module type 'stack'") {
+		t.Errorf("expected invalid module type error, got %v", err)
+	}
+}
+
+func TestFindModuleInAllDirs_Suggestions(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: "", Binary: "terraform"})
+	withWorkingDir(t, tmpDir)
+
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "key-vault"))
+
+	_, err := findModuleInAllDirs("storage-acount")
+	if err == nil || !strings.Contains(err.Error(), "Did you mean:\n  storage-account") {
+		t.Errorf("expected suggestion for storage-account, got %v", err)
+	}
+
+	_, err = findModuleInAllDirs("azurem/key-vault")
+	if err == nil || !strings.Contains(err.Error(), "Did you mean:\n  azurerm/key-vault") {
+		t.Errorf("expected qualified suggestion, got %v", err)
+	}
+
+	_, err = findModuleInAllDirs("network")
+	if err == nil || strings.Contains(err.Error(), "Did you mean") {
+		t.Errorf("expected no suggestions for an unrelated name, got %v", err)
+	}
+}
+
 // Tests for resolveTargetWithExample
 
 func TestResolveTargetWithExample_NoExample(t *testing.T) {
@@ -369,3 +450,21 @@ func TestResolveTargetWithExample_ExampleWithNonMainTf(t *testing.T) {
 		t.Errorf("expected '%s', got '%s'", examplePath, result)
 	}
 }
+
+func TestFindModuleInAllDirs_DiscoveryRules(t *testing.T) {
+	tmpDir := setupDiscoveryTree(t)
+
+	path, err := findModuleInAllDirs("subnet")
+	if err != nil {
+		t.Fatalf("findModuleInAllDirs returned error: %v", err)
+	}
+	if path != filepath.Join(tmpDir, "components", "network", "modules", "subnet") {
+		t.Errorf("unexpected path %s", path)
+	}
+
+	for _, name := range []string{"acme", "old-app"} {
+		if _, err := findModuleInAllDirs(name); err == nil {
+			t.Errorf("expected excluded module %s not to be found", name)
+		}
+	}
+}
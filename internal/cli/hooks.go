@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"context"
+	"io"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+// moduleHooks returns the hooks of .motf.yml run around the terraform/tofu
+// commands of a module, with the same environment (MOTF_GIT_ROOT set to
+// gitRoot) and execution policy as tasks. They are interrupted when ctx is done.
+func moduleHooks(ctx context.Context, gitRoot string) terraform.Hooks {
+	return func(stage, command, dir string, stdout, stderr io.Writer) error {
+		name := tasks.HookName(stage, command)
+		hooks := cfg.Hooks[name]
+		if len(hooks) == 0 {
+			return nil
+		}
+
+		env, err := buildTaskEnv(gitRoot, dir)
+		if err != nil {
+			return err
+		}
+		return tasks.NewRunner(nil, env).
+			WithContext(ctx).
+			WithPolicy(cfg.Execution.TaskPolicy(nil)).
+			RunHooks(name, hooks, dir, stdout, stderr)
+	}
+}
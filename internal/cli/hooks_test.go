@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+)
+
+func TestValCmd_Hooks(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	createTerraformModule(t, tmpDir, "components/broken")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Hooks: tasks.Hooks{
+		"pre_validate":  {{Command: `test "$MOTF_MODULE_NAME" != broken`}},
+		"post_validate": {{Command: `touch "validated-by-$MOTF_HOOK"`}},
+	}})
+	withFakeRunner(t, "#!/bin/sh\n")
+
+	jsonPath := filepath.Join(tmpDir, "report.json")
+	junitPath := filepath.Join(tmpDir, "junit.xml")
+	reportFlag = []string{"json=" + jsonPath, "junit=" + junitPath}
+	commandName = "validate"
+	allFlag = true
+
+	var runErr error
+	stdout := captureOutput(t, &os.Stdout, func() {
+		_ = captureOutput(t, &os.Stderr, func() {
+			runErr = valCmd.RunE(valCmd, nil)
+		})
+	})
+	if runErr == nil {
+		t.Fatal("expected the failing pre_validate hook to fail the run")
+	}
+	if !strings.Contains(stdout, "Running hook 'pre_validate' in ") {
+		t.Errorf("expected hooks in the output, got: %s", stdout)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "components", "storage", "validated-by-post_validate")); err != nil {
+		t.Errorf("expected post_validate to run on storage: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "components", "broken", "validated-by-post_validate")); err == nil {
+		t.Error("expected post_validate not to run after the failed pre_validate hook")
+	}
+
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("expected JSON report: %v", err)
+	}
+	var report runReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v\n%s", err, data)
+	}
+	broken, storage := report.Modules[0], report.Modules[1]
+	if len(broken.Hooks) != 1 || broken.Hooks[0].Name != "pre_validate" || broken.Hooks[0].Status != StatusFailed {
+		t.Errorf("expected the failed pre_validate hook in the report, got %+v", broken.Hooks)
+	}
+	if !strings.Contains(broken.Error, "hook 'pre_validate' failed: exit status 1") || broken.ExitCode == nil || *broken.ExitCode != 1 {
+		t.Errorf("unexpected entry of broken: %+v", broken)
+	}
+	if len(storage.Hooks) != 2 || storage.Hooks[1].Name != "post_validate" || storage.Hooks[1].Status != StatusPassed {
+		t.Errorf("expected both hooks of storage in the report, got %+v", storage.Hooks)
+	}
+
+	data, err = os.ReadFile(junitPath)
+	if err != nil {
+		t.Fatalf("expected JUnit report: %v", err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("report is not valid XML: %v\n%s", err, data)
+	}
+	properties := suites.Suites[0].Cases[0].Properties
+	if properties == nil || len(properties.Properties) != 1 || properties.Properties[0] != (junitProperty{Name: "hook.pre_validate", Value: StatusFailed}) {
+		t.Errorf("expected the hook as a test case property, got %+v", properties)
+	}
+}
+
+func TestModuleHooks_GitRoot(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Hooks: tasks.Hooks{
+		"pre_plan": {{Command: `echo "root=$MOTF_GIT_ROOT"`}},
+	}})
+
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(originalWd) }()
+
+	gitRoot, err := repoRoot()
+	if err != nil || gitRoot != "" {
+		t.Fatalf("expected no git root outside a git repository, got %q, %v", gitRoot, err)
+	}
+
+	var out bytes.Buffer
+	if err := moduleHooks(context.Background(), "/repo")("pre", "plan", tmpDir, &out, &out); err != nil {
+		t.Fatalf("expected the hook to succeed, got %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "root=/repo\n") {
+		t.Errorf("expected MOTF_GIT_ROOT from the command, got: %s", out.String())
+	}
+}
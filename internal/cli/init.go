@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"io"
 
 	"github.com/spf13/cobra"
@@ -8,23 +9,36 @@ import (
 
 // initCmd represents the init command
 var initCmd = &cobra.Command{
-	Use:   "init [module-name]",
-	Short: "Run terraform/tofu init on a component, base, or project",
-	Long: `Run terraform/tofu init on a component, base, or project.
+	Use:   "init [module-name...]",
+	Short: "Run terraform/tofu init on a module",
+	Long: `Run terraform/tofu init on a module: a component, base, or project by default,
+or a module of any kind configured in module_kinds.
 
 Use the --example/-e flag to run init on a specific example instead of the module itself.
 
+Several modules can be selected at once by passing multiple module names or
+using --all, --type, --search, --tag, or --changed. Selected modules run through the
+same pipeline as --changed (supporting --parallel and --dag).
+
 Examples:
   motf init storage-account              # Run init on storage-account module
-  motf init storage-account -e basic     # Run init on the 'basic' example`,
-	Args: cobra.MaximumNArgs(1),
+  motf init storage-account -e basic     # Run init on the 'basic' example
+  motf init --all -p                     # Run init on all modules in parallel
+  motf init --type project               # Run init on all projects`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if changedFlag {
-			if len(args) > 0 {
-				return cobra.MaximumNArgs(0)(cmd, args)
-			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
-				return runner.RunInitWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
+		gitRoot, err := repoRoot()
+		if err != nil {
+			return err
+		}
+
+		if usesModuleSelection(args) {
+			return runOnSelectedModulesWithPath(args, func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error {
+				tfRunner, err := moduleRunner(ctx, gitRoot, moduleAbsPath)
+				if err != nil {
+					return err
+				}
+				return tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
 			})
 		}
 
@@ -33,15 +47,35 @@ Examples:
 			return err
 		}
 
-		return runner.RunInit(targetPath, argsFlag...)
+		tfRunner, err := moduleRunner(runContext, gitRoot, targetPath)
+		if err != nil {
+			return err
+		}
+
+		return tfRunner.RunInit(targetPath, argsFlag...)
 	},
 }
 
 func init() {
 	initCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	initCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	initCmd.Flags().StringVar(&typeFlag, "type", "", "Only run on modules of this type, as configured in module_kinds (default: component, base, or project)")
+	initCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Only run on modules matching a wildcard (e.g., *storage*)")
+	initCmd.Flags().StringArrayVar(&tagFlag, "tag", []string{}, "Only run on modules with this tag (can be specified multiple times)")
 	initCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
 	initCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	initCmd.Flags().BoolVar(&dependentsFlag, "dependents", false, "Also include modules that transitively depend on changed modules (requires --changed)")
 	initCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
 	initCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	initCmd.Flags().BoolVar(&failFastFlag, "fail-fast", false, "Stop after the first failed module: cancel queued modules and interrupt running ones")
+	initCmd.Flags().IntVar(&maxFailuresFlag, "max-failures", 0, "Stop after this many modules have failed (default: no limit)")
+	initCmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Interrupt each command or task running longer than this, e.g. 20m (default: execution.timeout, no timeout)")
+	initCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry commands failing with an error matching execution.retry_on up to this many times (default: execution.retries)")
+	initCmd.Flags().StringVar(&outputModeFlag, "output-mode", "", "How to print output of multiple modules: stream, grouped, or quiet (default: stream)")
+	initCmd.Flags().StringVar(&ciFlag, "ci", "", "Format output for a CI system: github, gitlab, or none (default: detected from GITHUB_ACTIONS/GITLAB_CI)")
+	initCmd.Flags().BoolVar(&dagFlag, "dag", false, "Run modules in dependency order and skip dependents of failed modules")
+	initCmd.Flags().StringArrayVar(&reportFlag, "report", []string{}, "Write a report of the run: junit=<path> or json=<path> (can be specified multiple times)")
+	initCmd.Flags().StringVar(&logDirFlag, "log-dir", "", "Write each module's uncolored output to <dir>/<type>/<name>.log")
+	initCmd.Flags().BoolVar(&noSummaryFlag, "no-summary", false, "Don't print the summary table after running on several modules")
 	rootCmd.AddCommand(initCmd)
 }
@@ -3,9 +3,9 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
 	"github.com/TechnicallyJoe/terraform-motf/internal/spacelift"
@@ -21,10 +21,13 @@ var listNamesOnlyFlag bool
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
-	Short: "List all modules (components, bases, and projects)",
-	Long: `List all modules found in components, bases, and projects directories.
+	Short: "List all modules",
+	Long: `List all modules found in the module kind directories (components, bases, and
+projects by default; see module_kinds in .motf.yml).
 
 Use the --search/-s flag to filter modules using wildcards.
+Use the --type flag to list only modules of one kind (e.g. component).
+Use the --tag flag to list only modules tagged in their .motf.module.yml.
 Use the --changed flag to show only modules with changes compared to a git ref.
 Use the --json flag to output in JSON format for scripting.
 
@@ -32,24 +35,34 @@ Examples:
   motf list                        # List all modules
   motf list -s storage             # List modules containing "storage"
   motf list -s *account*           # List modules with "account" anywhere in the name
+  motf list --type component       # List only components
+  motf list --tag tofu-ready       # List modules tagged "tofu-ready"
   motf list --json                 # Output as JSON
   motf list --changed              # List only changed modules
   motf list --changed --ref HEAD~5 # List modules changed in last 5 commits
   motf list --changed --names      # Output only changed module names (for scripting)
-  motf list --changed -s storage   # List changed modules matching "storage"`,
+  motf list --changed -s storage   # List changed modules matching "storage"
+  motf list --changed --dependents # Include modules that depend on changed modules`,
 	RunE: runList,
 }
 
 func init() {
 	listCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Filter modules using wildcards (e.g., *storage*)")
+	listCmd.Flags().StringVar(&typeFlag, "type", "", "Only list modules of this type, as configured in module_kinds (default: component, base, or project)")
+	listCmd.Flags().StringArrayVar(&tagFlag, "tag", []string{}, "Only list modules with this tag (can be specified multiple times)")
 	listCmd.Flags().BoolVar(&listJsonFlag, "json", false, "Output in JSON format")
 	listCmd.Flags().BoolVar(&listNamesOnlyFlag, "names", false, "Output only module names (one per line)")
 	listCmd.Flags().BoolVar(&changedFlag, "changed", false, "List only modules changed compared to --ref")
 	listCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	listCmd.Flags().BoolVar(&dependentsFlag, "dependents", false, "Also include modules that transitively depend on changed modules (requires --changed)")
 	rootCmd.AddCommand(listCmd)
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if err := validateModuleType(typeFlag); err != nil {
+		return err
+	}
+
 	basePath, err := getBasePath()
 	if err != nil {
 		return err
@@ -64,17 +77,6 @@ func runList(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		// Apply search filter if specified
-		if searchFlag != "" && len(modules) > 0 {
-			var filtered []ModuleInfo
-			for _, mod := range modules {
-				if finder.MatchesWildcard(mod.Name, searchFlag) {
-					filtered = append(filtered, mod)
-				}
-			}
-			modules = filtered
-		}
-
 		// Populate version info for display
 		for i := range modules {
 			absPath := filepath.Join(basePath, modules[i].Path)
@@ -87,6 +89,13 @@ func runList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if err := applyModuleConfigs(basePath, modules); err != nil {
+		return err
+	}
+
+	// Apply type, search, and tag filters if specified
+	modules = filterModules(modules, typeFlag, searchFlag, tagFlag)
+
 	if len(modules) == 0 {
 		if listJsonFlag {
 			fmt.Println("[]")
@@ -97,6 +106,10 @@ func runList(cmd *cobra.Command, args []string) error {
 		}
 		if changedFlag {
 			fmt.Println("No changed modules found")
+		} else if typeFlag != "" {
+			fmt.Printf("No %s modules found\n", typeFlag)
+		} else if len(tagFlag) > 0 {
+			fmt.Printf("No modules found tagged '%s'\n", strings.Join(tagFlag, "', '"))
 		} else if searchFlag != "" {
 			fmt.Printf("No modules found matching '%s'\n", searchFlag)
 		} else {
@@ -122,42 +135,41 @@ func runList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// collectModules discovers all modules across components, bases, and projects directories
+// collectModules discovers all modules across the directories of all module kinds
 func collectModules(basePath, searchFilter string) ([]ModuleInfo, error) {
-	var allModules []ModuleInfo
+	index, err := moduleIndex(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list modules: %w", err)
+	}
 
-	for _, moduleDir := range ModuleDirs {
-		searchPath := filepath.Join(basePath, moduleDir)
+	var allModules []ModuleInfo
 
-		// Skip if directory doesn't exist
-		if _, err := os.Stat(searchPath); os.IsNotExist(err) {
-			continue
-		}
+	for _, kind := range moduleKinds() {
+		// Only the first module with a given name in each directory is listed
+		seen := make(map[string]bool)
 
-		// List all modules in this directory
-		modules, err := finder.ListAllModules(searchPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list modules in %s: %w", moduleDir, err)
-		}
+		for _, mod := range index.ModulesIn(filepath.Join(basePath, kind.Dir)) {
+			if seen[mod.Name] {
+				continue
+			}
+			seen[mod.Name] = true
 
-		// Process each module
-		for name, path := range modules {
 			// Apply search filter if specified
-			if searchFilter != "" && !finder.MatchesWildcard(name, searchFilter) {
+			if searchFilter != "" && !finder.MatchesWildcard(mod.Name, searchFilter) {
 				continue
 			}
 
 			// Make path relative to basePath
-			relativePath, err := filepath.Rel(basePath, path)
+			relativePath, err := filepath.Rel(basePath, mod.Path)
 			if err != nil {
-				relativePath = path // Fallback to full path if relative fails
+				relativePath = mod.Path // Fallback to full path if relative fails
 			}
 
 			allModules = append(allModules, ModuleInfo{
-				Name:    name,
-				Type:    getModuleType(path),
+				Name:    mod.Name,
+				Type:    kind.Type,
 				Path:    relativePath,
-				Version: spacelift.ReadModuleVersion(path),
+				Version: spacelift.ReadModuleVersion(mod.Path),
 			})
 		}
 	}
@@ -122,7 +122,7 @@ func TestCollectModules_EmptyResult(t *testing.T) {
 	cfg = &config.Config{Root: "", Binary: "terraform"}
 
 	// Create directories but no modules
-	for _, dir := range ModuleDirs {
+	for _, dir := range moduleDirs() {
 		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
 			t.Fatalf("failed to create directory: %v", err)
 		}
@@ -191,3 +191,42 @@ func TestSortModules_SingleElement(t *testing.T) {
 		t.Errorf("expected 'only', got '%s'", modules[0].Name)
 	}
 }
+
+// setupDiscoveryTree creates modules in directories named modules and vendor,
+// discovered according to the discovery section of the config
+func setupDiscoveryTree(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	createTerraformModule(t, tmpDir, "components/network")
+	createTerraformModule(t, tmpDir, "components/network/modules/subnet")
+	createTerraformModule(t, tmpDir, "components/vendor/acme")
+	createTerraformModule(t, tmpDir, "components/legacy/old-app")
+
+	withConfig(t, &config.Config{
+		Root:   tmpDir,
+		Binary: "terraform",
+		Discovery: &config.DiscoveryConfig{
+			Exclude: []string{"vendor/"},
+			Include: []string{"components/network/modules"},
+		},
+	})
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motfignore"), []byte("# retired\ncomponents/legacy\n"), 0644); err != nil {
+		t.Fatalf("failed to write .motfignore: %v", err)
+	}
+	return tmpDir
+}
+
+func TestCollectModules_DiscoveryRules(t *testing.T) {
+	tmpDir := setupDiscoveryTree(t)
+
+	modules, err := collectModules(tmpDir, "")
+	if err != nil {
+		t.Fatalf("collectModules returned error: %v", err)
+	}
+	sortModules(modules)
+
+	if got := moduleNames(modules); len(got) != 2 || got[0] != "network" || got[1] != "subnet" {
+		t.Errorf("collectModules() = %v, want [network subnet]", got)
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+)
+
+// logDirFlag is the directory --log-dir writes one log file per module to
+var logDirFlag string
+
+// ansiEscape matches ANSI escape sequences, such as the colors terraform/tofu
+// print even when their output is not a terminal
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// validateLogDirFlag checks --log-dir before any module runs. Like reports,
+// logs are written for module selections.
+func validateLogDirFlag(args []string) error {
+	if logDirFlag == "" {
+		return nil
+	}
+	if pathFlag != "" || exampleFlag != "" {
+		return fmt.Errorf("--log-dir cannot be used with --path or --example")
+	}
+	if !usesModuleSelection(args) {
+		return fmt.Errorf("--log-dir requires module names or a selector (--all, --changed, --type, --search, --tag)")
+	}
+	return nil
+}
+
+// moduleLogs writes the output of each module of a run to its own file
+type moduleLogs struct {
+	files map[string]string // module path -> log file path
+}
+
+// newModuleLogs returns the log files of modules under dir, at
+// <type>/<name>.log. Modules of the same type and name are told apart by
+// their qualified name, e.g. component/azurerm/naming.log.
+func newModuleLogs(dir string, modules []ModuleInfo) *moduleLogs {
+	byType := make(map[string][]string)
+	for _, mod := range modules {
+		byType[mod.Type] = append(byType[mod.Type], mod.Path)
+	}
+
+	files := make(map[string]string, len(modules))
+	for moduleType, paths := range byType {
+		for i, name := range finder.QualifiedNames(paths) {
+			files[paths[i]] = filepath.Join(dir, moduleType, filepath.FromSlash(name)+".log")
+		}
+	}
+	return &moduleLogs{files: files}
+}
+
+// create creates the log file of mod, replacing the log of a previous run
+func (l *moduleLogs) create(mod ModuleInfo) (*os.File, error) {
+	path := l.files[mod.Path]
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.Create(path) //nolint:gosec // path is inside the --log-dir given by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	return file, nil
+}
+
+// plainWriter writes to w with ANSI escape sequences removed. It is given
+// whole lines, so escape sequences are never split across writes.
+type plainWriter struct {
+	w io.Writer
+}
+
+// Write implements io.Writer
+func (p plainWriter) Write(b []byte) (int, error) {
+	if _, err := p.w.Write(ansiEscape.ReplaceAll(b, nil)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
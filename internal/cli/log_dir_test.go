@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// fakeColorBinary prints colored output to stdout and stderr, and fails in "broken"
+const fakeColorBinary = `#!/bin/sh
+printf '\033[1m\033[32mSuccess!\033[0m The configuration is valid.\n'
+if [ "$(basename "$PWD")" = "broken" ]; then
+  printf '\033[31mError: Unsupported argument\033[0m\n' >&2
+  exit 1
+fi
+`
+
+func TestNewModuleLogs(t *testing.T) {
+	logs := newModuleLogs("logs", []ModuleInfo{
+		{Name: "naming", Type: TypeComponent, Path: "components/azurerm/naming"},
+		{Name: "naming", Type: TypeComponent, Path: "components/aws/naming"},
+		{Name: "naming", Type: TypeBase, Path: "bases/naming"},
+		{Name: "app", Type: TypeProject, Path: "projects/app"},
+	})
+
+	want := map[string]string{
+		"components/azurerm/naming": filepath.Join("logs", "component", "azurerm", "naming.log"),
+		"components/aws/naming":     filepath.Join("logs", "component", "aws", "naming.log"),
+		"bases/naming":              filepath.Join("logs", "base", "naming.log"),
+		"projects/app":              filepath.Join("logs", "project", "app.log"),
+	}
+	for path, file := range want {
+		if got := logs.files[path]; got != file {
+			t.Errorf("log file of %s = %q, want %q", path, got, file)
+		}
+	}
+}
+
+func TestPlainWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := plainWriter{w: &buf}
+	line := "\033[1m\033[32mSuccess!\033[0m done \033[?25l\n"
+	if n, err := w.Write([]byte(line)); err != nil || n != len(line) {
+		t.Fatalf("Write() = %d, %v", n, err)
+	}
+	if buf.String() != "Success! done \n" {
+		t.Errorf("expected escape sequences to be removed, got %q", buf.String())
+	}
+}
+
+func TestValidateLogDirFlag(t *testing.T) {
+	resetFlags(t)
+	logDirFlag = "logs"
+
+	if err := validateLogDirFlag([]string{"storage"}); err != nil {
+		t.Errorf("expected a module name to be accepted, got %v", err)
+	}
+	if err := validateLogDirFlag(nil); err == nil || !strings.Contains(err.Error(), "--log-dir requires module names or a selector") {
+		t.Errorf("expected selection error, got %v", err)
+	}
+	pathFlag = "components/storage"
+	if err := validateLogDirFlag(nil); err == nil || !strings.Contains(err.Error(), "--path") {
+		t.Errorf("expected --path error, got %v", err)
+	}
+}
+
+func TestRunWithLogDir(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	createTerraformModule(t, tmpDir, "components/broken")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	withFakeRunner(t, fakeColorBinary)
+
+	logDir := filepath.Join(t.TempDir(), "logs")
+	logDirFlag = logDir
+	allFlag = true
+	parallelFlag = true
+	maxParallelFlag = 2
+
+	var runErr error
+	stdout := captureOutput(t, &os.Stdout, func() {
+		_ = captureOutput(t, &os.Stderr, func() {
+			runErr = valCmd.RunE(valCmd, nil)
+		})
+	})
+	if runErr == nil {
+		t.Fatal("expected validate to fail for broken")
+	}
+	if !strings.Contains(stdout, "storage |") {
+		t.Errorf("expected the prefixed console stream as well, got: %s", stdout)
+	}
+
+	data, err := os.ReadFile(filepath.Join(logDir, TypeComponent, "broken.log"))
+	if err != nil {
+		t.Fatalf("expected a log file for broken: %v", err)
+	}
+	log := string(data)
+	if !strings.HasPrefix(log, "Running ") || !strings.Contains(log, "\nSuccess! The configuration is valid.\nError: Unsupported argument\n") {
+		t.Errorf("expected plain stdout and stderr in the log, got %q", log)
+	}
+	if strings.Contains(log, "\033") || strings.Contains(log, "broken |") {
+		t.Errorf("expected no colors or prefixes in the log, got %q", log)
+	}
+
+	if _, err := os.Stat(filepath.Join(logDir, TypeComponent, "storage.log")); err != nil {
+		t.Errorf("expected a log file for storage: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+// moduleConfigDir returns the directory holding the .motf.module.yml that applies
+// to dir. Examples (module/examples/<name>) use the metadata of their module.
+func moduleConfigDir(dir string) string {
+	parent := filepath.Dir(dir)
+	if filepath.Base(parent) == DirExamples {
+		return filepath.Dir(parent)
+	}
+	return dir
+}
+
+// moduleRunner returns the runner for the module or example at dir, with the
+// binary and test overrides from the module's .motf.module.yml applied and the
+// hooks of .motf.yml run around its commands, with MOTF_GIT_ROOT set to
+// gitRoot. Its commands are interrupted when ctx is done.
+func moduleRunner(ctx context.Context, gitRoot, dir string) (*terraform.Runner, error) {
+	mc, err := config.LoadModuleConfig(moduleConfigDir(dir))
+	if err != nil {
+		return nil, err
+	}
+	r := runner.ForModule(mc).WithContext(ctx)
+	if cfg != nil && len(cfg.Hooks) > 0 {
+		r = r.WithHooks(moduleHooks(ctx, gitRoot))
+	}
+	return r, nil
+}
+
+// applyModuleConfigs fills in the metadata of each module from its .motf.module.yml
+func applyModuleConfigs(basePath string, modules []ModuleInfo) error {
+	for i := range modules {
+		mc, err := config.LoadModuleConfig(filepath.Join(basePath, modules[i].Path))
+		if err != nil {
+			return err
+		}
+		if mc == nil {
+			continue
+		}
+		modules[i].Description = mc.Description
+		modules[i].Lifecycle = mc.Lifecycle
+		modules[i].Tags = mc.Tags
+		modules[i].Owners = mc.Owners
+	}
+	return nil
+}
+
+// hasAllTags reports whether mod is tagged with every tag in tags
+func hasAllTags(mod ModuleInfo, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, t := range mod.Tags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// writeModuleMetadata writes a .motf.module.yml into modulePath
+func writeModuleMetadata(t *testing.T, modulePath, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(modulePath, config.ModuleFileName), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", config.ModuleFileName, err)
+	}
+}
+
+func TestModuleConfigDir(t *testing.T) {
+	module := filepath.Join("repo", "components", "storage-account")
+
+	if got := moduleConfigDir(module); got != module {
+		t.Errorf("moduleConfigDir(module) = %q, want %q", got, module)
+	}
+	if got := moduleConfigDir(filepath.Join(module, DirExamples, "basic")); got != module {
+		t.Errorf("moduleConfigDir(example) = %q, want %q", got, module)
+	}
+}
+
+func TestSelectModules_Tags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"single tag", []string{"storage"}, []string{"key-vault", "storage-account"}},
+		{"all tags must match", []string{"storage", "tofu-ready"}, []string{"storage-account"}},
+		{"unknown tag", []string{"network"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			tmpDir := setupSelectionTree(t)
+			writeModuleMetadata(t, filepath.Join(tmpDir, "components", "storage-account"), "tags: [storage, tofu-ready]\n")
+			writeModuleMetadata(t, filepath.Join(tmpDir, "components", "key-vault"), "tags: [storage]\n")
+			tagFlag = tt.tags
+
+			modules, err := selectModules(nil)
+			if err != nil {
+				t.Fatalf("selectModules returned error: %v", err)
+			}
+			if got := moduleNames(modules); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectModules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectModules_LoadsMetadata(t *testing.T) {
+	resetFlags(t)
+	tmpDir := setupSelectionTree(t)
+	writeModuleMetadata(t, filepath.Join(tmpDir, "bases", "app"), `description: Application base
+lifecycle: experimental
+owners: ["@app-team"]
+tags: [app]
+`)
+
+	modules, err := selectModules([]string{"app", "prod"})
+	if err != nil {
+		t.Fatalf("selectModules returned error: %v", err)
+	}
+
+	app := modules[0]
+	if app.Description != "Application base" || app.Lifecycle != config.LifecycleExperimental ||
+		!reflect.DeepEqual(app.Owners, []string{"@app-team"}) || !reflect.DeepEqual(app.Tags, []string{"app"}) {
+		t.Errorf("metadata not loaded: %+v", app)
+	}
+	if prod := modules[1]; prod.Lifecycle != "" || prod.Tags != nil {
+		t.Errorf("expected no metadata for prod, got %+v", prod)
+	}
+}
+
+func TestSelectModules_InvalidMetadata(t *testing.T) {
+	resetFlags(t)
+	tmpDir := setupSelectionTree(t)
+	writeModuleMetadata(t, filepath.Join(tmpDir, "projects", "prod"), "lifecycle: retired\n")
+	allFlag = true
+
+	_, err := selectModules(nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid lifecycle 'retired'") {
+		t.Fatalf("expected invalid lifecycle error, got %v", err)
+	}
+}
+
+func TestModuleRunner_Overrides(t *testing.T) {
+	resetFlags(t)
+	tmpDir := setupSelectionTree(t)
+	withFakeRunner(t, "#!/bin/sh\n")
+
+	modulePath := filepath.Join(tmpDir, "components", "storage-account")
+	writeModuleMetadata(t, modulePath, "binary: tofu\n")
+
+	for _, dir := range []string{modulePath, filepath.Join(modulePath, DirExamples, "basic")} {
+		tfRunner, err := moduleRunner(context.Background(), "", dir)
+		if err != nil {
+			t.Fatalf("moduleRunner(%s) returned error: %v", dir, err)
+		}
+		if tfRunner.Binary() != "tofu" {
+			t.Errorf("moduleRunner(%s).Binary() = %q, want 'tofu'", dir, tfRunner.Binary())
+		}
+	}
+
+	tfRunner, err := moduleRunner(context.Background(), "", filepath.Join(tmpDir, "components", "key-vault"))
+	if err != nil {
+		t.Fatalf("moduleRunner returned error: %v", err)
+	}
+	if tfRunner.Binary() != runner.Binary() {
+		t.Errorf("expected the shared runner's binary for a module without overrides, got %q", tfRunner.Binary())
+	}
+}
+
+func TestBuildTaskEnv_ModuleBinary(t *testing.T) {
+	resetFlags(t)
+	tmpDir := setupSelectionTree(t)
+	modulePath := filepath.Join(tmpDir, "components", "storage-account")
+	writeModuleMetadata(t, modulePath, "binary: tofu\n")
+
+	env, err := buildTaskEnv(tmpDir, modulePath)
+	if err != nil {
+		t.Fatalf("buildTaskEnv returned error: %v", err)
+	}
+	found := false
+	for _, e := range env {
+		if e == "MOTF_BINARY=tofu" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected MOTF_BINARY=tofu in env, got %v", env)
+	}
+}
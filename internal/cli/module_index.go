@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+)
+
+// moduleIndex returns the index of all modules in the module kind directories
+// under basePath, reusing the cached index when nothing changed since it was built.
+func moduleIndex(basePath string) (*finder.Index, error) {
+	rules, err := discoveryRules(basePath)
+	if err != nil {
+		return nil, err
+	}
+	return rules.CachedIndex(moduleIndexPath(basePath), moduleSearchPaths(basePath))
+}
+
+// moduleSearchPaths returns the directories of all module kinds under basePath
+func moduleSearchPaths(basePath string) []string {
+	dirs := moduleDirs()
+	paths := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		paths = append(paths, filepath.Join(basePath, dir))
+	}
+	return paths
+}
+
+// moduleIndexPath returns where the module index for basePath is cached, in
+// the motf data directory (see motfDataDir). It returns "" (no caching) if
+// there is none.
+func moduleIndexPath(basePath string) string {
+	dir := motfDataDir(basePath)
+	if dir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(basePath))
+	return filepath.Join(dir, "modules-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// motfDataDir returns the directory motf keeps caches for basePath in:
+// motf/ in the git directory of the enclosing repository, or
+// $XDG_CACHE_HOME/motf/ outside of one. It returns "" if neither is available.
+func motfDataDir(basePath string) string {
+	for dir := basePath; ; {
+		if gitDir := gitCommonDir(filepath.Join(dir, ".git")); gitDir != "" {
+			return filepath.Join(gitDir, "motf")
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	if cacheHome := os.Getenv("XDG_CACHE_HOME"); cacheHome != "" {
+		return filepath.Join(cacheHome, "motf")
+	}
+	return ""
+}
+
+// gitCommonDir returns the git directory shared by all worktrees of the
+// repository whose .git entry is at gitPath, or "" if there is none. In linked
+// worktrees and submodules .git is a file whose 'gitdir:' line points to the
+// actual git directory; a worktree's git directory in turn names the common
+// one in its commondir file.
+func gitCommonDir(gitPath string) string {
+	info, err := os.Stat(gitPath)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return gitPath
+	}
+
+	content, err := os.ReadFile(gitPath) //nolint:gosec // gitPath is the .git file of the enclosing repository
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitDir = resolveGitPath(filepath.Dir(gitPath), strings.TrimSpace(gitDir))
+
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil { //nolint:gosec // commondir is part of the repository's git directory
+		gitDir = resolveGitPath(gitDir, strings.TrimSpace(string(common)))
+	}
+	if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
+		return ""
+	}
+	return gitDir
+}
+
+// resolveGitPath resolves path, as written in a git pointer file, relative to dir
+func resolveGitPath(dir, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestModuleIndexPath(t *testing.T) {
+	t.Run("inside a git repository", func(t *testing.T) {
+		repo := t.TempDir()
+		if err := os.Mkdir(filepath.Join(repo, ".git"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		basePath := filepath.Join(repo, "iac")
+
+		got := moduleIndexPath(basePath)
+		if filepath.Dir(got) != filepath.Join(repo, ".git", "motf") {
+			t.Errorf("moduleIndexPath() = %s, want a file in .git/motf", got)
+		}
+		if got == moduleIndexPath(repo) {
+			t.Error("expected different cache files for different base paths")
+		}
+	})
+
+	t.Run("inside a linked worktree", func(t *testing.T) {
+		root := t.TempDir()
+		worktreeGitDir := filepath.Join(root, "repo", ".git", "worktrees", "feature")
+		if err := os.MkdirAll(worktreeGitDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(worktreeGitDir, "commondir"), []byte("../..\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		worktree := filepath.Join(root, "feature")
+		if err := os.Mkdir(worktree, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: ../repo/.git/worktrees/feature\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got := moduleIndexPath(filepath.Join(worktree, "iac"))
+		if filepath.Dir(got) != filepath.Join(root, "repo", ".git", "motf") {
+			t.Errorf("moduleIndexPath() = %s, want a file in the main repository's .git/motf", got)
+		}
+	})
+
+	t.Run("inside a submodule", func(t *testing.T) {
+		root := t.TempDir()
+		moduleGitDir := filepath.Join(root, ".git", "modules", "infra")
+		if err := os.MkdirAll(moduleGitDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		submodule := filepath.Join(root, "infra")
+		if err := os.Mkdir(submodule, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(submodule, ".git"), []byte("gitdir: ../.git/modules/infra\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got := moduleIndexPath(submodule)
+		if filepath.Dir(got) != filepath.Join(moduleGitDir, "motf") {
+			t.Errorf("moduleIndexPath() = %s, want a file in the submodule's git directory", got)
+		}
+	})
+
+	t.Run("outside a git repository", func(t *testing.T) {
+		cacheHome := t.TempDir()
+		t.Setenv("XDG_CACHE_HOME", cacheHome)
+
+		got := moduleIndexPath(t.TempDir())
+		if filepath.Dir(got) != filepath.Join(cacheHome, "motf") {
+			t.Errorf("moduleIndexPath() = %s, want a file in $XDG_CACHE_HOME/motf", got)
+		}
+
+		t.Setenv("XDG_CACHE_HOME", "")
+		if got := moduleIndexPath(t.TempDir()); got != "" {
+			t.Errorf("expected no cache without a repository or XDG_CACHE_HOME, got %s", got)
+		}
+	})
+}
+
+func TestModuleIndex_UsesCacheInRepository(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	createTerraformModule(t, tmpDir, "components/storage-account")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	// Backdate the tree so the index is not considered racy and gets cached
+	backdateTree(t, tmpDir)
+
+	index, err := moduleIndex(tmpDir)
+	if err != nil {
+		t.Fatalf("moduleIndex returned error: %v", err)
+	}
+	if got := index.Lookup("storage-account"); len(got) != 1 {
+		t.Fatalf("Lookup(storage-account) = %v", got)
+	}
+
+	data, err := os.ReadFile(moduleIndexPath(tmpDir))
+	if err != nil {
+		t.Fatalf("expected module index in .git/motf: %v", err)
+	}
+	if !strings.Contains(string(data), "storage-account") {
+		t.Errorf("expected cached index to list storage-account, got %s", data)
+	}
+
+	// A module added later invalidates the cached index
+	createTerraformModule(t, tmpDir, "components/key-vault")
+	path, err := findModuleInAllDirs("key-vault")
+	if err != nil {
+		t.Fatalf("findModuleInAllDirs returned error: %v", err)
+	}
+	if path != filepath.Join(tmpDir, "components", "key-vault") {
+		t.Errorf("unexpected path %s", path)
+	}
+}
+
+// backdateTree sets the mtime of every directory below root to an hour ago
+func backdateTree(t *testing.T, root string) {
+	t.Helper()
+	past := time.Now().Add(-time.Hour)
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		return os.Chtimes(path, past, past)
+	})
+	if err != nil {
+		t.Fatalf("failed to backdate %s: %v", root, err)
+	}
+}
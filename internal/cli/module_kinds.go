@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// moduleKinds returns the configured module kinds sorted by order, or the
+// default components/bases/projects kinds when none are configured.
+func moduleKinds() []config.ModuleKind {
+	if cfg == nil || len(cfg.ModuleKinds) == 0 {
+		return config.DefaultModuleKinds()
+	}
+	return cfg.ModuleKinds
+}
+
+// moduleDirs returns the directories of all module kinds, relative to root
+func moduleDirs() []string {
+	kinds := moduleKinds()
+	dirs := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		dirs = append(dirs, kind.Dir)
+	}
+	return dirs
+}
+
+// moduleTypeNames returns the known module types in sort order
+func moduleTypeNames() []string {
+	kinds := moduleKinds()
+	names := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		names = append(names, kind.Type)
+	}
+	return names
+}
+
+// moduleTypeOrder returns the position of moduleType among the module kinds
+// (0 for the lowest kind), and false if the type is unknown. Positions are
+// used rather than the configured order values so adjacent kinds always
+// differ by one.
+func moduleTypeOrder(moduleType string) (int, bool) {
+	for i, kind := range moduleKinds() {
+		if kind.Type == moduleType {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// topModuleType returns the type of the highest-ordered module kind ("project"
+// by default). These are the deployable root modules that drift checks by
+// default and destroy asks to confirm.
+func topModuleType() string {
+	kinds := moduleKinds()
+	return kinds[len(kinds)-1].Type
+}
+
+// getModuleType determines the module type from the kind directory the module
+// lives in. Paths under root are matched by their leading directories; other
+// paths fall back to looking for a kind directory anywhere in the path.
+func getModuleType(path string) string {
+	kinds := moduleKinds()
+
+	if cfg != nil {
+		if basePath, err := getBasePath(); err == nil {
+			if rel, err := filepath.Rel(basePath, path); err == nil {
+				rel = filepath.ToSlash(rel)
+				for _, kind := range kinds {
+					if strings.HasPrefix(rel, kind.Dir+"/") {
+						return kind.Type
+					}
+				}
+			}
+		}
+	}
+
+	normalized := strings.ReplaceAll(path, "\\", "/")
+	for _, kind := range kinds {
+		if strings.Contains(normalized, "/"+kind.Dir+"/") {
+			return kind.Type
+		}
+	}
+	return ""
+}
+
+// joinWithOr formats values as "a, b, or c"
+func joinWithOr(values []string) string {
+	if len(values) <= 2 {
+		return strings.Join(values, " or ")
+	}
+	return strings.Join(values[:len(values)-1], ", ") + ", or " + values[len(values)-1]
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// setupCustomKindsTree creates a modules/stacks/live layout with one module per kind
+func setupCustomKindsTree(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	createTerraformModule(t, tmpDir, "modules/network")
+	createTerraformModule(t, tmpDir, "stacks/platform")
+	createTerraformModule(t, tmpDir, "live/prod")
+	// Directories of the default layout are ignored once kinds are configured
+	createTerraformModule(t, tmpDir, "components/ignored")
+
+	withConfig(t, &config.Config{
+		Root:   tmpDir,
+		Binary: "terraform",
+		ModuleKinds: []config.ModuleKind{
+			{Dir: "modules", Type: "module", Order: 1},
+			{Dir: "stacks", Type: "stack", Order: 2},
+			{Dir: "live", Type: "live", Order: 3},
+		},
+	})
+	return tmpDir
+}
+
+func TestCollectModules_CustomKinds(t *testing.T) {
+	tmpDir := setupCustomKindsTree(t)
+
+	modules, err := collectModules(tmpDir, "")
+	if err != nil {
+		t.Fatalf("collectModules returned error: %v", err)
+	}
+	sortModules(modules)
+
+	got := make(map[string]string)
+	for _, mod := range modules {
+		got[mod.Name] = mod.Type
+	}
+	want := map[string]string{"network": "module", "platform": "stack", "prod": "live"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectModules() types = %v, want %v", got, want)
+	}
+}
+
+func TestSelectModules_CustomKindType(t *testing.T) {
+	resetFlags(t)
+	setupCustomKindsTree(t)
+
+	typeFlag = "stack"
+	modules, err := selectModules(nil)
+	if err != nil {
+		t.Fatalf("selectModules returned error: %v", err)
+	}
+	if got := moduleNames(modules); !reflect.DeepEqual(got, []string{"platform"}) {
+		t.Errorf("selectModules() = %v, want [platform]", got)
+	}
+
+	typeFlag = TypeComponent
+	_, err = selectModules(nil)
+	if err == nil || !strings.Contains(err.Error(), "must be one of module, stack, live") {
+		t.Errorf("expected error listing configured types, got %v", err)
+	}
+}
+
+func TestFindModuleInAllDirs_CustomKinds(t *testing.T) {
+	tmpDir := setupCustomKindsTree(t)
+
+	path, err := findModuleInAllDirs("platform")
+	if err != nil {
+		t.Fatalf("findModuleInAllDirs returned error: %v", err)
+	}
+	if path != filepath.Join(tmpDir, "stacks", "platform") {
+		t.Errorf("unexpected path %s", path)
+	}
+
+	_, err = findModuleInAllDirs("ignored")
+	if err == nil || !strings.Contains(err.Error(), "not found in modules, stacks, or live") {
+		t.Errorf("expected not found error listing configured dirs, got %v", err)
+	}
+}
+
+func TestGetModuleType_MatchesRelativeToRoot(t *testing.T) {
+	// The root itself lives under a directory named like a default kind
+	tmpDir := filepath.Join(t.TempDir(), "projects", "repo")
+	createTerraformModule(t, tmpDir, "components/storage")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	if got := getModuleType(filepath.Join(tmpDir, "components", "storage")); got != TypeComponent {
+		t.Errorf("getModuleType() = %q, want %q", got, TypeComponent)
+	}
+}
+
+func TestTopModuleType(t *testing.T) {
+	withConfig(t, &config.Config{})
+	if got := topModuleType(); got != TypeProject {
+		t.Errorf("topModuleType() = %q, want %q", got, TypeProject)
+	}
+
+	setupCustomKindsTree(t)
+	if got := topModuleType(); got != "live" {
+		t.Errorf("topModuleType() = %q, want 'live'", got)
+	}
+}
+
+func TestCheckProjectDestroy_CustomKinds(t *testing.T) {
+	resetFlags(t)
+	setupCustomKindsTree(t)
+
+	err := checkProjectDestroy([]ModuleInfo{{Name: "prod", Type: "live"}, {Name: "platform", Type: "stack"}})
+	if err == nil || !strings.Contains(err.Error(), "refusing to destroy live module(s) prod") {
+		t.Errorf("expected live modules to require confirmation, got %v", err)
+	}
+}
+
+func TestSkipsLayer_CustomKinds(t *testing.T) {
+	withConfig(t, &config.Config{ModuleKinds: []config.ModuleKind{
+		{Dir: "modules", Type: "module", Order: 10},
+		{Dir: "stacks", Type: "stack", Order: 20},
+		{Dir: "live", Type: "live", Order: 30},
+	}})
+
+	if skipsLayer("stack", "module") {
+		t.Error("stack -> module should not skip a layer")
+	}
+	if !skipsLayer("live", "module") {
+		t.Error("live -> module should skip a layer")
+	}
+}
+
+func TestJoinWithOr(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{[]string{"a"}, "a"},
+		{[]string{"a", "b"}, "a or b"},
+		{[]string{"a", "b", "c"}, "a, b, or c"},
+	}
+	for _, tt := range tests {
+		if got := joinWithOr(tt.values); got != tt.want {
+			t.Errorf("joinWithOr(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
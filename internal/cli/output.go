@@ -19,6 +19,25 @@ const (
 	colorRed     = "\033[31m"
 )
 
+// Output modes for multi-module runs, selected with --output-mode
+const (
+	OutputModeStream  = "stream"  // print lines as they arrive, interleaved across modules
+	OutputModeGrouped = "grouped" // print each module's output as one block when it finishes
+	OutputModeQuiet   = "quiet"   // print only the output of failed modules
+)
+
+// outputModeFlag selects how module output is printed; empty means stream
+var outputModeFlag string
+
+// validateOutputMode returns an error if mode is set but not a known output mode
+func validateOutputMode(mode string) error {
+	switch mode {
+	case "", OutputModeStream, OutputModeGrouped, OutputModeQuiet:
+		return nil
+	}
+	return fmt.Errorf("invalid output mode '%s': must be '%s', '%s', or '%s'", mode, OutputModeStream, OutputModeGrouped, OutputModeQuiet)
+}
+
 // colorPalette is the rotating list of colors for module prefixes
 var colorPalette = []string{
 	colorCyan,
@@ -44,6 +63,7 @@ type prefixedWriter struct {
 	buf        bytes.Buffer
 	timeFunc   func() time.Time // for testing
 	linePrefix string           // cached formatted prefix without timestamp
+	log        io.Writer        // optional; receives each line without prefix and timestamp
 }
 
 // newPrefixedWriter creates a new prefixedWriter.
@@ -109,6 +129,9 @@ func (w *prefixedWriter) writeLine(line []byte) error {
 
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	if w.log != nil {
+		_, _ = w.log.Write(line)
+	}
 	_, err := w.out.Write([]byte(formatted))
 	return err
 }
@@ -127,6 +150,13 @@ func newPrefixedWriterPair(moduleName string, maxNameLen int, colorIndex int, st
 	}
 }
 
+// logTo also writes every line of stdout and stderr to log, without prefix
+// and timestamp. Lines are written to log in the order they are printed.
+func (p *prefixedWriterPair) logTo(log io.Writer) {
+	p.stdout.log = log
+	p.stderr.log = log
+}
+
 // Flush flushes both stdout and stderr writers
 func (p *prefixedWriterPair) Flush() error {
 	if err := p.stdout.Flush(); err != nil {
@@ -134,3 +164,66 @@ func (p *prefixedWriterPair) Flush() error {
 	}
 	return p.stderr.Flush()
 }
+
+// moduleOutput buffers a module's stdout and stderr in the order they were
+// written, so they can be printed later as one contiguous block
+type moduleOutput struct {
+	mu     sync.Mutex
+	chunks []outputChunk
+}
+
+// outputChunk is a piece of buffered output and the stream it was written to
+type outputChunk struct {
+	stderr bool
+	data   []byte
+}
+
+// moduleOutputStream is an io.Writer appending to one stream of a moduleOutput
+type moduleOutputStream struct {
+	output *moduleOutput
+	stderr bool
+}
+
+// Write implements io.Writer
+func (s *moduleOutputStream) Write(p []byte) (int, error) {
+	s.output.mu.Lock()
+	defer s.output.mu.Unlock()
+	s.output.chunks = append(s.output.chunks, outputChunk{stderr: s.stderr, data: append([]byte(nil), p...)})
+	return len(p), nil
+}
+
+// streams returns writers for the buffered stdout and stderr
+func (o *moduleOutput) streams() (stdout, stderr io.Writer) {
+	return &moduleOutputStream{output: o}, &moduleOutputStream{output: o, stderr: true}
+}
+
+// writeTo prints the buffered output to stdout and stderr while holding mu, so
+// no other module's output is printed in between. Non-empty header and footer
+// are written to stdout around it.
+func (o *moduleOutput) writeTo(stdout, stderr io.Writer, mu *sync.Mutex, header, footer string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	mu.Lock()
+	defer mu.Unlock()
+
+	if header != "" {
+		if _, err := io.WriteString(stdout, header); err != nil {
+			return err
+		}
+	}
+	for _, chunk := range o.chunks {
+		out := stdout
+		if chunk.stderr {
+			out = stderr
+		}
+		if _, err := out.Write(chunk.data); err != nil {
+			return err
+		}
+	}
+	if footer != "" {
+		if _, err := io.WriteString(stdout, footer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -266,3 +266,34 @@ func TestPrefixedWriter_ConcurrentWrites(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateOutputMode(t *testing.T) {
+	for _, mode := range []string{"", OutputModeStream, OutputModeGrouped, OutputModeQuiet} {
+		if err := validateOutputMode(mode); err != nil {
+			t.Errorf("validateOutputMode(%q) returned error: %v", mode, err)
+		}
+	}
+	if err := validateOutputMode("interleaved"); err == nil || !strings.Contains(err.Error(), "invalid output mode") {
+		t.Errorf("expected invalid output mode error, got %v", err)
+	}
+}
+
+func TestModuleOutput_WriteTo(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	output := &moduleOutput{}
+	out, errOut := output.streams()
+
+	_, _ = out.Write([]byte("one\n"))
+	_, _ = errOut.Write([]byte("two\n"))
+	_, _ = out.Write([]byte("three\n"))
+
+	if stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Fatal("expected output to be buffered")
+	}
+	if err := output.writeTo(&stdout, &stderr, &sync.Mutex{}, "", ""); err != nil {
+		t.Fatalf("writeTo returned error: %v", err)
+	}
+	if stdout.String() != "one\nthree\n" || stderr.String() != "two\n" {
+		t.Errorf("unexpected output: stdout=%q stderr=%q", stdout.String(), stderr.String())
+	}
+}
@@ -1,17 +1,44 @@
 package cli
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/graph"
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
 )
 
 // ModuleRunner is a function that runs a command on a module
-// with the given stdout and stderr writers.
-type ModuleRunner func(mod ModuleInfo, stdout, stderr io.Writer) error
+// with the given stdout and stderr writers. ctx is cancelled when the run
+// stops early; commands started with it are then interrupted.
+type ModuleRunner func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error
+
+// runOptions controls how modules are scheduled by runOnModulesWithOptions.
+type runOptions struct {
+	parallel bool        // run modules concurrently
+	maxJobs  int         // maximum concurrent jobs when parallel
+	out      io.Writer   // output writer for prefixed output (typically os.Stdout)
+	errOut   io.Writer   // error output writer (typically os.Stderr)
+	mode     string      // output mode; empty means OutputModeStream
+	ci       string      // CI system to group module output for; empty for none
+	logs     *moduleLogs // per-module log files for --log-dir; nil for none
+
+	// maxFailures stops the run once this many modules have failed: queued
+	// modules are cancelled and running ones interrupted. 0 means no limit.
+	maxFailures int
+
+	// deps maps a module path to the paths of modules that must succeed before
+	// it may start. Modules whose dependencies fail are skipped. When nil,
+	// modules run without ordering constraints.
+	deps map[string][]string
+}
 
 // runOnModules executes fn on each module, either sequentially or in parallel
 // based on the parallel flag. When parallel is true, it uses a worker pool
@@ -27,8 +54,38 @@ type ModuleRunner func(mod ModuleInfo, stdout, stderr io.Writer) error
 //
 // Returns combined errors from all failed modules (does not fail fast).
 func runOnModules(modules []ModuleInfo, parallel bool, maxJobs int, out, errOut io.Writer, fn ModuleRunner) error {
+	return runOnModulesWithOptions(modules, runOptions{
+		parallel: parallel,
+		maxJobs:  maxJobs,
+		out:      out,
+		errOut:   errOut,
+	}, fn)
+}
+
+// runOnModulesWithOptions executes fn on each module according to opts.
+// When opts.deps is set, a module only starts after all of its dependencies
+// have succeeded, in both sequential and parallel mode.
+//
+// Returns combined errors from all failed, skipped, and cancelled modules.
+func runOnModulesWithOptions(modules []ModuleInfo, opts runOptions, fn ModuleRunner) error {
+	_, err := runModules(context.Background(), modules, opts, fn)
+	return err
+}
+
+// runModules is runOnModulesWithOptions, also returning the result of each
+// module in the order the modules were run. When parent is cancelled, the
+// run stops like it does on reaching opts.maxFailures.
+func runModules(parent context.Context, modules []ModuleInfo, opts runOptions, fn ModuleRunner) ([]moduleResult, error) {
 	if len(modules) == 0 {
-		return nil
+		return nil, nil
+	}
+
+	if opts.deps != nil {
+		ordered, err := orderByDependencies(modules, opts.deps)
+		if err != nil {
+			return nil, err
+		}
+		modules = ordered
 	}
 
 	// Calculate max name length for alignment
@@ -39,62 +96,294 @@ func runOnModules(modules []ModuleInfo, parallel bool, maxJobs int, out, errOut
 		}
 	}
 
-	if !parallel {
-		return runSequential(modules, maxNameLen, out, errOut, fn)
+	ctx, cancel := context.WithCancelCause(parent)
+	defer cancel(nil)
+	limit := &failureLimit{max: opts.maxFailures, stop: cancel}
+
+	if !opts.parallel {
+		return runSequential(ctx, modules, maxNameLen, opts, limit, fn)
 	}
 
-	return runParallel(modules, maxJobs, maxNameLen, out, errOut, fn)
+	return runParallel(ctx, modules, maxNameLen, opts, limit, fn)
+}
+
+// failureLimit stops a run once the number of failed modules reaches max
+type failureLimit struct {
+	max    int
+	failed int
+	stop   context.CancelCauseFunc
+}
+
+// record counts result and stops the run when the limit is reached.
+// Callers serialize calls.
+func (l *failureLimit) record(result moduleResult) {
+	if result.Status != StatusFailed {
+		return
+	}
+	l.failed++
+	if l.max > 0 && l.failed >= l.max {
+		l.stop(&failureLimitError{maxFailures: l.max})
+	}
 }
 
-// runSequential runs fn on each module one at a time
-func runSequential(modules []ModuleInfo, maxNameLen int, out, errOut io.Writer, fn ModuleRunner) error {
+// runSequential runs fn on each module one at a time.
+// Modules must already be in dependency order when opts.deps is set.
+// Once ctx is cancelled, the remaining modules are cancelled without running.
+func runSequential(ctx context.Context, modules []ModuleInfo, maxNameLen int, opts runOptions, limit *failureLimit, fn ModuleRunner) ([]moduleResult, error) {
 	var errs []error
+	results := make([]moduleResult, 0, len(modules))
 	mu := &sync.Mutex{} // For consistent output even in sequential mode
+	failed := make(map[string]ModuleInfo)
 
 	for i, mod := range modules {
-		writers := newPrefixedWriterPair(mod.Name, maxNameLen, i, out, errOut, mu)
-		if err := fn(mod, writers.stdout, writers.stderr); err != nil {
-			errs = append(errs, &moduleError{module: mod, err: err})
+		result := runWithOutput(mod, maxNameLen, i, opts, mu, func(writers *prefixedWriterPair) moduleResult {
+			if ctx.Err() != nil {
+				return cancelModule(ctx, mod, writers)
+			}
+			if dep, ok := firstFailedDependency(mod, opts.deps, failed); ok {
+				return skipModule(mod, dep, writers)
+			}
+			return runModule(ctx, mod, fn, writers)
+		})
+
+		results = append(results, result)
+		limit.record(result)
+		if result.Err != nil && !result.notApplicable() {
+			failed[mod.Path] = mod
+			errs = append(errs, &moduleError{module: mod, err: result.Err})
 		}
-		_ = writers.Flush()
 	}
 
-	return errors.Join(errs...)
+	return results, errors.Join(errs...)
 }
 
-// runParallel runs fn on modules concurrently with bounded parallelism
-func runParallel(modules []ModuleInfo, maxJobs int, maxNameLen int, out, errOut io.Writer, fn ModuleRunner) error {
+// runParallel runs fn on modules concurrently with bounded parallelism.
+// A module waits for its dependencies (if any) before taking a job slot.
+// Once ctx is cancelled, modules still waiting for a slot are cancelled.
+func runParallel(ctx context.Context, modules []ModuleInfo, maxNameLen int, opts runOptions, limit *failureLimit, fn ModuleRunner) ([]moduleResult, error) {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var errs []error
+	results := make([]moduleResult, len(modules))
+	failed := make(map[string]ModuleInfo)
 
 	// Semaphore channel for bounded concurrency
-	sem := make(chan struct{}, maxJobs)
+	sem := make(chan struct{}, opts.maxJobs)
 
 	// Shared mutex for output synchronization
 	outputMu := &sync.Mutex{}
 
+	// done[path] is closed once the module has finished, failed, or been skipped
+	done := make(map[string]chan struct{}, len(modules))
+	for _, mod := range modules {
+		done[mod.Path] = make(chan struct{})
+	}
+
 	for i, mod := range modules {
 		wg.Add(1)
 		go func(index int, m ModuleInfo) {
 			defer wg.Done()
+			defer close(done[m.Path])
+
+			// Wait for dependencies before taking a job slot
+			for _, dep := range opts.deps[m.Path] {
+				if ch, ok := done[dep]; ok {
+					<-ch
+				}
+			}
 
-			// Acquire semaphore
-			sem <- struct{}{}
-			defer func() { <-sem }()
+			mu.Lock()
+			dep, skip := firstFailedDependency(m, opts.deps, failed)
+			mu.Unlock()
 
-			writers := newPrefixedWriterPair(m.Name, maxNameLen, index, out, errOut, outputMu)
-			if err := fn(m, writers.stdout, writers.stderr); err != nil {
-				mu.Lock()
-				errs = append(errs, &moduleError{module: m, err: err})
-				mu.Unlock()
+			result := runWithOutput(m, maxNameLen, index, opts, outputMu, func(writers *prefixedWriterPair) moduleResult {
+				if ctx.Err() == nil && skip {
+					return skipModule(m, dep, writers)
+				}
+				// Acquire semaphore, unless the run stops while waiting
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return cancelModule(ctx, m, writers)
+				}
+				defer func() { <-sem }()
+				if ctx.Err() != nil {
+					return cancelModule(ctx, m, writers)
+				}
+				return runModule(ctx, m, fn, writers)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[index] = result
+			limit.record(result)
+			if result.Err != nil && !result.notApplicable() {
+				failed[m.Path] = m
+				errs = append(errs, &moduleError{module: m, err: result.Err})
 			}
-			_ = writers.Flush()
 		}(i, mod)
 	}
 
 	wg.Wait()
-	return errors.Join(errs...)
+	return results, errors.Join(errs...)
+}
+
+// runWithOutput calls run with prefixed writers for mod according to the output
+// mode. In stream mode lines are printed as they arrive. In grouped mode the
+// module's output is buffered and printed as one block once run returns; in
+// quiet mode only if the module failed. With opts.ci, each block is wrapped in
+// a collapsible CI log section (in stream mode only when running sequentially,
+// as parallel output interleaves).
+func runWithOutput(mod ModuleInfo, maxNameLen, index int, opts runOptions, mu *sync.Mutex, run func(*prefixedWriterPair) moduleResult) moduleResult {
+	if opts.logs != nil {
+		run = withModuleLog(mod, opts.logs, run)
+	}
+
+	if opts.mode == "" || opts.mode == OutputModeStream {
+		if opts.ci == "" || opts.parallel {
+			return run(newPrefixedWriterPair(mod.Name, maxNameLen, index, opts.out, opts.errOut, mu))
+		}
+		start, end := ciGroup(opts.ci, mod, "")
+		_, _ = io.WriteString(opts.out, start)
+		result := run(newPrefixedWriterPair(mod.Name, maxNameLen, index, opts.out, opts.errOut, mu))
+		_, _ = io.WriteString(opts.out, end)
+		return result
+	}
+
+	output := &moduleOutput{}
+	stdout, stderr := output.streams()
+	result := run(newPrefixedWriterPair(mod.Name, maxNameLen, index, stdout, stderr, &sync.Mutex{}))
+
+	if opts.mode == OutputModeGrouped || result.Status == StatusFailed {
+		start, end := ciGroup(opts.ci, mod, result.Status)
+		_ = output.writeTo(opts.out, opts.errOut, mu, start, end)
+	}
+	return result
+}
+
+// withModuleLog returns run writing the module's output to its log file as
+// well. If the file cannot be created, the module runs without it.
+func withModuleLog(mod ModuleInfo, logs *moduleLogs, run func(*prefixedWriterPair) moduleResult) func(*prefixedWriterPair) moduleResult {
+	return func(writers *prefixedWriterPair) moduleResult {
+		file, err := logs.create(mod)
+		if err != nil {
+			_, _ = fmt.Fprintf(writers.stderr, "Not writing a log: %v\n", err)
+			return run(writers)
+		}
+		defer func() { _ = file.Close() }()
+		writers.logTo(plainWriter{w: file})
+		return run(writers)
+	}
+}
+
+// runModule runs fn on mod and records the result, keeping the tail of its
+// stderr for reports. A module that fails after ctx was cancelled was
+// interrupted because the run stopped, and is recorded as cancelled. A module
+// the command does not apply to is recorded as skipped.
+func runModule(ctx context.Context, mod ModuleInfo, fn ModuleRunner, writers *prefixedWriterPair) moduleResult {
+	tail := newTailWriter(stderrTailLines)
+	attemptsCtx, attempts := process.WithAttempts(ctx)
+	hooksCtx, hookRuns := tasks.WithHookRuns(attemptsCtx)
+	start := time.Now()
+	err := fn(hooksCtx, mod, writers.stdout, io.MultiWriter(writers.stderr, tail))
+	_ = writers.Flush()
+
+	result := moduleResult{
+		Module:     mod,
+		Status:     StatusPassed,
+		Duration:   time.Since(start),
+		StderrTail: tail.String(),
+		Retries:    attempts.Retries(),
+		Hooks:      hookRuns.Runs(),
+		Err:        err,
+	}
+	var notApplicable *notApplicableError
+	switch {
+	case errors.As(err, &notApplicable):
+		_, _ = fmt.Fprintf(writers.stderr, "Skipped: %s\n", notApplicable.reason)
+		_ = writers.Flush()
+		result.Status = StatusSkipped
+	case err != nil && ctx.Err() != nil:
+		result.Status = StatusCancelled
+		result.Err = &cancelledError{cause: context.Cause(ctx), err: err}
+	case err != nil:
+		result.Status = StatusFailed
+	}
+	return result
+}
+
+// firstFailedDependency returns the first dependency of mod that failed or was skipped
+func firstFailedDependency(mod ModuleInfo, deps map[string][]string, failed map[string]ModuleInfo) (ModuleInfo, bool) {
+	for _, dep := range deps[mod.Path] {
+		if depMod, ok := failed[dep]; ok {
+			return depMod, true
+		}
+	}
+	return ModuleInfo{}, false
+}
+
+// skipModule reports a module skipped because dep failed and returns its result
+func skipModule(mod, dep ModuleInfo, writers *prefixedWriterPair) moduleResult {
+	_, _ = fmt.Fprintf(writers.stderr, "Skipped: dependency %s did not succeed\n", dep.Name)
+	_ = writers.Flush()
+	return moduleResult{Module: mod, Status: StatusSkipped, Err: &skippedError{dependency: dep}}
+}
+
+// cancelModule reports a module that was not started because the run was
+// stopped (ctx is cancelled) and returns its result
+func cancelModule(ctx context.Context, mod ModuleInfo, writers *prefixedWriterPair) moduleResult {
+	cause := context.Cause(ctx)
+	_, _ = fmt.Fprintf(writers.stderr, "Cancelled: %v\n", cause)
+	_ = writers.Flush()
+	return moduleResult{Module: mod, Status: StatusCancelled, Err: &cancelledError{cause: cause}}
+}
+
+// orderByDependencies returns modules ordered so that each module comes after
+// its dependencies, keeping the original order where there are no constraints.
+// Returns an error if deps contains a cycle.
+func orderByDependencies(modules []ModuleInfo, deps map[string][]string) ([]ModuleInfo, error) {
+	g := graph.New()
+	for _, mod := range modules {
+		g.AddNode(mod.Path)
+		for _, dep := range deps[mod.Path] {
+			g.AddEdge(mod.Path, dep)
+		}
+	}
+	if _, err := g.TopologicalSort(); err != nil {
+		return nil, err
+	}
+
+	// Kahn's algorithm over the input order for stable output
+	remaining := make(map[string]int, len(modules))
+	inSet := make(map[string]bool, len(modules))
+	for _, mod := range modules {
+		inSet[mod.Path] = true
+	}
+	for _, mod := range modules {
+		for _, dep := range deps[mod.Path] {
+			if inSet[dep] {
+				remaining[mod.Path]++
+			}
+		}
+	}
+
+	ordered := make([]ModuleInfo, 0, len(modules))
+	placed := make(map[string]bool, len(modules))
+	for len(ordered) < len(modules) {
+		for _, mod := range modules {
+			if placed[mod.Path] || remaining[mod.Path] > 0 {
+				continue
+			}
+			placed[mod.Path] = true
+			ordered = append(ordered, mod)
+			for _, dependent := range g.Dependents(mod.Path) {
+				remaining[dependent]--
+			}
+			break
+		}
+	}
+
+	return ordered, nil
 }
 
 // moduleError wraps an error with module context
@@ -111,12 +400,152 @@ func (e *moduleError) Unwrap() error {
 	return e.err
 }
 
+// skippedError indicates a module was not run because a dependency did not succeed
+type skippedError struct {
+	dependency ModuleInfo
+}
+
+func (e *skippedError) Error() string {
+	return "skipped: dependency " + e.dependency.Name + " (" + e.dependency.Path + ") did not succeed"
+}
+
+// notApplicableError indicates a module was skipped because the command does
+// not apply to it. Unlike other skipped modules, it does not fail the run.
+type notApplicableError struct {
+	reason string
+}
+
+func (e *notApplicableError) Error() string {
+	return "skipped: " + e.reason
+}
+
+// cancelledError indicates a module was not started, or was interrupted,
+// because the run stopped: it reached the failure limit or motf received a signal
+type cancelledError struct {
+	cause error // why the run stopped: a *failureLimitError or *process.SignalError
+	err   error // error of the interrupted command; nil if the module did not start
+}
+
+func (e *cancelledError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("cancelled: not started, %v", e.cause)
+	}
+	return fmt.Sprintf("cancelled: interrupted, %v (%v)", e.cause, e.err)
+}
+
+func (e *cancelledError) Unwrap() error {
+	return e.err
+}
+
+// failureLimitError is the cause of a run stopped by --fail-fast or --max-failures
+type failureLimitError struct {
+	maxFailures int
+}
+
+func (e *failureLimitError) Error() string {
+	return fmt.Sprintf("the run stopped after %d failed module(s)", e.maxFailures)
+}
+
+// failFastFlag stops a run after the first failed module
+var failFastFlag bool
+
+// maxFailuresFlag stops a run after this many failed modules; 0 means no limit
+var maxFailuresFlag int
+
+// resolveMaxFailures returns the failure limit of a run from --fail-fast and
+// --max-failures, where 0 means no limit
+func resolveMaxFailures() (int, error) {
+	if maxFailuresFlag < 0 {
+		return 0, fmt.Errorf("invalid --max-failures %d: must be 0 or greater", maxFailuresFlag)
+	}
+	if failFastFlag {
+		if maxFailuresFlag > 1 {
+			return 0, fmt.Errorf("--fail-fast cannot be used with --max-failures %d", maxFailuresFlag)
+		}
+		return 1, nil
+	}
+	return maxFailuresFlag, nil
+}
+
 // RunOnModulesParallel is a convenience function that uses the global
 // parallelFlag along with config to run on modules.
 // This is the primary entry point for commands using --changed.
+// When dagFlag is set, modules run in dependency order.
 //
 // Note: CLI flags are merged into config during PersistentPreRunE,
 // so parallelismCfg already reflects any --max-parallel override.
 func RunOnModulesParallel(modules []ModuleInfo, parallelismCfg *config.ParallelismConfig, fn ModuleRunner) error {
-	return runOnModules(modules, parallelFlag, parallelismCfg.GetMaxJobs(), os.Stdout, os.Stderr, fn)
+	return runAndReport(modules, parallelismCfg, !noSummaryFlag, fn)
+}
+
+// runAndReport runs fn on modules like RunOnModulesParallel, writes --report
+// reports, and prints the run summary to stderr when summary is set and more
+// than one module ran. Stderr keeps stdout clean for JSON output. If motf
+// received a signal, it also lists the modules that were interrupted.
+func runAndReport(modules []ModuleInfo, parallelismCfg *config.ParallelismConfig, summary bool, fn ModuleRunner) error {
+	maxFailures, err := resolveMaxFailures()
+	if err != nil {
+		return err
+	}
+
+	opts := runOptions{
+		parallel:    parallelFlag,
+		maxJobs:     parallelismCfg.GetMaxJobs(),
+		out:         os.Stdout,
+		errOut:      os.Stderr,
+		mode:        outputModeFlag,
+		ci:          ciProvider,
+		maxFailures: maxFailures,
+	}
+	if logDirFlag != "" {
+		opts.logs = newModuleLogs(logDirFlag, modules)
+	}
+
+	// CI log sections need each module's output in one block
+	if opts.ci != "" && opts.mode == "" {
+		opts.mode = OutputModeGrouped
+	}
+
+	if dagFlag {
+		basePath, err := getBasePath()
+		if err != nil {
+			return err
+		}
+		deps, err := moduleDependencies(basePath, modules)
+		if err != nil {
+			return err
+		}
+		opts.deps = deps
+	}
+
+	reports, err := parseReportFlags(reportFlag)
+	if err != nil {
+		return err
+	}
+
+	started := time.Now()
+	ctx, annotations := withAnnotations(runContext)
+	results, runErr := runModules(ctx, modules, opts, fn)
+	wall := time.Since(started)
+
+	if opts.ci == CIGitHub {
+		printGitHubAnnotations(opts.out, annotations.all())
+	}
+	if summary && len(results) > 1 {
+		printRunSummary(opts.errOut, results, wall)
+	}
+	var sig *process.SignalError
+	if errors.As(context.Cause(runContext), &sig) {
+		printInterruptReport(opts.errOut, sig, results)
+	}
+
+	var errs []error
+	if summary && opts.ci == CIGitHub {
+		errs = append(errs, writeGitHubStepSummary(commandName, results, wall))
+	}
+	errs = append(errs, writeReports(reports, commandName, started, results))
+	if err := errors.Join(errs...); err != nil {
+		return errors.Join(runErr, err)
+	}
+	return runErr
 }
@@ -2,10 +2,14 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -15,7 +19,7 @@ func TestRunOnModules_Empty(t *testing.T) {
 	var buf bytes.Buffer
 	called := false
 
-	err := runOnModules([]ModuleInfo{}, false, 4, &buf, &buf, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+	err := runOnModules([]ModuleInfo{}, false, 4, &buf, &buf, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
 		called = true
 		return nil
 	})
@@ -38,7 +42,7 @@ func TestRunOnModules_Sequential(t *testing.T) {
 
 	var order []string
 
-	err := runOnModules(modules, false, 4, &buf, &buf, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+	err := runOnModules(modules, false, 4, &buf, &buf, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
 		order = append(order, mod.Name)
 		_, _ = stdout.Write([]byte("processing " + mod.Name + "\n"))
 		return nil
@@ -68,7 +72,7 @@ func TestRunOnModules_Parallel(t *testing.T) {
 
 	var count atomic.Int32
 
-	err := runOnModules(modules, true, 4, &buf, &buf, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+	err := runOnModules(modules, true, 4, &buf, &buf, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
 		count.Add(1)
 		_, _ = stdout.Write([]byte("processing " + mod.Name + "\n"))
 		return nil
@@ -91,7 +95,7 @@ func TestRunOnModules_CollectsAllErrors(t *testing.T) {
 		{Name: "mod-c", Path: "path/to/c"},
 	}
 
-	err := runOnModules(modules, false, 4, &buf, &buf, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+	err := runOnModules(modules, false, 4, &buf, &buf, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
 		if mod.Name == "mod-a" || mod.Name == "mod-c" {
 			return errors.New("failed")
 		}
@@ -120,7 +124,7 @@ func TestRunOnModules_ParallelCollectsAllErrors(t *testing.T) {
 		{Name: "mod-c", Path: "path/to/c"},
 	}
 
-	err := runOnModules(modules, true, 4, &buf, &buf, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+	err := runOnModules(modules, true, 4, &buf, &buf, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
 		if mod.Name == "mod-a" || mod.Name == "mod-c" {
 			return errors.New("failed")
 		}
@@ -152,7 +156,7 @@ func TestRunOnModules_BoundedParallelism(t *testing.T) {
 	var maxConcurrent atomic.Int32
 	maxJobs := 3
 
-	err := runOnModules(modules, true, maxJobs, &buf, &buf, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+	err := runOnModules(modules, true, maxJobs, &buf, &buf, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
 		current := concurrent.Add(1)
 		// Track max concurrent
 		for {
@@ -200,3 +204,359 @@ func TestModuleError(t *testing.T) {
 		t.Errorf("Unwrap() should return original error")
 	}
 }
+
+// dagTestModules returns prod -> app -> storage plus an independent module,
+// listed in reverse dependency order.
+func dagTestModules() ([]ModuleInfo, map[string][]string) {
+	modules := []ModuleInfo{
+		{Name: "prod", Path: "projects/prod"},
+		{Name: "app", Path: "bases/app"},
+		{Name: "storage", Path: "components/storage"},
+		{Name: "other", Path: "components/other"},
+	}
+	deps := map[string][]string{
+		"projects/prod": {"bases/app", "components/storage"},
+		"bases/app":     {"components/storage"},
+	}
+	return modules, deps
+}
+
+func TestOrderByDependencies(t *testing.T) {
+	modules, deps := dagTestModules()
+
+	ordered, err := orderByDependencies(modules, deps)
+	if err != nil {
+		t.Fatalf("orderByDependencies returned error: %v", err)
+	}
+
+	var names []string
+	for _, m := range ordered {
+		names = append(names, m.Name)
+	}
+
+	want := []string{"storage", "app", "prod", "other"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", names, want)
+	}
+}
+
+func TestOrderByDependencies_Cycle(t *testing.T) {
+	modules := []ModuleInfo{
+		{Name: "a", Path: "a"},
+		{Name: "b", Path: "b"},
+	}
+	deps := map[string][]string{"a": {"b"}, "b": {"a"}}
+
+	if _, err := orderByDependencies(modules, deps); err == nil {
+		t.Fatal("expected cycle error, got nil")
+	}
+}
+
+func TestRunOnModulesWithOptions_SequentialDependencyOrder(t *testing.T) {
+	var buf bytes.Buffer
+	modules, deps := dagTestModules()
+
+	var order []string
+	err := runOnModulesWithOptions(modules, runOptions{out: &buf, errOut: &buf, deps: deps}, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+		order = append(order, mod.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []string{"storage", "app", "prod", "other"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestRunOnModulesWithOptions_SkipsDependentsOfFailures(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("parallel=%v", parallel), func(t *testing.T) {
+			var buf bytes.Buffer
+			modules, deps := dagTestModules()
+
+			var mu sync.Mutex
+			ran := make(map[string]bool)
+			opts := runOptions{parallel: parallel, maxJobs: 4, out: &buf, errOut: &buf, deps: deps}
+
+			err := runOnModulesWithOptions(modules, opts, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+				mu.Lock()
+				ran[mod.Name] = true
+				mu.Unlock()
+				if mod.Name == "storage" {
+					return errors.New("init failed")
+				}
+				return nil
+			})
+
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if ran["app"] || ran["prod"] {
+				t.Errorf("dependents of failed module should not run, ran: %v", ran)
+			}
+			if !ran["other"] {
+				t.Error("independent module should still run")
+			}
+
+			var skipErr *skippedError
+			if !errors.As(err, &skipErr) {
+				t.Errorf("expected a skippedError in %v", err)
+			}
+
+			errStr := err.Error()
+			for _, want := range []string{"storage", "init failed", "app (bases/app): skipped", "prod (projects/prod): skipped"} {
+				if !strings.Contains(errStr, want) {
+					t.Errorf("error should contain %q: %s", want, errStr)
+				}
+			}
+			if !strings.Contains(buf.String(), "Skipped: dependency") {
+				t.Errorf("output should report skipped modules, got: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestRunOnModulesWithOptions_ParallelWaitsForDependencies(t *testing.T) {
+	var buf bytes.Buffer
+	modules, deps := dagTestModules()
+
+	var mu sync.Mutex
+	finished := make(map[string]bool)
+	var violations []string
+
+	opts := runOptions{parallel: true, maxJobs: 4, out: &buf, errOut: &buf, deps: deps}
+	err := runOnModulesWithOptions(modules, opts, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+		mu.Lock()
+		for _, dep := range deps[mod.Path] {
+			if !finished[dep] {
+				violations = append(violations, mod.Name+" started before "+dep)
+			}
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		finished[mod.Path] = true
+		mu.Unlock()
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(violations) > 0 {
+		t.Errorf("dependency order violated: %v", violations)
+	}
+}
+
+func TestRunOnModulesWithOptions_GroupedOutput(t *testing.T) {
+	var out bytes.Buffer
+	modules := []ModuleInfo{
+		{Name: "mod-a", Path: "path/to/a"},
+		{Name: "mod-b", Path: "path/to/b"},
+	}
+
+	// mod-a writes its second line only after mod-b has written everything
+	bDone := make(chan struct{})
+	err := runOnModulesWithOptions(modules, runOptions{parallel: true, maxJobs: 2, out: &out, errOut: &out, mode: OutputModeGrouped}, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+		if mod.Name == "mod-b" {
+			_, _ = fmt.Fprintln(stdout, "b1")
+			_, _ = fmt.Fprintln(stderr, "b2")
+			close(bDone)
+			return nil
+		}
+		_, _ = fmt.Fprintln(stdout, "a1")
+		<-bDone
+		_, _ = fmt.Fprintln(stderr, "a2")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		got = append(got, line[strings.LastIndex(line, " ")+1:])
+	}
+	if strings.Join(got, ",") != "b1,b2,a1,a2" {
+		t.Errorf("expected contiguous blocks per module, got %v", got)
+	}
+}
+
+func TestRunOnModulesWithOptions_QuietOutput(t *testing.T) {
+	var out bytes.Buffer
+	modules := []ModuleInfo{
+		{Name: "mod-a", Path: "path/to/a"},
+		{Name: "mod-b", Path: "path/to/b"},
+	}
+
+	err := runOnModulesWithOptions(modules, runOptions{out: &out, errOut: &out, mode: OutputModeQuiet}, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+		_, _ = fmt.Fprintln(stdout, "output of "+mod.Name)
+		if mod.Name == "mod-b" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if strings.Contains(out.String(), "output of mod-a") {
+		t.Errorf("expected output of succeeded module to be hidden, got: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "output of mod-b") {
+		t.Errorf("expected output of failed module, got: %s", out.String())
+	}
+}
+
+func TestRunModules_MaxFailures(t *testing.T) {
+	modules := []ModuleInfo{
+		{Name: "a", Path: "components/a"},
+		{Name: "b", Path: "components/b"},
+		{Name: "c", Path: "components/c"},
+		{Name: "d", Path: "components/d"},
+	}
+
+	tests := []struct {
+		name        string
+		maxFailures int
+		want        []string
+	}{
+		{"no limit", 0, []string{StatusFailed, StatusFailed, StatusPassed, StatusPassed}},
+		{"fail fast", 1, []string{StatusFailed, StatusCancelled, StatusCancelled, StatusCancelled}},
+		{"two failures", 2, []string{StatusFailed, StatusFailed, StatusCancelled, StatusCancelled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var ran []string
+			opts := runOptions{out: &buf, errOut: &buf, maxFailures: tt.maxFailures}
+
+			results, err := runModules(context.Background(), modules, opts, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+				ran = append(ran, mod.Name)
+				if mod.Name == "a" || mod.Name == "b" {
+					return errors.New("init failed")
+				}
+				return nil
+			})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			var got []string
+			for _, r := range results {
+				got = append(got, r.Status)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statuses = %v, want %v", got, tt.want)
+			}
+			for _, r := range results {
+				if r.Status == StatusCancelled && slices.Contains(ran, r.Module.Name) {
+					t.Errorf("cancelled module %s should not have run", r.Module.Name)
+				}
+			}
+			if tt.maxFailures > 0 && !strings.Contains(err.Error(), "d (components/d): cancelled: not started") {
+				t.Errorf("expected cancelled modules in error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunModules_FailFastInterruptsRunningModules(t *testing.T) {
+	var buf bytes.Buffer
+	modules := []ModuleInfo{
+		{Name: "a", Path: "components/a"},
+		{Name: "b", Path: "components/b"},
+	}
+
+	bStarted := make(chan struct{})
+	opts := runOptions{parallel: true, maxJobs: 2, out: &buf, errOut: &buf, maxFailures: 1}
+	results, err := runModules(context.Background(), modules, opts, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+		if mod.Name == "a" {
+			<-bStarted
+			return errors.New("init failed")
+		}
+		close(bStarted)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if results[0].Status != StatusFailed {
+		t.Errorf("expected a to fail, got %s", results[0].Status)
+	}
+	if results[1].Status != StatusCancelled || !results[1].started() {
+		t.Errorf("expected b to be interrupted, got %s (started: %v)", results[1].Status, results[1].started())
+	}
+	if !strings.Contains(err.Error(), "b (components/b): cancelled: interrupted") {
+		t.Errorf("expected interrupted module in error, got: %v", err)
+	}
+}
+
+func TestRunModules_FailFastCancelsQueuedDependents(t *testing.T) {
+	var buf bytes.Buffer
+	modules := []ModuleInfo{
+		{Name: "a", Path: "components/a"},
+		{Name: "b", Path: "components/b"},
+	}
+	deps := map[string][]string{"components/b": {"components/a"}}
+
+	opts := runOptions{parallel: true, maxJobs: 2, out: &buf, errOut: &buf, deps: deps, maxFailures: 1}
+	results, err := runModules(context.Background(), modules, opts, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+		return errors.New("init failed")
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// The run stopped, so b is cancelled rather than skipped for its failed dependency
+	if results[1].Status != StatusCancelled || results[1].started() {
+		t.Errorf("expected b to be cancelled before starting, got %s", results[1].Status)
+	}
+	if !strings.Contains(buf.String(), "Cancelled: the run stopped after 1 failed module(s)") {
+		t.Errorf("expected cancellation in output, got: %s", buf.String())
+	}
+}
+
+func TestResolveMaxFailures(t *testing.T) {
+	tests := []struct {
+		name        string
+		failFast    bool
+		maxFailures int
+		want        int
+		errMsg      string
+	}{
+		{"default", false, 0, 0, ""},
+		{"fail fast", true, 0, 1, ""},
+		{"max failures", false, 3, 3, ""},
+		{"fail fast with max failures 1", true, 1, 1, ""},
+		{"fail fast conflicts", true, 2, 0, "--fail-fast cannot be used with --max-failures 2"},
+		{"negative", false, -1, 0, "invalid --max-failures -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			failFastFlag = tt.failFast
+			maxFailuresFlag = tt.maxFailures
+
+			got, err := resolveMaxFailures()
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveMaxFailures() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
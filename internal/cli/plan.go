@@ -1,62 +1,155 @@
 package cli
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	planOutFlag  string // File to save the plan to, relative to each module directory
+	planJSONFlag bool   // Print the plan summary as JSON
+)
+
 // planCmd represents the plan command
 var planCmd = &cobra.Command{
-	Use:   "plan [module-name]",
-	Short: "Run terraform/tofu plan on a component, base, or project",
-	Long: `Run terraform/tofu plan on a component, base, or project.
+	Use:   "plan [module-name...]",
+	Short: "Run terraform/tofu plan on a module",
+	Long: `Run terraform/tofu plan on a module: a component, base, or project by default,
+or a module of any kind configured in module_kinds.
 
 Use the --example/-e flag to run plan on a specific example instead of the module itself.
 
+Use the --out flag to save the plan to a file in each module directory, which can then
+be applied with 'motf apply --plan-file'. With --out, each saved plan is read back with
+'show -json' and a summary of resources to add, change, destroy, and replace is printed
+per module, followed by a combined summary table. Use --json to print the combined
+summary as JSON instead; terraform/tofu output then goes to stderr.
+
+Several modules can be selected at once by passing multiple module names or
+using --all, --type, --search, --tag, or --changed. Selected modules run through the
+same pipeline as --changed (supporting --parallel and --dag).
+
 Examples:
   motf plan storage-account                 # Run plan on storage-account module
   motf plan storage-account -e basic        # Run plan on the 'basic' example
   motf plan storage-account --example basic # Run plan on the 'basic' example
-  motf plan -i storage-account              # Run init then plan`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		if changedFlag {
-			if len(args) > 0 {
-				return cobra.MaximumNArgs(0)(cmd, args)
+  motf plan -i storage-account              # Run init then plan
+  motf plan storage-account --out tfplan    # Save the plan to storage-account/tfplan
+  motf plan --changed -p --out tfplan       # Plan changed modules and print a combined summary
+  motf plan --changed --out tfplan --json   # Combined summary as JSON on stdout
+  motf plan -i --type project --dag         # Plan all projects in dependency order`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runPlan,
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	if planJSONFlag && planOutFlag == "" {
+		return fmt.Errorf("--json requires --out")
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	gitRoot, err := repoRoot()
+	if err != nil {
+		return err
+	}
+
+	var summaries *planSummaryCollector
+	if planOutFlag != "" {
+		summaries = &planSummaryCollector{}
+	}
+
+	planModule := func(ctx context.Context, mod ModuleInfo, moduleAbsPath string, stdout, stderr io.Writer) error {
+		// Keep stdout clean for the JSON summary
+		if planJSONFlag {
+			stdout = stderr
+		}
+		tfRunner, err := moduleRunner(ctx, gitRoot, moduleAbsPath)
+		if err != nil {
+			return err
+		}
+		if initFlag {
+			if err := tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
+				return err
 			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
-				if initFlag {
-					if err := runner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
-						return err
-					}
-				}
-				return runner.RunPlanWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
-			})
 		}
+		if err := tfRunner.RunPlanWithOutput(moduleAbsPath, stdout, stderr, planArgs()...); err != nil {
+			return err
+		}
+		if summaries == nil {
+			return nil
+		}
+		return summarizeModulePlan(tfRunner, summaries, mod, moduleAbsPath, stdout, stderr)
+	}
 
-		targetPath, err := resolveTargetWithExample(args, exampleFlag)
+	if usesModuleSelection(args) {
+		err = runOnSelectedModules(args, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+			return planModule(ctx, mod, filepath.Join(basePath, mod.Path), stdout, stderr)
+		})
+	} else {
+		var targetPath string
+		targetPath, err = resolveTargetWithExample(args, exampleFlag)
 		if err != nil {
 			return err
 		}
+		err = planModule(runContext, moduleInfoFromPath(basePath, targetPath), targetPath, os.Stdout, os.Stderr)
+	}
 
-		// Run init first if flag is set
-		if initFlag {
-			if err := runner.RunInit(targetPath); err != nil {
-				return err
+	// Print the combined summary even when some modules failed
+	if summaries != nil {
+		report := summaries.report()
+		if planJSONFlag {
+			if jsonErr := printPlanSummaryJSON(cmd.OutOrStdout(), report); jsonErr != nil {
+				return errors.Join(err, jsonErr)
 			}
+		} else if len(report.Modules) > 0 {
+			printPlanSummaryTable(cmd.OutOrStdout(), report)
 		}
+	}
+
+	return err
+}
 
-		return runner.RunPlan(targetPath, argsFlag...)
-	},
+// planArgs returns the extra arguments for terraform/tofu plan, including -out when --out is set
+func planArgs() []string {
+	args := append([]string{}, argsFlag...)
+	if planOutFlag != "" {
+		args = append(args, "-out="+planOutFlag)
+	}
+	return args
 }
 
 func init() {
+	planCmd.Flags().StringVar(&planOutFlag, "out", "", "Save the plan to this file (relative to each module directory) and print a change summary")
+	planCmd.Flags().BoolVar(&planJSONFlag, "json", false, "Print the plan summary as JSON (requires --out)")
 	planCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
 	planCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	planCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	planCmd.Flags().StringVar(&typeFlag, "type", "", "Only run on modules of this type, as configured in module_kinds (default: component, base, or project)")
+	planCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Only run on modules matching a wildcard (e.g., *storage*)")
+	planCmd.Flags().StringArrayVar(&tagFlag, "tag", []string{}, "Only run on modules with this tag (can be specified multiple times)")
 	planCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
 	planCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	planCmd.Flags().BoolVar(&dependentsFlag, "dependents", false, "Also include modules that transitively depend on changed modules (requires --changed)")
 	planCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
 	planCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	planCmd.Flags().BoolVar(&failFastFlag, "fail-fast", false, "Stop after the first failed module: cancel queued modules and interrupt running ones")
+	planCmd.Flags().IntVar(&maxFailuresFlag, "max-failures", 0, "Stop after this many modules have failed (default: no limit)")
+	planCmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Interrupt each command or task running longer than this, e.g. 20m (default: execution.timeout, no timeout)")
+	planCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry commands failing with an error matching execution.retry_on up to this many times (default: execution.retries)")
+	planCmd.Flags().StringVar(&outputModeFlag, "output-mode", "", "How to print output of multiple modules: stream, grouped, or quiet (default: stream)")
+	planCmd.Flags().StringVar(&ciFlag, "ci", "", "Format output for a CI system: github, gitlab, or none (default: detected from GITHUB_ACTIONS/GITLAB_CI)")
+	planCmd.Flags().BoolVar(&dagFlag, "dag", false, "Run modules in dependency order and skip dependents of failed modules")
+	planCmd.Flags().StringArrayVar(&reportFlag, "report", []string{}, "Write a report of the run: junit=<path> or json=<path> (can be specified multiple times)")
+	planCmd.Flags().StringVar(&logDirFlag, "log-dir", "", "Write each module's uncolored output to <dir>/<type>/<name>.log")
+	planCmd.Flags().BoolVar(&noSummaryFlag, "no-summary", false, "Don't print the summary table after running on several modules")
 	rootCmd.AddCommand(planCmd)
 }
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+// modulePlanSummary is the summary of a saved plan for one module
+type modulePlanSummary struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	terraform.PlanSummary
+}
+
+// planSummaryReport is the combined summary of all planned modules
+type planSummaryReport struct {
+	Modules []modulePlanSummary   `json:"modules"`
+	Total   terraform.PlanSummary `json:"total"`
+}
+
+// planSummaryCollector collects plan summaries from concurrently running modules
+type planSummaryCollector struct {
+	mu        sync.Mutex
+	summaries []modulePlanSummary
+}
+
+// add records the plan summary of mod
+func (c *planSummaryCollector) add(mod ModuleInfo, summary *terraform.PlanSummary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.summaries = append(c.summaries, modulePlanSummary{Name: mod.Name, Path: mod.Path, PlanSummary: *summary})
+}
+
+// report returns the collected summaries sorted by path, with totals
+func (c *planSummaryCollector) report() *planSummaryReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &planSummaryReport{Modules: append([]modulePlanSummary{}, c.summaries...)}
+	sort.Slice(report.Modules, func(i, j int) bool {
+		return report.Modules[i].Path < report.Modules[j].Path
+	})
+	for _, mod := range report.Modules {
+		report.Total = report.Total.Plus(mod.PlanSummary)
+	}
+	return report
+}
+
+// summarizeModulePlan summarizes the plan saved by --out in moduleAbsPath using
+// tfRunner, prints a one-line summary to stdout, and records it in collector.
+func summarizeModulePlan(tfRunner *terraform.Runner, collector *planSummaryCollector, mod ModuleInfo, moduleAbsPath string, stdout, stderr io.Writer) error {
+	summary, err := tfRunner.SummarizePlan(moduleAbsPath, planOutFlag, stderr)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(stdout, "Summary: %d to add, %d to change, %d to destroy, %d to replace\n",
+		summary.Add, summary.Change, summary.Destroy, summary.Replace)
+	collector.add(mod, summary)
+	return nil
+}
+
+// printPlanSummaryTable writes the combined plan summary as a table, followed by
+// the addresses of every resource that will be destroyed or replaced.
+func printPlanSummaryTable(w io.Writer, report *planSummaryReport) {
+	nameWidth := len("MODULE")
+	for _, mod := range report.Modules {
+		if len(mod.Name) > nameWidth {
+			nameWidth = len(mod.Name)
+		}
+	}
+
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Plan summary:")
+	_, _ = fmt.Fprintf(w, "%-*s  %5s  %6s  %7s  %7s\n", nameWidth, "MODULE", "ADD", "CHANGE", "DESTROY", "REPLACE")
+	for _, mod := range report.Modules {
+		_, _ = fmt.Fprintf(w, "%-*s  %5d  %6d  %7d  %7d\n", nameWidth, mod.Name, mod.Add, mod.Change, mod.Destroy, mod.Replace)
+	}
+	if len(report.Modules) > 1 {
+		t := report.Total
+		_, _ = fmt.Fprintf(w, "%-*s  %5d  %6d  %7d  %7d\n", nameWidth, "TOTAL", t.Add, t.Change, t.Destroy, t.Replace)
+	}
+
+	printPlanAddresses(w, "Resources to destroy:", report.Modules, func(s terraform.PlanSummary) []string { return s.Destroyed })
+	printPlanAddresses(w, "Resources to replace:", report.Modules, func(s terraform.PlanSummary) []string { return s.Replaced })
+}
+
+// printPlanAddresses writes a titled list of module/address pairs, or nothing if there are none
+func printPlanAddresses(w io.Writer, title string, modules []modulePlanSummary, addresses func(terraform.PlanSummary) []string) {
+	printedTitle := false
+	for _, mod := range modules {
+		for _, address := range addresses(mod.PlanSummary) {
+			if !printedTitle {
+				_, _ = fmt.Fprintln(w)
+				_, _ = fmt.Fprintln(w, title)
+				printedTitle = true
+			}
+			_, _ = fmt.Fprintf(w, "  %s: %s\n", mod.Name, address)
+		}
+	}
+}
+
+// printPlanSummaryJSON writes the combined plan summary as indented JSON
+func printPlanSummaryJSON(w io.Writer, report *planSummaryReport) error {
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+// fakePlanBinary is a terraform stand-in: "plan -out=FILE" copies plan.json to FILE
+// and "show -json FILE" prints FILE.
+const fakePlanBinary = `#!/bin/sh
+case "$1" in
+plan)
+  for arg in "$@"; do
+    case "$arg" in -out=*) cp plan.json "${arg#-out=}" ;; esac
+  done
+  echo "planned"
+  ;;
+show)
+  cat "$3"
+  ;;
+esac
+`
+
+// writePlanJSON writes the plan.json fixture used by fakePlanBinary into modulePath.
+func writePlanJSON(t *testing.T, modulePath string, actions ...string) {
+	t.Helper()
+	var changes []string
+	for i, action := range actions {
+		changes = append(changes, `{"address": "null_resource.r`+string(rune('0'+i))+`", "change": {"actions": [`+action+`]}}`)
+	}
+	content := `{"resource_changes": [` + strings.Join(changes, ",") + `]}`
+	if err := os.WriteFile(filepath.Join(modulePath, "plan.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlanSummaryCollector_Report(t *testing.T) {
+	c := &planSummaryCollector{}
+	c.add(ModuleInfo{Name: "prod", Path: "projects/prod"}, &terraform.PlanSummary{Add: 1, Destroy: 1, Destroyed: []string{"a.b"}})
+	c.add(ModuleInfo{Name: "app", Path: "bases/app"}, &terraform.PlanSummary{Change: 2, Replace: 1})
+
+	report := c.report()
+
+	if len(report.Modules) != 2 || report.Modules[0].Name != "app" {
+		t.Fatalf("expected modules sorted by path, got %+v", report.Modules)
+	}
+	want := terraform.PlanSummary{Add: 1, Change: 2, Destroy: 1, Replace: 1}
+	if report.Total.Add != want.Add || report.Total.Change != want.Change ||
+		report.Total.Destroy != want.Destroy || report.Total.Replace != want.Replace {
+		t.Errorf("Total = %+v, want %+v", report.Total, want)
+	}
+}
+
+func TestPrintPlanSummaryTable(t *testing.T) {
+	report := &planSummaryReport{
+		Modules: []modulePlanSummary{
+			{Name: "app", Path: "bases/app", PlanSummary: terraform.PlanSummary{Add: 3}},
+			{Name: "prod", Path: "projects/prod", PlanSummary: terraform.PlanSummary{Destroy: 1, Destroyed: []string{"azurerm_storage_account.this"}}},
+		},
+		Total: terraform.PlanSummary{Add: 3, Destroy: 1},
+	}
+
+	var buf bytes.Buffer
+	printPlanSummaryTable(&buf, report)
+	out := buf.String()
+
+	expected := []string{
+		"MODULE    ADD  CHANGE  DESTROY  REPLACE",
+		"app         3       0        0        0",
+		"TOTAL       3       0        1        0",
+		"Resources to destroy:\n  prod: azurerm_storage_account.this",
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Errorf("table output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Resources to replace:") {
+		t.Errorf("did not expect replace section without replacements:\n%s", out)
+	}
+}
+
+func TestPrintPlanSummaryTable_SingleModuleHasNoTotal(t *testing.T) {
+	report := &planSummaryReport{
+		Modules: []modulePlanSummary{{Name: "app", Path: "bases/app", PlanSummary: terraform.PlanSummary{Add: 1}}},
+		Total:   terraform.PlanSummary{Add: 1},
+	}
+
+	var buf bytes.Buffer
+	printPlanSummaryTable(&buf, report)
+	if strings.Contains(buf.String(), "TOTAL") {
+		t.Errorf("did not expect TOTAL row for a single module:\n%s", buf.String())
+	}
+}
+
+func TestRunPlan_SummaryAcrossModules(t *testing.T) {
+	resetFlags(t)
+	tmpDir := setupSelectionTree(t)
+	withFakeRunner(t, fakePlanBinary)
+
+	writePlanJSON(t, filepath.Join(tmpDir, "components", "storage-account"), `"create"`, `"delete"`)
+	writePlanJSON(t, filepath.Join(tmpDir, "components", "key-vault"), `"update"`, `"delete", "create"`)
+
+	typeFlag = TypeComponent
+	planOutFlag = "tfplan"
+	planJSONFlag = true
+
+	var buf bytes.Buffer
+	planCmd.SetOut(&buf)
+	t.Cleanup(func() { planCmd.SetOut(nil) })
+
+	if err := runPlan(planCmd, nil); err != nil {
+		t.Fatalf("runPlan returned error: %v", err)
+	}
+
+	var report planSummaryReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(report.Modules) != 2 {
+		t.Fatalf("expected 2 modules, got %+v", report.Modules)
+	}
+	if report.Modules[1].Name != "storage-account" || report.Modules[1].Destroy != 1 {
+		t.Errorf("unexpected storage-account summary: %+v", report.Modules[1])
+	}
+	if got := report.Total; got.Add != 1 || got.Change != 1 || got.Destroy != 1 || got.Replace != 1 {
+		t.Errorf("unexpected total: %+v", got)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "components", "key-vault", "tfplan")); err != nil {
+		t.Errorf("expected plan file to be saved in the module directory: %v", err)
+	}
+}
+
+func TestRunPlan_JSONRequiresOut(t *testing.T) {
+	resetFlags(t)
+	planJSONFlag = true
+
+	err := runPlan(planCmd, []string{"storage-account"})
+	if err == nil || !strings.Contains(err.Error(), "--json requires --out") {
+		t.Fatalf("expected --json requires --out error, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -23,3 +24,20 @@ func TestPlanCmd_Flags(t *testing.T) {
 		t.Errorf("example flag shorthand = %q, want %q", exampleFlagDef.Shorthand, "e")
 	}
 }
+
+func TestPlanArgs(t *testing.T) {
+	resetFlags(t)
+	argsFlag = []string{"-var=env=dev"}
+
+	if got, want := planArgs(), []string{"-var=env=dev"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("planArgs() = %v, want %v", got, want)
+	}
+
+	planOutFlag = "tfplan"
+	if got, want := planArgs(), []string{"-var=env=dev", "-out=tfplan"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("planArgs() with --out = %v, want %v", got, want)
+	}
+	if len(argsFlag) != 1 {
+		t.Errorf("planArgs() should not modify argsFlag, got %v", argsFlag)
+	}
+}
@@ -0,0 +1,414 @@
+package cli
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+)
+
+// Report formats accepted by --report
+const (
+	ReportFormatJUnit = "junit"
+	ReportFormatJSON  = "json"
+)
+
+// Module result statuses
+const (
+	StatusPassed    = "passed"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+	StatusCancelled = "cancelled"
+)
+
+// stderrTailLines is how many trailing stderr lines are kept per module for reports
+const stderrTailLines = 20
+
+// reportFlag holds --report values of the form <format>=<path>
+var reportFlag []string
+
+// commandName is the name of the running command, recorded in reports
+var commandName string
+
+// moduleResult records how running a command on one module went
+type moduleResult struct {
+	Module     ModuleInfo
+	Status     string
+	Duration   time.Duration
+	StderrTail string
+	Retries    int             // retries of commands failing with a retryable error
+	Hooks      []tasks.HookRun // hooks run around the module's commands
+	Err        error
+}
+
+// started reports whether the module's command ran, rather than the module
+// being skipped or cancelled before it started
+func (r moduleResult) started() bool {
+	var cancelled *cancelledError
+	if errors.As(r.Err, &cancelled) {
+		return cancelled.err != nil
+	}
+	return r.Status != StatusSkipped
+}
+
+// notApplicable reports whether the module was skipped because the command
+// does not apply to it
+func (r moduleResult) notApplicable() bool {
+	var notApplicable *notApplicableError
+	return errors.As(r.Err, &notApplicable)
+}
+
+// timedOut reports whether the module failed because a command ran longer
+// than its timeout
+func (r moduleResult) timedOut() bool {
+	var timeout *process.TimeoutError
+	return errors.As(r.Err, &timeout)
+}
+
+// exitCode returns the exit code of the module's command: the process exit
+// code when it ran and exited non-zero, 1 for other failures, and false for
+// modules that did not run.
+func (r moduleResult) exitCode() (int, bool) {
+	switch {
+	case !r.started():
+		return 0, false
+	case r.Err == nil:
+		return 0, true
+	}
+	var exitErr *exec.ExitError
+	if errors.As(r.Err, &exitErr) {
+		return exitErr.ExitCode(), true
+	}
+	return 1, true
+}
+
+// reportTarget is a parsed --report value
+type reportTarget struct {
+	format string
+	path   string
+}
+
+// parseReportFlags parses --report values such as junit=report.xml
+func parseReportFlags(values []string) ([]reportTarget, error) {
+	var targets []reportTarget
+	for _, value := range values {
+		format, path, ok := strings.Cut(value, "=")
+		if !ok || path == "" {
+			return nil, fmt.Errorf("invalid --report '%s': must be <format>=<path>, e.g. junit=report.xml", value)
+		}
+		if format != ReportFormatJUnit && format != ReportFormatJSON {
+			return nil, fmt.Errorf("invalid --report format '%s': must be '%s' or '%s'", format, ReportFormatJUnit, ReportFormatJSON)
+		}
+		targets = append(targets, reportTarget{format: format, path: path})
+	}
+	return targets, nil
+}
+
+// validateReportFlags checks --report values before any module runs. Reports
+// are written for module selections, so a module name or selector is required.
+func validateReportFlags(args []string) error {
+	if len(reportFlag) == 0 {
+		return nil
+	}
+	if pathFlag != "" || exampleFlag != "" {
+		return fmt.Errorf("--report cannot be used with --path or --example")
+	}
+	if !usesModuleSelection(args) {
+		return fmt.Errorf("--report requires module names or a selector (--all, --changed, --type, --search, --tag)")
+	}
+	_, err := parseReportFlags(reportFlag)
+	return err
+}
+
+// writeReports writes a report of results to every --report target
+func writeReports(targets []reportTarget, command string, started time.Time, results []moduleResult) error {
+	for _, target := range targets {
+		var data []byte
+		var err error
+		switch target.format {
+		case ReportFormatJUnit:
+			data, err = junitReport(command, results)
+		case ReportFormatJSON:
+			data, err = jsonReport(command, started, results)
+		}
+		if err != nil {
+			return err
+		}
+
+		if dir := filepath.Dir(target.path); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create report directory: %w", err)
+			}
+		}
+		if err := os.WriteFile(target.path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s report: %w", target.format, err)
+		}
+	}
+	return nil
+}
+
+// runReport is the JSON report of a multi-module run
+type runReport struct {
+	Command   string             `json:"command"`
+	StartedAt time.Time          `json:"started_at"`
+	Duration  float64            `json:"duration_seconds"`
+	Summary   runReportSummary   `json:"summary"`
+	Modules   []moduleReportItem `json:"modules"`
+}
+
+// runReportSummary counts modules by status
+type runReportSummary struct {
+	Total     int `json:"total"`
+	Passed    int `json:"passed"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	Cancelled int `json:"cancelled"`
+}
+
+// moduleReportItem is the JSON report entry of one module
+type moduleReportItem struct {
+	Name       string           `json:"name"`
+	Type       string           `json:"type"`
+	Path       string           `json:"path"`
+	Command    string           `json:"command"`
+	Status     string           `json:"status"`
+	Duration   float64          `json:"duration_seconds"`
+	ExitCode   *int             `json:"exit_code,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	StderrTail string           `json:"stderr_tail,omitempty"`
+	Retries    int              `json:"retries,omitempty"`
+	TimedOut   bool             `json:"timed_out,omitempty"`
+	Hooks      []hookReportItem `json:"hooks,omitempty"`
+}
+
+// hookReportItem is the JSON report entry of a hook run on a module
+type hookReportItem struct {
+	Name     string  `json:"name"`
+	Command  string  `json:"command"`
+	Status   string  `json:"status"`
+	Duration float64 `json:"duration_seconds"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// hookStatus returns the report status of a hook run
+func hookStatus(run tasks.HookRun) string {
+	if run.Err != nil {
+		return StatusFailed
+	}
+	return StatusPassed
+}
+
+// jsonReport renders results as a JSON report
+func jsonReport(command string, started time.Time, results []moduleResult) ([]byte, error) {
+	report := runReport{
+		Command:   command,
+		StartedAt: started.UTC(),
+		Duration:  time.Since(started).Seconds(),
+		Modules:   []moduleReportItem{},
+	}
+
+	for _, r := range results {
+		item := moduleReportItem{
+			Name:       r.Module.Name,
+			Type:       r.Module.Type,
+			Path:       r.Module.Path,
+			Command:    command,
+			Status:     r.Status,
+			Duration:   r.Duration.Seconds(),
+			StderrTail: r.StderrTail,
+			Retries:    r.Retries,
+			TimedOut:   r.timedOut(),
+		}
+		if code, ok := r.exitCode(); ok {
+			item.ExitCode = &code
+		}
+		if r.Err != nil {
+			item.Error = r.Err.Error()
+		}
+		for _, run := range r.Hooks {
+			hook := hookReportItem{Name: run.Name, Command: run.Command, Status: hookStatus(run), Duration: run.Duration.Seconds()}
+			if run.Err != nil {
+				hook.Error = run.Err.Error()
+			}
+			item.Hooks = append(item.Hooks, hook)
+		}
+		report.Modules = append(report.Modules, item)
+
+		report.Summary.Total++
+		switch r.Status {
+		case StatusPassed:
+			report.Summary.Passed++
+		case StatusFailed:
+			report.Summary.Failed++
+		case StatusSkipped:
+			report.Summary.Skipped++
+		case StatusCancelled:
+			report.Summary.Cancelled++
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// JUnit XML report structure, as understood by GitLab, Jenkins, and most CI systems
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	Classname  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Failure    *junitMessage    `xml:"failure,omitempty"`
+	Skipped    *junitMessage    `xml:"skipped,omitempty"`
+	SystemErr  string           `xml:"system-err,omitempty"`
+}
+
+// junitProperties records the hooks run on a module, e.g.
+// <property name="hook.pre_init" value="passed"/>
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// junitReport renders results as a JUnit XML report with one test case per module
+func junitReport(command string, results []moduleResult) ([]byte, error) {
+	suite := junitTestSuite{Name: "motf " + command}
+	var total time.Duration
+
+	for _, r := range results {
+		tc := junitTestCase{
+			Name:      r.Module.Name,
+			Classname: r.Module.Path,
+			Time:      junitSeconds(r.Duration),
+			SystemErr: r.StderrTail,
+		}
+		if len(r.Hooks) > 0 {
+			tc.Properties = &junitProperties{}
+			for _, run := range r.Hooks {
+				tc.Properties.Properties = append(tc.Properties.Properties, junitProperty{Name: "hook." + run.Name, Value: hookStatus(run)})
+			}
+		}
+		switch r.Status {
+		case StatusFailed:
+			suite.Failures++
+			tc.Failure = &junitMessage{Message: r.Err.Error(), Text: r.StderrTail}
+			if code, ok := r.exitCode(); ok {
+				tc.Failure.Type = fmt.Sprintf("exit code %d", code)
+			}
+		case StatusSkipped, StatusCancelled:
+			suite.Skipped++
+			tc.Skipped = &junitMessage{Message: r.Err.Error()}
+		}
+		suite.Tests++
+		total += r.Duration
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = junitSeconds(total)
+
+	suites := junitTestSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JUnit XML: %w", err)
+	}
+	return append(append([]byte(xml.Header), data...), '\n'), nil
+}
+
+// junitSeconds formats a duration as JUnit seconds
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// tailWriter keeps the last lines written to it
+type tailWriter struct {
+	mu    sync.Mutex
+	max   int
+	lines []string
+	line  []byte
+}
+
+// newTailWriter returns a tailWriter keeping up to maxLines lines
+func newTailWriter(maxLines int) *tailWriter {
+	return &tailWriter{max: maxLines}
+}
+
+// Write implements io.Writer
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, b := range p {
+		if b != '\n' {
+			w.line = append(w.line, b)
+			continue
+		}
+		w.lines = append(w.lines, string(w.line))
+		w.line = w.line[:0]
+		if len(w.lines) > w.max {
+			w.lines = w.lines[len(w.lines)-w.max:]
+		}
+	}
+	return len(p), nil
+}
+
+// String returns the kept lines, including an unterminated last line
+func (w *tailWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	lines := w.lines
+	if len(w.line) > 0 {
+		lines = append(append([]string{}, lines...), string(w.line))
+		if len(lines) > w.max {
+			lines = lines[len(lines)-w.max:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
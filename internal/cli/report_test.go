@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
+)
+
+// fakeFailingBinary fails with exit code 3 in "broken" and succeeds elsewhere
+const fakeFailingBinary = `#!/bin/sh
+if [ "$(basename "$PWD")" = "broken" ]; then
+  echo "first line" >&2
+  echo "Error: something went wrong" >&2
+  exit 3
+fi
+exit 0
+`
+
+func TestParseReportFlags(t *testing.T) {
+	targets, err := parseReportFlags([]string{"junit=out/report.xml", "json=report.json"})
+	if err != nil {
+		t.Fatalf("parseReportFlags returned error: %v", err)
+	}
+	want := []reportTarget{
+		{format: ReportFormatJUnit, path: "out/report.xml"},
+		{format: ReportFormatJSON, path: "report.json"},
+	}
+	if !reflect.DeepEqual(targets, want) {
+		t.Errorf("parseReportFlags() = %v, want %v", targets, want)
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"report.xml", "must be <format>=<path>"},
+		{"junit=", "must be <format>=<path>"},
+		{"html=report.html", "invalid --report format 'html'"},
+	}
+	for _, tt := range tests {
+		if _, err := parseReportFlags([]string{tt.value}); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseReportFlags(%q) error = %v, want %q", tt.value, err, tt.want)
+		}
+	}
+}
+
+func TestValidateReportFlags(t *testing.T) {
+	resetFlags(t)
+	reportFlag = []string{"junit=report.xml"}
+
+	if err := validateReportFlags([]string{"storage"}); err != nil {
+		t.Errorf("expected a module name to be accepted, got %v", err)
+	}
+	if err := validateReportFlags(nil); err == nil || !strings.Contains(err.Error(), "requires module names or a selector") {
+		t.Errorf("expected selection error, got %v", err)
+	}
+
+	pathFlag = "components/storage"
+	allFlag = true
+	if err := validateReportFlags(nil); err == nil || !strings.Contains(err.Error(), "--path") {
+		t.Errorf("expected --path error, got %v", err)
+	}
+}
+
+func TestRunReports_RecordModules(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	createTerraformModule(t, tmpDir, "components/broken")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	withFakeRunner(t, fakeFailingBinary)
+
+	junitPath := filepath.Join(tmpDir, "reports", "junit.xml")
+	jsonPath := filepath.Join(tmpDir, "reports", "report.json")
+	reportFlag = []string{"junit=" + junitPath, "json=" + jsonPath}
+	commandName = "fmt"
+	allFlag = true
+
+	if err := fmtCmd.RunE(fmtCmd, nil); err == nil {
+		t.Fatal("expected an error for the failing module")
+	}
+
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("expected JSON report: %v", err)
+	}
+	var report runReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v\n%s", err, data)
+	}
+	if report.Command != "fmt" || report.Summary != (runReportSummary{Total: 2, Passed: 1, Failed: 1}) {
+		t.Errorf("unexpected report header: %+v", report)
+	}
+
+	broken := report.Modules[0]
+	if broken.Name != "broken" || broken.Type != TypeComponent || broken.Path != filepath.Join("components", "broken") {
+		t.Errorf("unexpected module entry: %+v", broken)
+	}
+	if broken.Status != StatusFailed || broken.ExitCode == nil || *broken.ExitCode != 3 {
+		t.Errorf("expected failed module with exit code 3, got %+v", broken)
+	}
+	if broken.StderrTail != "first line\nError: something went wrong" {
+		t.Errorf("stderr tail = %q", broken.StderrTail)
+	}
+	if storage := report.Modules[1]; storage.Status != StatusPassed || storage.ExitCode == nil || *storage.ExitCode != 0 {
+		t.Errorf("expected passed module with exit code 0, got %+v", storage)
+	}
+
+	data, err = os.ReadFile(junitPath)
+	if err != nil {
+		t.Fatalf("expected JUnit report: %v", err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("report is not valid XML: %v\n%s", err, data)
+	}
+	if suites.Tests != 2 || suites.Failures != 1 || len(suites.Suites) != 1 {
+		t.Fatalf("unexpected JUnit totals: %+v", suites)
+	}
+	suite := suites.Suites[0]
+	if suite.Name != "motf fmt" || suite.Cases[0].Failure == nil || suite.Cases[0].Failure.Type != "exit code 3" {
+		t.Errorf("unexpected JUnit suite: %+v", suite)
+	}
+	if suite.Cases[1].Failure != nil {
+		t.Errorf("expected passing test case, got %+v", suite.Cases[1])
+	}
+}
+
+func TestJUnitReport_Skipped(t *testing.T) {
+	results := []moduleResult{
+		{Module: ModuleInfo{Name: "app", Path: "bases/app"}, Status: StatusSkipped, Err: &skippedError{dependency: ModuleInfo{Name: "network", Path: "components/network"}}},
+	}
+
+	data, err := junitReport("plan", results)
+	if err != nil {
+		t.Fatalf("junitReport returned error: %v", err)
+	}
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Error("expected XML header")
+	}
+	if !strings.Contains(string(data), `skipped="1"`) || !strings.Contains(string(data), `<skipped message="skipped: dependency network (components/network) did not succeed">`) {
+		t.Errorf("expected skipped test case, got %s", data)
+	}
+}
+
+func TestJSONReport_Cancelled(t *testing.T) {
+	results := []moduleResult{
+		{Module: ModuleInfo{Name: "a", Path: "components/a"}, Status: StatusFailed, Err: errors.New("exit status 1")},
+		{Module: ModuleInfo{Name: "b", Path: "components/b"}, Status: StatusCancelled, Err: &cancelledError{cause: &failureLimitError{maxFailures: 1}}},
+	}
+
+	data, err := jsonReport("plan", time.Now(), results)
+	if err != nil {
+		t.Fatalf("jsonReport returned error: %v", err)
+	}
+	var report runReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if report.Summary.Failed != 1 || report.Summary.Cancelled != 1 {
+		t.Errorf("unexpected summary: %+v", report.Summary)
+	}
+	if b := report.Modules[1]; b.Status != StatusCancelled || b.ExitCode != nil || b.Error != "cancelled: not started, the run stopped after 1 failed module(s)" {
+		t.Errorf("unexpected cancelled module: %+v", b)
+	}
+}
+
+func TestJSONReport_RetriesAndTimeouts(t *testing.T) {
+	results := []moduleResult{
+		{Module: ModuleInfo{Name: "a", Path: "components/a"}, Status: StatusPassed, Retries: 2},
+		{Module: ModuleInfo{Name: "b", Path: "components/b"}, Status: StatusFailed, Err: &process.TimeoutError{Timeout: time.Minute, Err: errors.New("exit status 1")}},
+	}
+
+	data, err := jsonReport("init", time.Now(), results)
+	if err != nil {
+		t.Fatalf("jsonReport returned error: %v", err)
+	}
+	var report runReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if a := report.Modules[0]; a.Retries != 2 || a.TimedOut {
+		t.Errorf("unexpected retried module: %+v", a)
+	}
+	if b := report.Modules[1]; !b.TimedOut || b.Error != "timed out after 1m0s (exit status 1)" {
+		t.Errorf("unexpected timed out module: %+v", b)
+	}
+}
+
+func TestModuleResult_ExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		result moduleResult
+		code   int
+		ok     bool
+	}{
+		{"passed", moduleResult{Status: StatusPassed}, 0, true},
+		{"failed without process", moduleResult{Status: StatusFailed, Err: errors.New("boom")}, 1, true},
+		{"skipped", moduleResult{Status: StatusSkipped, Err: errors.New("skipped")}, 0, false},
+		{"cancelled before starting", moduleResult{Status: StatusCancelled, Err: &cancelledError{cause: &failureLimitError{maxFailures: 1}}}, 0, false},
+		{"interrupted", moduleResult{Status: StatusCancelled, Err: &cancelledError{cause: &failureLimitError{maxFailures: 1}, err: context.Canceled}}, 1, true},
+	}
+	for _, tt := range tests {
+		code, ok := tt.result.exitCode()
+		if code != tt.code || ok != tt.ok {
+			t.Errorf("%s: exitCode() = %d, %v, want %d, %v", tt.name, code, ok, tt.code, tt.ok)
+		}
+	}
+}
+
+func TestTailWriter(t *testing.T) {
+	w := newTailWriter(2)
+	_, _ = w.Write([]byte("one\ntwo\nth"))
+	_, _ = w.Write([]byte("ree\nfour"))
+
+	if got := w.String(); got != "three\nfour" {
+		t.Errorf("String() = %q, want %q", got, "three\nfour")
+	}
+}
+
+func TestJSONReport_Duration(t *testing.T) {
+	started := time.Now().Add(-2 * time.Second)
+	data, err := jsonReport("test", started, nil)
+	if err != nil {
+		t.Fatalf("jsonReport returned error: %v", err)
+	}
+	var report runReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if report.Duration < 2 || report.Modules == nil {
+		t.Errorf("unexpected empty report: %+v", report)
+	}
+}
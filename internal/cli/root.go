@@ -1,10 +1,12 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
 	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
 	"github.com/spf13/cobra"
 )
@@ -20,6 +22,10 @@ var (
 	cfg    *config.Config
 	runner *terraform.Runner
 
+	// runContext is cancelled when motf receives SIGINT or SIGTERM, which
+	// interrupts the commands started with it. A second signal kills them.
+	runContext = context.Background()
+
 	// Global flags (persistent across all commands)
 	pathFlag   string   // Explicit path to module
 	argsFlag   []string // Extra arguments passed to terraform/tofu
@@ -28,13 +34,19 @@ var (
 	// Command-specific flags
 	// Note: These are registered per-command but share state here for simplicity.
 	// Each command that uses these flags registers them in its own init().
-	initFlag        bool   // Run init before the command (fmt, validate)
-	changedFlag     bool   // Run command against changed modules
-	refFlag         string // Ref for change detection (defaults to auto-detect)
-	searchFlag      string // Filter pattern for list command
-	exampleFlag     string // Target a specific example instead of the module (init, fmt, validate)
-	parallelFlag    bool   // Run commands in parallel (init, fmt, validate, test, plan, task)
-	maxParallelFlag int    // Maximum parallel jobs to run (default: number of CPU cores)
+	initFlag        bool     // Run init before the command (fmt, validate)
+	changedFlag     bool     // Run command against changed modules
+	dependentsFlag  bool     // Include modules that transitively depend on changed modules
+	dagFlag         bool     // Run modules in dependency order, skipping dependents of failures
+	refFlag         string   // Ref for change detection (defaults to auto-detect)
+	allFlag         bool     // Run command against all modules
+	typeFlag        string   // Filter modules by type (component, base, project)
+	searchFlag      string   // Filter modules by name using wildcards
+	tagFlag         []string // Filter modules by tags from .motf.module.yml
+	exampleFlag     string   // Target a specific example instead of the module (init, fmt, validate)
+	parallelFlag    bool     // Run commands in parallel (init, fmt, validate, test, plan, task)
+	maxParallelFlag int      // Maximum parallel jobs to run (default: number of CPU cores)
+	yesFlag         bool     // Skip approval prompts for apply and destroy
 )
 
 // versionTemplate returns the version string with commit and date.
@@ -51,8 +63,9 @@ var rootCmd = &cobra.Command{
 	Version: version,
 	Long: `motf (Terraform Monorepo Orchestrator) is a CLI tool for working with Terraform monorepos.
 
-It supports running terraform/tofu commands on components, bases, and projects organized
-in a structured monorepo.`,
+It supports running terraform/tofu commands on modules organized in a structured
+monorepo: components, bases, and projects by default, or the kinds configured in
+module_kinds.`,
 	Example: `  motf fmt storage-account         # Run fmt on storage-account (searches all types)
   motf val k8s-argocd              # Run validate on k8s-argocd
   motf val -i k8s-argocd           # Run init then validate on k8s-argocd
@@ -71,6 +84,28 @@ in a structured monorepo.`,
 			return err
 		}
 
+		if dependentsFlag && !changedFlag {
+			return fmt.Errorf("--dependents requires --changed")
+		}
+
+		if err := validateOutputMode(outputModeFlag); err != nil {
+			return err
+		}
+		if _, err := resolveMaxFailures(); err != nil {
+			return err
+		}
+		if ciProvider, err = resolveCIProvider(ciFlag); err != nil {
+			return err
+		}
+
+		commandName = cmd.Name()
+		if err := validateReportFlags(args); err != nil {
+			return err
+		}
+		if err := validateLogDirFlag(args); err != nil {
+			return err
+		}
+
 		// Merge CLI flags into config (CLI takes priority)
 		// Centralize the "CLI overrides config" logic here
 		if cmd.Flags().Changed("max-parallel") {
@@ -79,6 +114,10 @@ in a structured monorepo.`,
 			}
 			cfg.Parallelism.MaxJobs = maxParallelFlag
 		}
+		if err := applyExecutionFlags(cmd, cfg); err != nil {
+			return err
+		}
+		applyCacheFlag(cmd, cfg)
 
 		// Create terraform runner with config
 		runner = terraform.NewRunner(cfg)
@@ -99,5 +138,18 @@ func init() {
 
 // Execute runs the root command
 func Execute() error {
+	ctx, stop := process.NotifyContext(context.Background(), reportSignal)
+	defer stop()
+	runContext = ctx
 	return rootCmd.Execute()
 }
+
+// reportSignal tells the user how motf reacts to a received signal
+func reportSignal(sig os.Signal, force bool) {
+	name := process.SignalName(sig)
+	if force {
+		_, _ = fmt.Fprintf(os.Stderr, "\nReceived %s again, killing running commands\n", name)
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "\nReceived %s, interrupting running commands (send it again to kill them)\n", name)
+}
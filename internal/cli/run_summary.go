@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
+)
+
+// noSummaryFlag disables the summary table printed after multi-module runs
+var noSummaryFlag bool
+
+// summaryStatusOrder sorts failed modules first, then cancelled, skipped, and passed
+var summaryStatusOrder = map[string]int{
+	StatusFailed:    0,
+	StatusCancelled: 1,
+	StatusSkipped:   2,
+	StatusPassed:    3,
+}
+
+// summaryStatusLabel returns the label of a status in the summary table
+func summaryStatusLabel(status string) string {
+	if status == StatusPassed {
+		return "ok"
+	}
+	return status
+}
+
+// printRunSummary writes a table of module results sorted by status, followed by
+// totals and the wall-clock time of the run compared to the time summed across modules.
+func printRunSummary(w io.Writer, results []moduleResult, wall time.Duration) {
+	sorted := sortedForSummary(results)
+
+	nameWidth := len("MODULE")
+	statusWidth := len(StatusSkipped)
+	retried := false
+	for _, r := range sorted {
+		if len(r.Module.Name) > nameWidth {
+			nameWidth = len(r.Module.Name)
+		}
+		if len(r.Status) > statusWidth {
+			statusWidth = len(r.Status)
+		}
+		retried = retried || r.Retries > 0
+	}
+
+	// The RETRIES column is only shown when a command was retried
+	retriesColumn := func(value string) string {
+		if !retried {
+			return ""
+		}
+		return fmt.Sprintf("%7s  ", value)
+	}
+
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Run summary:")
+	_, _ = fmt.Fprintf(w, "%-*s  %-*s  %8s  %s%s\n", nameWidth, "MODULE", statusWidth, "STATUS", "DURATION", retriesColumn("RETRIES"), "ERROR")
+	for _, r := range sorted {
+		duration := "-"
+		if r.started() {
+			duration = formatSeconds(r.Duration)
+		}
+		line := fmt.Sprintf("%-*s  %-*s  %8s  %s%s", nameWidth, r.Module.Name, statusWidth, summaryStatusLabel(r.Status), duration, retriesColumn(strconv.Itoa(r.Retries)), firstErrorLine(r))
+		_, _ = fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%s\n", summaryTotals(results, wall))
+}
+
+// sortedForSummary returns a copy of results sorted by status, keeping the
+// run order within each status
+func sortedForSummary(results []moduleResult) []moduleResult {
+	sorted := make([]moduleResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return summaryStatusOrder[sorted[i].Status] < summaryStatusOrder[sorted[j].Status]
+	})
+	return sorted
+}
+
+// summaryTotals returns the line counting results by status and comparing the
+// wall-clock time of the run with the time summed across modules. Cancelled
+// modules are only counted when there are any.
+func summaryTotals(results []moduleResult, wall time.Duration) string {
+	counts := make(map[string]int)
+	var summed time.Duration
+	for _, r := range results {
+		counts[r.Status]++
+		summed += r.Duration
+	}
+	cancelled := ""
+	if counts[StatusCancelled] > 0 {
+		cancelled = fmt.Sprintf(", %d cancelled", counts[StatusCancelled])
+	}
+	return fmt.Sprintf("%d modules: %d ok, %d failed, %d skipped%s in %s (%s summed across modules)",
+		len(results), counts[StatusPassed], counts[StatusFailed], counts[StatusSkipped], cancelled, formatSeconds(wall), formatSeconds(summed))
+}
+
+// firstErrorLine returns the line explaining why a module did not succeed: the
+// timeout for commands that timed out, the first "Error" line of its stderr
+// tail if there is one, otherwise the first line of its error. It is empty for
+// modules that succeeded.
+func firstErrorLine(r moduleResult) string {
+	if r.Err == nil {
+		return ""
+	}
+	var timeout *process.TimeoutError
+	if errors.As(r.Err, &timeout) {
+		return timeout.Error()
+	}
+	if r.Status == StatusFailed {
+		for _, line := range strings.Split(r.StderrTail, "\n") {
+			// terraform frames diagnostics with a box-drawing border
+			if line = strings.TrimSpace(strings.TrimLeft(line, "│ ")); strings.HasPrefix(line, "Error") {
+				return line
+			}
+		}
+	}
+	line, _, _ := strings.Cut(r.Err.Error(), "\n")
+	return line
+}
+
+// printInterruptReport writes which modules were interrupted, or not started,
+// because motf received a signal during the run
+func printInterruptReport(w io.Writer, sig *process.SignalError, results []moduleResult) {
+	var interrupted, notStarted []moduleResult
+	for _, r := range results {
+		if r.Status != StatusCancelled {
+			continue
+		}
+		if r.started() {
+			interrupted = append(interrupted, r)
+		} else {
+			notStarted = append(notStarted, r)
+		}
+	}
+	finished := len(results) - len(interrupted) - len(notStarted)
+
+	_, _ = fmt.Fprintf(w, "\nInterrupted by %s: %d module(s) interrupted, %d not started, %d finished\n",
+		process.SignalName(sig.Signal), len(interrupted), len(notStarted), finished)
+	for _, r := range interrupted {
+		_, _ = fmt.Fprintf(w, "  interrupted  %s (%s)\n", r.Module.Name, r.Module.Path)
+	}
+	for _, r := range notStarted {
+		_, _ = fmt.Fprintf(w, "  not started  %s (%s)\n", r.Module.Name, r.Module.Path)
+	}
+}
+
+// formatSeconds formats a duration as seconds with one decimal, e.g. 2.5s
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
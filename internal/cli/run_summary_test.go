@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
+)
+
+func TestPrintRunSummary(t *testing.T) {
+	results := []moduleResult{
+		{Module: ModuleInfo{Name: "naming"}, Status: StatusPassed, Duration: 1500 * time.Millisecond},
+		{Module: ModuleInfo{Name: "app"}, Status: StatusSkipped, Err: &skippedError{dependency: ModuleInfo{Name: "storage-account", Path: "components/storage-account"}}},
+		{
+			Module:     ModuleInfo{Name: "storage-account"},
+			Status:     StatusFailed,
+			Duration:   2 * time.Second,
+			StderrTail: "╷\n│ Error: Unsupported argument\n│\n╵",
+			Err:        errors.New("exit status 1"),
+		},
+	}
+
+	var buf bytes.Buffer
+	printRunSummary(&buf, results, 3*time.Second)
+
+	want := `
+Run summary:
+MODULE           STATUS   DURATION  ERROR
+storage-account  failed       2.0s  Error: Unsupported argument
+app              skipped         -  skipped: dependency storage-account (components/storage-account) did not succeed
+naming           ok           1.5s
+
+3 modules: 1 ok, 1 failed, 1 skipped in 3.0s (3.5s summed across modules)
+`
+	if buf.String() != want {
+		t.Errorf("printRunSummary() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestPrintRunSummary_Cancelled(t *testing.T) {
+	results := []moduleResult{
+		{Module: ModuleInfo{Name: "a"}, Status: StatusFailed, Duration: time.Second, Err: errors.New("exit status 1")},
+		{Module: ModuleInfo{Name: "b"}, Status: StatusCancelled, Duration: time.Second, Err: &cancelledError{cause: &failureLimitError{maxFailures: 1}, err: errors.New("exit status 130")}},
+		{Module: ModuleInfo{Name: "c"}, Status: StatusCancelled, Err: &cancelledError{cause: &failureLimitError{maxFailures: 1}}},
+	}
+
+	var buf bytes.Buffer
+	printRunSummary(&buf, results, 2*time.Second)
+
+	want := `
+Run summary:
+MODULE  STATUS     DURATION  ERROR
+a       failed         1.0s  exit status 1
+b       cancelled      1.0s  cancelled: interrupted, the run stopped after 1 failed module(s) (exit status 130)
+c       cancelled         -  cancelled: not started, the run stopped after 1 failed module(s)
+
+3 modules: 0 ok, 1 failed, 0 skipped, 2 cancelled in 2.0s (2.0s summed across modules)
+`
+	if buf.String() != want {
+		t.Errorf("printRunSummary() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestPrintRunSummary_Retries(t *testing.T) {
+	results := []moduleResult{
+		{Module: ModuleInfo{Name: "network"}, Status: StatusPassed, Duration: 3 * time.Second, Retries: 2},
+		{
+			Module:     ModuleInfo{Name: "app"},
+			Status:     StatusFailed,
+			Duration:   time.Second,
+			StderrTail: "Error: Operation cancelled",
+			Err:        &process.TimeoutError{Timeout: time.Second, Err: errors.New("exit status 1")},
+		},
+	}
+
+	var buf bytes.Buffer
+	printRunSummary(&buf, results, 4*time.Second)
+
+	want := `
+Run summary:
+MODULE   STATUS   DURATION  RETRIES  ERROR
+app      failed       1.0s        0  timed out after 1s (exit status 1)
+network  ok           3.0s        2
+
+2 modules: 1 ok, 1 failed, 0 skipped in 4.0s (4.0s summed across modules)
+`
+	if buf.String() != want {
+		t.Errorf("printRunSummary() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestFirstErrorLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		result moduleResult
+		want   string
+	}{
+		{"passed", moduleResult{Status: StatusPassed}, ""},
+		{"error line in stderr", moduleResult{Status: StatusFailed, StderrTail: "Initializing...\nError: Failed to install provider", Err: errors.New("exit status 1")}, "Error: Failed to install provider"},
+		{"no error line", moduleResult{Status: StatusFailed, StderrTail: "boom", Err: errors.New("exit status 2\ndetails")}, "exit status 2"},
+	}
+	for _, tt := range tests {
+		if got := firstErrorLine(tt.result); got != tt.want {
+			t.Errorf("%s: firstErrorLine() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunOnModulesParallel_Summary(t *testing.T) {
+	resetFlags(t)
+	withConfig(t, &config.Config{Root: t.TempDir(), Binary: "terraform"})
+
+	// The summary goes to os.Stderr
+	captureStderr := func(fn func()) string {
+		return captureOutput(t, &os.Stderr, fn)
+	}
+
+	modules := []ModuleInfo{{Name: "a", Path: "components/a"}, {Name: "b", Path: "components/b"}}
+	noop := func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error { return nil }
+
+	out := captureStderr(func() { _ = RunOnModulesParallel(modules, nil, noop) })
+	if !strings.Contains(out, "Run summary:") || !strings.Contains(out, "2 modules: 2 ok, 0 failed, 0 skipped") {
+		t.Errorf("expected run summary, got: %s", out)
+	}
+
+	out = captureStderr(func() { _ = RunOnModulesParallel(modules[:1], nil, noop) })
+	if strings.Contains(out, "Run summary:") {
+		t.Errorf("expected no summary for a single module, got: %s", out)
+	}
+
+	noSummaryFlag = true
+	out = captureStderr(func() { _ = RunOnModulesParallel(modules, nil, noop) })
+	if strings.Contains(out, "Run summary:") {
+		t.Errorf("expected no summary with --no-summary, got: %s", out)
+	}
+}
+
+func TestPrintInterruptReport(t *testing.T) {
+	results := []moduleResult{
+		{Module: ModuleInfo{Name: "a", Path: "components/a"}, Status: StatusPassed},
+		{Module: ModuleInfo{Name: "b", Path: "components/b"}, Status: StatusCancelled, Err: &cancelledError{cause: &process.SignalError{Signal: os.Interrupt}, err: errors.New("exit status 130")}},
+		{Module: ModuleInfo{Name: "c", Path: "components/c"}, Status: StatusCancelled, Err: &cancelledError{cause: &process.SignalError{Signal: os.Interrupt}}},
+	}
+
+	var buf bytes.Buffer
+	printInterruptReport(&buf, &process.SignalError{Signal: os.Interrupt}, results)
+
+	want := `
+Interrupted by SIGINT: 1 module(s) interrupted, 1 not started, 1 finished
+  interrupted  b (components/b)
+  not started  c (components/c)
+`
+	if buf.String() != want {
+		t.Errorf("printInterruptReport() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestRunOnModulesParallel_Interrupted(t *testing.T) {
+	resetFlags(t)
+	withConfig(t, &config.Config{Root: t.TempDir(), Binary: "terraform"})
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(&process.SignalError{Signal: syscall.SIGTERM})
+	runContext = ctx
+
+	ran := false
+	modules := []ModuleInfo{{Name: "a", Path: "components/a"}, {Name: "b", Path: "components/b"}}
+	var runErr error
+	out := captureOutput(t, &os.Stderr, func() {
+		_ = captureOutput(t, &os.Stdout, func() {
+			runErr = RunOnModulesParallel(modules, nil, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+				ran = true
+				return nil
+			})
+		})
+	})
+
+	if ran {
+		t.Error("expected no module to run after a signal")
+	}
+	if runErr == nil || !strings.Contains(runErr.Error(), "a (components/a): cancelled: not started, received SIGTERM") {
+		t.Errorf("expected cancelled modules in error, got %v", runErr)
+	}
+	if !strings.Contains(out, "Interrupted by SIGTERM: 0 module(s) interrupted, 2 not started, 0 finished") {
+		t.Errorf("expected interrupt report, got: %s", out)
+	}
+}
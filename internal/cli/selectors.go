@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+)
+
+// usesModuleSelection reports whether the command targets a set of modules
+// (via --changed, --all, --type, --search, --tag, or several module names)
+// rather than a single module or --path. A single module name is treated as a
+// selection when --report, --log-dir, or --cache is set, so the run can be
+// reported, logged, and cached.
+func usesModuleSelection(args []string) bool {
+	return changedFlag || allFlag || typeFlag != "" || searchFlag != "" || len(tagFlag) > 0 || len(args) > 1 ||
+		((len(reportFlag) > 0 || logDirFlag != "" || cacheFlag) && len(args) > 0)
+}
+
+// selectModules resolves the modules targeted by module name arguments and the
+// selector flags (--changed, --all, --type, --search, --tag). The result is sorted by path.
+//
+// --changed and --all pick the candidate set; module names pick an explicit set.
+// --type, --search, and --tag then filter the candidates. Using them on their
+// own filters all modules.
+func selectModules(args []string) ([]ModuleInfo, error) {
+	if pathFlag != "" {
+		return nil, fmt.Errorf("--path cannot be used with --changed, --all, --type, --search, --tag, or multiple module names")
+	}
+	if exampleFlag != "" {
+		return nil, fmt.Errorf("--example cannot be used with --changed, --all, --type, --search, --tag, or multiple module names")
+	}
+	if changedFlag && allFlag {
+		return nil, fmt.Errorf("--changed and --all are mutually exclusive")
+	}
+	if len(args) > 0 && changedFlag {
+		return nil, fmt.Errorf("--changed cannot be used with module names")
+	}
+	if len(args) > 0 && allFlag {
+		return nil, fmt.Errorf("--all cannot be used with module names")
+	}
+	if err := validateModuleType(typeFlag); err != nil {
+		return nil, err
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []ModuleInfo
+	switch {
+	case changedFlag:
+		modules, err = detectChangedModules(refFlag)
+	case len(args) > 0:
+		modules, err = modulesByName(basePath, args)
+	default:
+		modules, err = collectModules(basePath, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyModuleConfigs(basePath, modules); err != nil {
+		return nil, err
+	}
+
+	modules = filterModules(modules, typeFlag, searchFlag, tagFlag)
+	sortModules(modules)
+	return modules, nil
+}
+
+// modulesByName resolves each module name to its ModuleInfo, ignoring duplicates
+func modulesByName(basePath string, names []string) ([]ModuleInfo, error) {
+	var modules []ModuleInfo
+	seen := make(map[string]bool)
+
+	for _, name := range names {
+		absPath, err := findModuleInAllDirs(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[absPath] {
+			continue
+		}
+		seen[absPath] = true
+		modules = append(modules, moduleInfoFromPath(basePath, absPath))
+	}
+
+	return modules, nil
+}
+
+// moduleInfoFromPath builds a ModuleInfo for the module at absPath
+func moduleInfoFromPath(basePath, absPath string) ModuleInfo {
+	relPath, err := filepath.Rel(basePath, absPath)
+	if err != nil {
+		relPath = absPath
+	}
+	return ModuleInfo{
+		Name: filepath.Base(absPath),
+		Type: getModuleType(absPath),
+		Path: relPath,
+	}
+}
+
+// filterModules keeps modules matching moduleType (if set), the name wildcard
+// (if set), and every tag in tags. Tags must already be loaded with applyModuleConfigs.
+func filterModules(modules []ModuleInfo, moduleType, search string, tags []string) []ModuleInfo {
+	if moduleType == "" && search == "" && len(tags) == 0 {
+		return modules
+	}
+
+	var filtered []ModuleInfo
+	for _, mod := range modules {
+		if moduleType != "" && mod.Type != moduleType {
+			continue
+		}
+		if search != "" && !finder.MatchesWildcard(mod.Name, search) {
+			continue
+		}
+		if !hasAllTags(mod, tags) {
+			continue
+		}
+		filtered = append(filtered, mod)
+	}
+	return filtered
+}
+
+// validateModuleType returns an error if moduleType is set but not a known module type
+func validateModuleType(moduleType string) error {
+	if moduleType == "" {
+		return nil
+	}
+	if _, ok := moduleTypeOrder(moduleType); !ok {
+		return fmt.Errorf("invalid module type '%s': must be one of %s", moduleType, strings.Join(moduleTypeNames(), ", "))
+	}
+	return nil
+}
+
+// runOnSelectedModules selects modules with selectModules and runs fn on each.
+// When parallelFlag is set, modules are processed concurrently.
+// It is a no-op (success) when no modules are selected.
+//
+// The function signature for fn receives stdout/stderr writers to support
+// prefixed output in parallel mode.
+func runOnSelectedModules(args []string, fn ModuleRunner) error {
+	modules, err := selectModules(args)
+	if err != nil {
+		return err
+	}
+	return runOnModuleSet(modules, fn)
+}
+
+// runOnSelectedModulesWithPath is a convenience wrapper for commands that need
+// the module's absolute path. It wraps fn to provide the path from ModuleInfo.
+func runOnSelectedModulesWithPath(args []string, fn func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error) error {
+	modules, err := selectModules(args)
+	if err != nil {
+		return err
+	}
+	return runOnModuleSetWithPath(modules, fn)
+}
+
+// runOnModuleSet runs fn on already selected modules, honoring --parallel and --dag.
+// Commands that need to check the selection before running (e.g. destroy)
+// call selectModules themselves and then use this.
+func runOnModuleSet(modules []ModuleInfo, fn ModuleRunner) error {
+	if len(modules) == 0 {
+		if changedFlag {
+			fmt.Println("No changed modules found")
+		} else {
+			fmt.Println("No modules matched the selection")
+		}
+		return nil
+	}
+
+	var parallelismCfg *config.ParallelismConfig
+	if cfg != nil {
+		parallelismCfg = cfg.Parallelism
+	}
+
+	return RunOnModulesParallel(modules, parallelismCfg, fn)
+}
+
+// runOnModuleSetWithPath is runOnModuleSet for functions that need the module's absolute path.
+func runOnModuleSetWithPath(modules []ModuleInfo, fn func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	return runOnModuleSet(modules, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+		moduleAbsPath := filepath.Join(basePath, mod.Path)
+		return fn(ctx, moduleAbsPath, stdout, stderr)
+	})
+}
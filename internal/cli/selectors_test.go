@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// setupSelectionTree creates two components, a base, and a project.
+func setupSelectionTree(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	createTerraformModule(t, tmpDir, "components/storage-account")
+	createTerraformModule(t, tmpDir, "components/key-vault")
+	createTerraformModule(t, tmpDir, "bases/app")
+	createTerraformModule(t, tmpDir, "projects/prod")
+
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	return tmpDir
+}
+
+func moduleNames(modules []ModuleInfo) []string {
+	names := make([]string, 0, len(modules))
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	return names
+}
+
+func TestRunCommands_HaveSelectorFlags(t *testing.T) {
+	for _, cmd := range []*cobra.Command{fmtCmd, valCmd, initCmd, planCmd, testCmd, taskCmd} {
+		for _, name := range []string{"all", "type", "search", "tag"} {
+			if cmd.Flags().Lookup(name) == nil {
+				t.Errorf("%s should have --%s flag", cmd.Name(), name)
+			}
+		}
+		if err := cmd.Args(cmd, []string{"a", "b", "c"}); err != nil {
+			t.Errorf("%s should accept multiple module names: %v", cmd.Name(), err)
+		}
+	}
+}
+
+func TestUsesModuleSelection(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func()
+		args  []string
+		want  bool
+	}{
+		{"single module", func() {}, []string{"storage-account"}, false},
+		{"no args", func() {}, nil, false},
+		{"multiple modules", func() {}, []string{"a", "b"}, true},
+		{"all", func() { allFlag = true }, nil, true},
+		{"type", func() { typeFlag = TypeComponent }, nil, true},
+		{"search", func() { searchFlag = "*storage*" }, nil, true},
+		{"tag", func() { tagFlag = []string{"storage"} }, nil, true},
+		{"changed", func() { changedFlag = true }, nil, true},
+		{"single module with report", func() { reportFlag = []string{"junit=report.xml"} }, []string{"storage-account"}, true},
+		{"report without module", func() { reportFlag = []string{"junit=report.xml"} }, nil, false},
+		{"single module with cache", func() { cacheFlag = true }, []string{"storage-account"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			tt.setup()
+			if got := usesModuleSelection(tt.args); got != tt.want {
+				t.Errorf("usesModuleSelection(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectModules(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func()
+		args  []string
+		want  []string
+	}{
+		{"all", func() { allFlag = true }, nil, []string{"app", "key-vault", "storage-account", "prod"}},
+		{"type", func() { typeFlag = TypeComponent }, nil, []string{"key-vault", "storage-account"}},
+		{"search", func() { searchFlag = "*a*" }, nil, []string{"app", "key-vault", "storage-account"}},
+		{"all with type and search", func() { allFlag = true; typeFlag = TypeComponent; searchFlag = "*storage*" }, nil, []string{"storage-account"}},
+		{"names", func() {}, []string{"prod", "storage-account"}, []string{"storage-account", "prod"}},
+		{"duplicate names", func() {}, []string{"prod", "prod"}, []string{"prod"}},
+		{"names with type", func() { typeFlag = TypeProject }, []string{"prod", "storage-account"}, []string{"prod"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			setupSelectionTree(t)
+			tt.setup()
+
+			modules, err := selectModules(tt.args)
+			if err != nil {
+				t.Fatalf("selectModules returned error: %v", err)
+			}
+			if got := moduleNames(modules); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectModules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectModules_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func()
+		args    []string
+		wantErr string
+	}{
+		{"all with names", func() { allFlag = true }, []string{"prod"}, "--all cannot be used with module names"},
+		{"changed with names", func() { changedFlag = true }, []string{"a", "b"}, "--changed cannot be used with module names"},
+		{"changed with all", func() { changedFlag = true; allFlag = true }, nil, "mutually exclusive"},
+		{"path", func() { allFlag = true; pathFlag = "/tmp" }, nil, "--path cannot be used"},
+		{"example", func() { allFlag = true; exampleFlag = "basic" }, nil, "--example cannot be used"},
+		{"invalid type", func() { typeFlag = "widget" }, nil, "invalid module type 'widget'"},
+		{"unknown name", func() {}, []string{"prod", "missing"}, "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			setupSelectionTree(t)
+			tt.setup()
+
+			_, err := selectModules(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunOnSelectedModules_RunsEachModule(t *testing.T) {
+	resetFlags(t)
+	setupSelectionTree(t)
+	typeFlag = TypeComponent
+
+	var ran []string
+	err := runOnSelectedModules(nil, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+		ran = append(ran, mod.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("runOnSelectedModules returned error: %v", err)
+	}
+
+	if want := []string{"key-vault", "storage-account"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+}
+
+func TestRunOnSelectedModules_NoMatches(t *testing.T) {
+	resetFlags(t)
+	setupSelectionTree(t)
+	searchFlag = "*nothing*"
+
+	err := runOnSelectedModules(nil, func(ctx context.Context, mod ModuleInfo, stdout, stderr io.Writer) error {
+		t.Errorf("fn should not be called, got %s", mod.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("runOnSelectedModules returned error: %v", err)
+	}
+}
+
+func TestValidateModuleType(t *testing.T) {
+	for _, valid := range []string{"", TypeComponent, TypeBase, TypeProject} {
+		if err := validateModuleType(valid); err != nil {
+			t.Errorf("validateModuleType(%q) returned error: %v", valid, err)
+		}
+	}
+	if err := validateModuleType("components"); err == nil {
+		t.Error("validateModuleType(\"components\") should return an error")
+	}
+}
@@ -1,28 +1,39 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"path/filepath"
 	"sort"
+	"strings"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 	"github.com/TechnicallyJoe/terraform-motf/internal/git"
 	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
 	"github.com/spf13/cobra"
 )
 
 var (
 	taskFlag     string
 	listTaskFlag bool
+	paramFlag    []string
 )
 
 var taskCmd = &cobra.Command{
-	Use:   "task [module-name]",
+	Use:   "task [module-name...]",
 	Short: "Run a custom task from .motf.yml",
 	Long: `Run a custom task defined in .motf.yml on a module.
 
 Tasks are shell commands configured in your .motf.yml file under the 'tasks' section.
 By default, or with --list, shows all available tasks.
 
+Several modules can be selected at once by passing multiple module names or
+using --all, --type, --search, --tag, or --changed. Selected modules run through the
+same pipeline as --changed (supporting --parallel and --dag).
+
 Examples:
   motf task storage-account                    # List available tasks
   motf task storage-account --list             # List available tasks
@@ -31,57 +42,122 @@ Examples:
   motf task storage-account -t lint -e basic   # Run 'lint' task on 'basic' example
   motf task --path ./modules/x -t docs         # Run task on explicit path
   motf task -t lint --changed                  # Run 'lint' task on changed modules
-  motf task -t lint --changed --parallel       # Run 'lint' task on changed modules in parallel`,
-	Args: cobra.MaximumNArgs(1),
+  motf task -t lint --changed --parallel       # Run 'lint' task on changed modules in parallel
+  motf task -t lint storage-account key-vault  # Run 'lint' task on several modules
+  motf task -t lint --all                      # Run 'lint' task on all modules
+  motf task app -t bump --param level=minor    # Run 'bump' task with a param`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// If no task specified, list tasks
 		if taskFlag == "" || listTaskFlag {
-			return listTasks()
+			return listTasks(args)
 		}
 
-		// Get git root (soft fail - empty string if not in git repo)
-		gitRoot, _ := git.GetRepoRoot()
+		// Check params before running on any module
+		params, err := tasks.ParseParams(paramFlag)
+		if err != nil {
+			return err
+		}
+		checkRunner := tasks.NewRunner(cfg.Tasks, nil).WithBuiltins(taskBuiltins(runner)).WithParams(params)
+		if err := checkRunner.CheckParams(taskFlag); err != nil {
+			return err
+		}
 
-		if changedFlag {
-			if exampleFlag != "" {
-				return fmt.Errorf("--changed cannot be used with --example")
-			}
-			if len(args) > 0 {
-				return cobra.MaximumNArgs(0)(cmd, args)
+		gitRoot, err := repoRoot()
+		if err != nil {
+			return err
+		}
+
+		if usesModuleSelection(args) {
+			commandName = "task " + taskFlag
+			spec, err := taskCacheSpec(checkRunner, taskFlag)
+			if err != nil {
+				return err
 			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
-				taskRunner := tasks.NewRunner(cfg.Tasks, buildTaskEnv(gitRoot, moduleAbsPath))
-				return taskRunner.RunWithOutput(taskFlag, moduleAbsPath, stdout, stderr)
+			run := withCache(spec, func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error {
+				taskRunner, err := moduleTaskRunner(ctx, gitRoot, moduleAbsPath)
+				if err != nil {
+					return err
+				}
+				return taskRunner.WithParams(params).RunWithOutput(taskFlag, moduleAbsPath, stdout, stderr)
+			})
+			return runOnSelectedModulesWithPath(args, func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error {
+				reason, err := taskSkipReason(taskFlag, moduleAbsPath)
+				if err != nil {
+					return err
+				}
+				if reason != "" {
+					return &notApplicableError{reason: reason}
+				}
+				return run(ctx, moduleAbsPath, stdout, stderr)
 			})
 		}
 
+		if err := checkCacheFlag(); err != nil {
+			return err
+		}
+
 		// Resolve module path (with optional example)
 		targetPath, err := resolveTargetWithExample(args, exampleFlag)
 		if err != nil {
 			return err
 		}
 
+		reason, err := taskSkipReason(taskFlag, targetPath)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			fmt.Printf("Skipped: %s\n", reason)
+			return nil
+		}
+
 		// Run the task
-		taskRunner := tasks.NewRunner(cfg.Tasks, buildTaskEnv(gitRoot, targetPath))
-		return taskRunner.Run(taskFlag, targetPath)
+		taskRunner, err := moduleTaskRunner(runContext, gitRoot, targetPath)
+		if err != nil {
+			return err
+		}
+		return taskRunner.WithParams(params).Run(taskFlag, targetPath)
 	},
 }
 
-func listTasks() error {
+// listTasks prints the defined tasks. Given a single module (or --path, and
+// optionally --example), it prints only the tasks that apply to it.
+func listTasks(args []string) error {
 	if len(cfg.Tasks) == 0 {
 		fmt.Println("No tasks defined in .motf.yml")
 		return nil
 	}
 
-	fmt.Println("Available tasks:")
+	var target *tasks.Target
+	if len(args) == 1 || pathFlag != "" {
+		targetPath, err := resolveTargetWithExample(args, exampleFlag)
+		if err != nil {
+			return err
+		}
+		t, err := taskTarget(targetPath)
+		if err != nil {
+			return err
+		}
+		target = &t
+	}
 
 	// Sort task names for consistent output
 	names := make([]string, 0, len(cfg.Tasks))
-	for name := range cfg.Tasks {
+	for name, task := range cfg.Tasks {
+		if target != nil && task != nil && task.SkipReason(*target) != "" {
+			continue
+		}
 		names = append(names, name)
 	}
 	sort.Strings(names)
 
+	if len(names) == 0 {
+		fmt.Printf("No tasks apply to %s\n", target.Path)
+		return nil
+	}
+	fmt.Println("Available tasks:")
+
 	for _, name := range names {
 		task := cfg.Tasks[name]
 		if task.Description != "" {
@@ -89,28 +165,197 @@ func listTasks() error {
 		} else {
 			fmt.Printf("  %s\n", name)
 		}
+		params := make([]string, 0, len(task.Params))
+		for param := range task.Params {
+			params = append(params, param)
+		}
+		sort.Strings(params)
+		for _, param := range params {
+			fmt.Printf("      --param %s\n", describeParam(param, task.Params[param]))
+		}
 	}
 	return nil
 }
 
+// taskCacheSpec returns what the result of running name with the params of
+// r depends on, for the cache
+func taskCacheSpec(r *tasks.Runner, name string) (cacheSpec, error) {
+	fingerprint, err := r.Fingerprint(name)
+	if err != nil {
+		return cacheSpec{}, err
+	}
+	spec := cacheSpec{command: "task " + name, args: fingerprint}
+	if task := cfg.Tasks[name]; task != nil {
+		spec.inputs = task.Inputs
+		spec.outputs = task.Outputs
+	}
+	return spec, nil
+}
+
+// taskSkipReason returns why the task name does not apply to the module or
+// example at dir, or "" if it applies
+func taskSkipReason(name, dir string) (string, error) {
+	task := cfg.Tasks[name]
+	if task == nil || task.When == nil {
+		return "", nil
+	}
+	target, err := taskTarget(dir)
+	if err != nil {
+		return "", err
+	}
+	if reason := task.SkipReason(target); reason != "" {
+		return fmt.Sprintf("task '%s' does not apply: %s", name, reason), nil
+	}
+	return "", nil
+}
+
+// taskTarget describes the module or example at dir for the when conditions
+// of tasks
+func taskTarget(dir string) (tasks.Target, error) {
+	moduleDir := moduleConfigDir(dir)
+	mc, err := config.LoadModuleConfig(moduleDir)
+	if err != nil {
+		return tasks.Target{}, err
+	}
+
+	target := tasks.Target{
+		Dir:     dir,
+		Path:    filepath.ToSlash(moduleDir),
+		Type:    getModuleType(moduleDir),
+		Example: moduleDir != dir,
+	}
+	if basePath, err := getBasePath(); err == nil {
+		if rel, err := filepath.Rel(basePath, moduleDir); err == nil {
+			target.Path = filepath.ToSlash(rel)
+		}
+	}
+	if mc != nil {
+		target.Tags = mc.Tags
+	}
+	return target, nil
+}
+
+// describeParam describes a task param for --list, e.g.
+// "level=patch|minor|major (default: patch)"
+func describeParam(name string, p *tasks.ParamConfig) string {
+	if p == nil {
+		p = &tasks.ParamConfig{}
+	}
+	value := "<" + p.Type + ">"
+	if p.Type == "" {
+		value = "<string>"
+	}
+	if len(p.Allowed) > 0 {
+		value = strings.Join(p.Allowed, "|")
+	}
+	desc := name + "=" + value
+	switch {
+	case p.Required:
+		desc += " (required)"
+	case p.Default != "":
+		desc += " (default: " + p.Default + ")"
+	}
+	if p.Description != "" {
+		desc += "  " + p.Description
+	}
+	return desc
+}
+
+// moduleTaskRunner returns the task runner for a module. Tasks can depend on
+// the terraform/tofu commands of the module's runner, and dependencies whose
+// when conditions exclude the module are skipped.
+func moduleTaskRunner(ctx context.Context, gitRoot, modulePath string) (*tasks.Runner, error) {
+	env, err := buildTaskEnv(gitRoot, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	tfRunner, err := moduleRunner(ctx, gitRoot, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	target, err := taskTarget(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	return tasks.NewRunner(cfg.Tasks, env).
+		WithContext(ctx).
+		WithTarget(target).
+		WithTaskPolicy(cfg.Execution.TaskPolicy).
+		WithBuiltins(taskBuiltins(tfRunner)), nil
+}
+
+// taskBuiltins returns the built-ins tasks can depend on, run with tfRunner
+func taskBuiltins(tfRunner *terraform.Runner) map[string]tasks.Builtin {
+	return map[string]tasks.Builtin{
+		"fmt": func(dir string, stdout, stderr io.Writer) error {
+			return tfRunner.RunFmtWithOutput(dir, stdout, stderr)
+		},
+		"init": func(dir string, stdout, stderr io.Writer) error {
+			return tfRunner.RunInitWithOutput(dir, stdout, stderr)
+		},
+		"plan": func(dir string, stdout, stderr io.Writer) error {
+			return tfRunner.RunPlanWithOutput(dir, stdout, stderr)
+		},
+		"test": func(dir string, stdout, stderr io.Writer) error {
+			return tfRunner.RunTestWithOutput(dir, stdout, stderr)
+		},
+		"validate": func(dir string, stdout, stderr io.Writer) error {
+			return tfRunner.RunValidateWithOutput(dir, stdout, stderr)
+		},
+	}
+}
+
+// repoRoot returns the root of the git repository, resolved once per command
+// for MOTF_GIT_ROOT of tasks and hooks. Outside a git repository it is "".
+func repoRoot() (string, error) {
+	root, err := git.GetRepoRoot()
+	if err != nil && !errors.Is(err, git.ErrNotRepository) {
+		return "", fmt.Errorf("failed to find the git root: %w", err)
+	}
+	return root, nil
+}
+
 // buildTaskEnv creates the environment variables for task execution.
-func buildTaskEnv(gitRoot, modulePath string) []string {
+// MOTF_BINARY honors the binary override from the module's .motf.module.yml.
+func buildTaskEnv(gitRoot, modulePath string) ([]string, error) {
+	mc, err := config.LoadModuleConfig(moduleConfigDir(modulePath))
+	if err != nil {
+		return nil, err
+	}
+
 	return tasks.NewEnvBuilder().
 		WithGitRoot(gitRoot).
 		WithModulePath(modulePath).
 		WithModuleName(tasks.ModuleNameFromPath(modulePath)).
 		WithConfigPath(cfg.ConfigPath).
-		WithBinary(cfg.Binary).
-		Build()
+		WithBinary(cfg.ForModule(mc).Binary).
+		Build(), nil
 }
 
 func init() {
 	taskCmd.Flags().StringVarP(&taskFlag, "task", "t", "", "Task name to run")
 	taskCmd.Flags().BoolVarP(&listTaskFlag, "list", "l", false, "List available tasks")
+	taskCmd.Flags().StringArrayVar(&paramFlag, "param", []string{}, "Set a task param: name=value (can be specified multiple times)")
 	taskCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	taskCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	taskCmd.Flags().StringVar(&typeFlag, "type", "", "Only run on modules of this type, as configured in module_kinds (default: component, base, or project)")
+	taskCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Only run on modules matching a wildcard (e.g., *storage*)")
+	taskCmd.Flags().StringArrayVar(&tagFlag, "tag", []string{}, "Only run on modules with this tag (can be specified multiple times)")
 	taskCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
 	taskCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	taskCmd.Flags().BoolVar(&dependentsFlag, "dependents", false, "Also include modules that transitively depend on changed modules (requires --changed)")
 	taskCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
 	taskCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	taskCmd.Flags().BoolVar(&failFastFlag, "fail-fast", false, "Stop after the first failed module: cancel queued modules and interrupt running ones")
+	taskCmd.Flags().IntVar(&maxFailuresFlag, "max-failures", 0, "Stop after this many modules have failed (default: no limit)")
+	taskCmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Interrupt each command or task running longer than this, e.g. 20m (default: execution.timeout, no timeout)")
+	taskCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry commands failing with an error matching execution.retry_on up to this many times (default: execution.retries)")
+	taskCmd.Flags().StringVar(&outputModeFlag, "output-mode", "", "How to print output of multiple modules: stream, grouped, or quiet (default: stream)")
+	taskCmd.Flags().StringVar(&ciFlag, "ci", "", "Format output for a CI system: github, gitlab, or none (default: detected from GITHUB_ACTIONS/GITLAB_CI)")
+	taskCmd.Flags().BoolVar(&dagFlag, "dag", false, "Run modules in dependency order and skip dependents of failed modules")
+	taskCmd.Flags().StringArrayVar(&reportFlag, "report", []string{}, "Write a report of the run: junit=<path> or json=<path> (can be specified multiple times)")
+	taskCmd.Flags().BoolVar(&cacheFlag, "cache", false, "Skip modules whose result is cached and replay their output; cache successful results (default: cache.enabled)")
+	taskCmd.Flags().StringVar(&logDirFlag, "log-dir", "", "Write each module's uncolored output to <dir>/<type>/<name>.log")
+	taskCmd.Flags().BoolVar(&noSummaryFlag, "no-summary", false, "Don't print the summary table after running on several modules")
 	rootCmd.AddCommand(taskCmd)
 }
@@ -1,7 +1,13 @@
 package cli
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
 )
 
 func TestTaskCmd_Flags(t *testing.T) {
@@ -29,3 +35,150 @@ func TestTaskCmd_Flags(t *testing.T) {
 		t.Errorf("example flag shorthand = %q, want %q", exampleFlagDef.Shorthand, "e")
 	}
 }
+
+func TestTaskCmd_DependsOnBuiltin(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	createTerraformModule(t, tmpDir, "components/network")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Tasks: map[string]*tasks.TaskConfig{
+		"docs": {DependsOn: []string{"init", "lint"}, Command: "test -f .initialized && echo docs >> ran"},
+		"lint": {DependsOn: []string{"init"}, Command: "echo lint >> ran"},
+	}})
+	withFakeRunner(t, "#!/bin/sh\necho \"$1\" >> ran\ntouch .initialized\n")
+
+	taskFlag = "docs"
+	allFlag = true
+	var runErr error
+	_ = captureOutput(t, &os.Stdout, func() {
+		_ = captureOutput(t, &os.Stderr, func() {
+			runErr = taskCmd.RunE(taskCmd, nil)
+		})
+	})
+	if runErr != nil {
+		t.Fatalf("expected the task to succeed, got %v", runErr)
+	}
+
+	for _, name := range []string{"storage", "network"} {
+		data, err := os.ReadFile(filepath.Join(tmpDir, "components", name, "ran"))
+		if err != nil {
+			t.Fatalf("expected %s to run the task: %v", name, err)
+		}
+		if got := string(data); got != "init\nlint\ndocs\n" {
+			t.Errorf("%s ran %q, want init once before lint and docs", name, got)
+		}
+	}
+}
+
+func TestTaskCmd_Params(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/app")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Tasks: map[string]*tasks.TaskConfig{
+		"bump": {
+			Command: "echo {{ .Params.level }} $MOTF_PARAM_LEVEL > bumped",
+			Params:  map[string]*tasks.ParamConfig{"level": {Default: "patch", Allowed: []string{"patch", "minor", "major"}}},
+		},
+	}})
+	withFakeRunner(t, "#!/bin/sh\n")
+
+	taskFlag = "bump"
+	allFlag = true
+	paramFlag = []string{"level=minor"}
+	var runErr error
+	_ = captureOutput(t, &os.Stdout, func() {
+		_ = captureOutput(t, &os.Stderr, func() {
+			runErr = taskCmd.RunE(taskCmd, nil)
+		})
+	})
+	if runErr != nil {
+		t.Fatalf("expected the task to succeed, got %v", runErr)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "components", "app", "bumped"))
+	if err != nil || string(data) != "minor minor\n" {
+		t.Errorf("expected the param in the template and env, got %q, %v", data, err)
+	}
+
+	paramFlag = []string{"level=huge"}
+	runErr = taskCmd.RunE(taskCmd, nil)
+	if runErr == nil || !strings.Contains(runErr.Error(), "'huge' must be one of patch, minor, major") {
+		t.Errorf("expected the invalid param to be rejected before running, got %v", runErr)
+	}
+}
+
+func TestListTasks_Params(t *testing.T) {
+	withConfig(t, &config.Config{Tasks: map[string]*tasks.TaskConfig{
+		"bump": {
+			Description: "Bump the module version",
+			Command:     "bump {{ .Params.level }}",
+			Params: map[string]*tasks.ParamConfig{
+				"level":  {Default: "patch", Allowed: []string{"patch", "minor", "major"}},
+				"reason": {Required: true, Description: "Changelog entry"},
+			},
+		},
+	}})
+
+	stdout := captureOutput(t, &os.Stdout, func() {
+		if err := listTasks(nil); err != nil {
+			t.Errorf("listTasks() returned error: %v", err)
+		}
+	})
+	want := "      --param level=patch|minor|major (default: patch)\n      --param reason=<string> (required)  Changelog entry\n"
+	if !strings.Contains(stdout, want) {
+		t.Errorf("expected the params to be listed, got:\n%s", stdout)
+	}
+}
+
+func TestTaskCmd_WhenSkipsModules(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/storage")
+	createTerraformModule(t, tmpDir, "components/network")
+	if err := os.MkdirAll(filepath.Join(tmpDir, "components", "storage", "tests"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "components", "storage", "tests", "storage_test.go"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Tasks: map[string]*tasks.TaskConfig{
+		"go-lint": {Command: "touch linted", When: &tasks.WhenConfig{Files: []string{"tests/*_test.go"}}},
+		"docs":    {Command: "true", When: &tasks.WhenConfig{Types: []string{"base"}}},
+		"fmt":     {Command: "true"},
+	}})
+	withFakeRunner(t, "#!/bin/sh\n")
+
+	taskFlag = "go-lint"
+	allFlag = true
+	var runErr error
+	var stderr string
+	_ = captureOutput(t, &os.Stdout, func() {
+		stderr = captureOutput(t, &os.Stderr, func() {
+			runErr = taskCmd.RunE(taskCmd, nil)
+		})
+	})
+	if runErr != nil {
+		t.Fatalf("expected modules the task does not apply to be skipped, not failed, got %v", runErr)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "components", "storage", "linted")); err != nil {
+		t.Errorf("expected the task to run on storage: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "components", "network", "linted")); err == nil {
+		t.Error("expected the task not to run on network")
+	}
+	if !strings.Contains(stderr, "Skipped: task 'go-lint' does not apply: no file matches 'tests/*_test.go'") {
+		t.Errorf("expected network to be reported as skipped, got: %s", stderr)
+	}
+	if !strings.Contains(stderr, "2 modules: 1 ok, 0 failed, 1 skipped") {
+		t.Errorf("expected the skip in the summary, got: %s", stderr)
+	}
+
+	allFlag = false
+	stdout := captureOutput(t, &os.Stdout, func() {
+		if err := listTasks([]string{"network"}); err != nil {
+			t.Errorf("listTasks() returned error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "fmt") || strings.Contains(stdout, "go-lint") || strings.Contains(stdout, "docs") {
+		t.Errorf("expected only the tasks that apply to network, got:\n%s", stdout)
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"io"
 
 	"github.com/spf13/cobra"
@@ -8,26 +9,46 @@ import (
 
 // testCmd represents the test command
 var testCmd = &cobra.Command{
-	Use:   "test [module-name]",
-	Short: "Run tests on a component, base, or project",
-	Long: `Run tests on a component, base, or project using the configured test engine.
+	Use:   "test [module-name...]",
+	Short: "Run tests on a module",
+	Long: `Run tests on a module (a component, base, or project by default, or a module
+of any kind configured in module_kinds) using the configured test engine.
 
 The test engine (e.g., terratest, terraform, tofu) is configured in .motf.yml under the 'test' section.
 By default, terratest is used, which runs 'go test ./...' in the module directory.
+A module can override the engine and its arguments in its .motf.module.yml.
+
+Several modules can be selected at once by passing multiple module names or
+using --all, --type, --search, --tag, or --changed. Selected modules run through the
+same pipeline as --changed (supporting --parallel and --dag).
 
 Examples:
   motf test storage-account                    # Run tests on storage-account module
   motf test storage-account -a -v              # Run tests with verbose output
-  motf test storage-account -a -timeout=30m    # Run tests with custom timeout`,
-	Args: cobra.MaximumNArgs(1),
+  motf test storage-account -a -timeout=30m    # Run tests with custom timeout
+  motf test storage-account key-vault          # Run tests on several modules
+  motf test --type component -p                # Run tests on all components in parallel
+  motf test --tag tofu-ready                   # Run tests on modules tagged 'tofu-ready'`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if changedFlag {
-			if len(args) > 0 {
-				return cobra.MaximumNArgs(0)(cmd, args)
-			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
-				return runner.RunTestWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
-			})
+		gitRoot, err := repoRoot()
+		if err != nil {
+			return err
+		}
+
+		if usesModuleSelection(args) {
+			spec := cacheSpec{command: "test", args: argsFlag}
+			return runOnSelectedModulesWithPath(args, withCache(spec, func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error {
+				tfRunner, err := moduleRunner(ctx, gitRoot, moduleAbsPath)
+				if err != nil {
+					return err
+				}
+				return tfRunner.RunTestWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
+			}))
+		}
+
+		if err := checkCacheFlag(); err != nil {
+			return err
 		}
 
 		targetPath, err := resolveTargetPath(args)
@@ -35,14 +56,35 @@ Examples:
 			return err
 		}
 
-		return runner.RunTest(targetPath, argsFlag...)
+		tfRunner, err := moduleRunner(runContext, gitRoot, targetPath)
+		if err != nil {
+			return err
+		}
+
+		return tfRunner.RunTest(targetPath, argsFlag...)
 	},
 }
 
 func init() {
+	testCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	testCmd.Flags().StringVar(&typeFlag, "type", "", "Only run on modules of this type, as configured in module_kinds (default: component, base, or project)")
+	testCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Only run on modules matching a wildcard (e.g., *storage*)")
+	testCmd.Flags().StringArrayVar(&tagFlag, "tag", []string{}, "Only run on modules with this tag (can be specified multiple times)")
 	testCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
 	testCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	testCmd.Flags().BoolVar(&dependentsFlag, "dependents", false, "Also include modules that transitively depend on changed modules (requires --changed)")
 	testCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
 	testCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	testCmd.Flags().BoolVar(&failFastFlag, "fail-fast", false, "Stop after the first failed module: cancel queued modules and interrupt running ones")
+	testCmd.Flags().IntVar(&maxFailuresFlag, "max-failures", 0, "Stop after this many modules have failed (default: no limit)")
+	testCmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Interrupt each command or task running longer than this, e.g. 20m (default: execution.timeout, no timeout)")
+	testCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry commands failing with an error matching execution.retry_on up to this many times (default: execution.retries)")
+	testCmd.Flags().StringVar(&outputModeFlag, "output-mode", "", "How to print output of multiple modules: stream, grouped, or quiet (default: stream)")
+	testCmd.Flags().StringVar(&ciFlag, "ci", "", "Format output for a CI system: github, gitlab, or none (default: detected from GITHUB_ACTIONS/GITLAB_CI)")
+	testCmd.Flags().BoolVar(&dagFlag, "dag", false, "Run modules in dependency order and skip dependents of failed modules")
+	testCmd.Flags().StringArrayVar(&reportFlag, "report", []string{}, "Write a report of the run: junit=<path> or json=<path> (can be specified multiple times)")
+	testCmd.Flags().BoolVar(&cacheFlag, "cache", false, "Skip modules whose result is cached and replay their output; cache successful results (default: cache.enabled)")
+	testCmd.Flags().StringVar(&logDirFlag, "log-dir", "", "Write each module's uncolored output to <dir>/<type>/<name>.log")
+	testCmd.Flags().BoolVar(&noSummaryFlag, "no-summary", false, "Don't print the summary table after running on several modules")
 	rootCmd.AddCommand(testCmd)
 }
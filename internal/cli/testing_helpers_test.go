@@ -1,11 +1,14 @@
 package cli
 
 import (
+	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
 )
 
 // resetFlags resets all package-level flags to their default values.
@@ -17,11 +20,40 @@ func resetFlags(t *testing.T) {
 		argsFlag = []string{}
 		initFlag = false
 		searchFlag = ""
+		allFlag = false
+		typeFlag = ""
+		tagFlag = []string{}
 		exampleFlag = ""
 		changedFlag = false
+		dependentsFlag = false
+		dagFlag = false
+		reportFlag = []string{}
+		logDirFlag = ""
+		noSummaryFlag = false
+		failFastFlag = false
+		maxFailuresFlag = 0
+		timeoutFlag = 0
+		retriesFlag = 0
+		outputModeFlag = ""
+		ciFlag = ""
+		ciProvider = ""
+		commandName = ""
+		runContext = context.Background()
 		parallelFlag = false
 		maxParallelFlag = 0
 		refFlag = ""
+		yesFlag = false
+		planOutFlag = ""
+		planJSONFlag = false
+		driftModeFlag = DriftModeRefreshOnly
+		driftJSONFlag = false
+		driftFailOnErrorFlag = false
+		applyPlanFileFlag = ""
+		destroyConfirmFlag = []string{}
+		cacheFlag = false
+		taskFlag = ""
+		listTaskFlag = false
+		paramFlag = []string{}
 	})
 }
 
@@ -69,3 +101,42 @@ func createTerraformModule(t *testing.T, baseDir, relativePath string) string {
 
 	return modulePath
 }
+
+// withFakeRunner points the global runner at a shell script standing in for
+// terraform/tofu. It will be restored after the test completes.
+func withFakeRunner(t *testing.T, script string) {
+	t.Helper()
+	binary := filepath.Join(t.TempDir(), "fake-terraform")
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake binary: %v", err)
+	}
+
+	original := runner
+	runner = terraform.NewRunner(&config.Config{Binary: binary})
+	t.Cleanup(func() {
+		runner = original
+	})
+}
+
+// captureOutput redirects *stream (os.Stdout or os.Stderr) while fn runs and
+// returns what was written to it
+func captureOutput(t *testing.T, stream **os.File, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+
+	original := *stream
+	*stream = w
+	defer func() { *stream = original }()
+	fn()
+	_ = w.Close()
+	return string(<-done)
+}
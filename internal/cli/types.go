@@ -1,10 +1,12 @@
 package cli
 
-// Module directory constants
+import "github.com/TechnicallyJoe/terraform-motf/internal/config"
+
+// Directories of the default module kinds (see config.DefaultModuleKinds)
 const (
-	DirComponents = "components"
-	DirBases      = "bases"
-	DirProjects   = "projects"
+	DirComponents = config.DirComponents
+	DirBases      = config.DirBases
+	DirProjects   = config.DirProjects
 )
 
 // Subdirectory constants
@@ -20,27 +22,22 @@ const (
 	FileSpaceliftConfig = "config.yml"
 )
 
-// Module type constants
+// Type names of the default module kinds (see config.DefaultModuleKinds)
 const (
-	TypeComponent = "component"
-	TypeBase      = "base"
-	TypeProject   = "project"
+	TypeComponent = config.TypeComponent
+	TypeBase      = config.TypeBase
+	TypeProject   = config.TypeProject
 )
 
-// ModuleDirs contains all module directory names
-var ModuleDirs = []string{DirComponents, DirBases, DirProjects}
-
-// ModuleTypeOrder defines the sorting order for module types
-var ModuleTypeOrder = map[string]int{
-	TypeComponent: 1,
-	TypeBase:      2,
-	TypeProject:   3,
-}
-
-// ModuleInfo holds information about a discovered module
+// ModuleInfo holds information about a discovered module.
+// Description, Lifecycle, Tags, and Owners come from the module's .motf.module.yml.
 type ModuleInfo struct {
-	Name    string `json:"name"`
-	Type    string `json:"type"`
-	Path    string `json:"path"`
-	Version string `json:"version,omitempty"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Path        string   `json:"path"`
+	Version     string   `json:"version,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Lifecycle   string   `json:"lifecycle,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Owners      []string `json:"owners,omitempty"`
 }
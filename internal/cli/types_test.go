@@ -1,31 +1,37 @@
 package cli
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 )
 
-func TestModuleDirs(t *testing.T) {
+func TestModuleDirs_Default(t *testing.T) {
+	withConfig(t, &config.Config{})
 	expected := []string{"components", "bases", "projects"}
 
-	if len(ModuleDirs) != len(expected) {
-		t.Fatalf("expected %d module dirs, got %d", len(expected), len(ModuleDirs))
-	}
-
-	for i, dir := range expected {
-		if ModuleDirs[i] != dir {
-			t.Errorf("ModuleDirs[%d] = '%s', expected '%s'", i, ModuleDirs[i], dir)
-		}
+	if dirs := moduleDirs(); !reflect.DeepEqual(dirs, expected) {
+		t.Errorf("moduleDirs() = %v, expected %v", dirs, expected)
 	}
 }
 
-func TestModuleTypeOrder(t *testing.T) {
+func TestModuleTypeOrder_Default(t *testing.T) {
+	withConfig(t, &config.Config{})
+
 	// Components should sort first, then bases, then projects
-	if ModuleTypeOrder[TypeComponent] >= ModuleTypeOrder[TypeBase] {
+	component, _ := moduleTypeOrder(TypeComponent)
+	base, _ := moduleTypeOrder(TypeBase)
+	project, _ := moduleTypeOrder(TypeProject)
+	if component >= base {
 		t.Error("components should sort before bases")
 	}
-	if ModuleTypeOrder[TypeBase] >= ModuleTypeOrder[TypeProject] {
+	if base >= project {
 		t.Error("bases should sort before projects")
 	}
+	if _, ok := moduleTypeOrder("stack"); ok {
+		t.Error("unknown type should not have an order")
+	}
 }
 
 func TestDirConstants(t *testing.T) {
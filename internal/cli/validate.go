@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"context"
+	"fmt"
 	"io"
 
 	"github.com/spf13/cobra"
@@ -8,31 +10,54 @@ import (
 
 // valCmd represents the validate command
 var valCmd = &cobra.Command{
-	Use:     "val [module-name]",
+	Use:     "val [module-name...]",
 	Aliases: []string{"validate"},
-	Short:   "Run terraform/tofu validate on a component, base, or project",
-	Long: `Run terraform/tofu validate on a component, base, or project.
+	Short:   "Run terraform/tofu validate on a module",
+	Long: `Run terraform/tofu validate on a module: a component, base, or project by default,
+or a module of any kind configured in module_kinds.
 
 Use the --example/-e flag to run validate on a specific example instead of the module itself.
 
+Several modules can be selected at once by passing multiple module names or
+using --all, --type, --search, --tag, or --changed. Selected modules run through the
+same pipeline as --changed (supporting --parallel and --dag).
+
 Examples:
   motf val storage-account              # Run validate on storage-account module
   motf val storage-account -e basic     # Run validate on the 'basic' example
-  motf val -i storage-account -e basic  # Run init then validate on the 'basic' example`,
-	Args: cobra.MaximumNArgs(1),
+  motf val -i storage-account -e basic  # Run init then validate on the 'basic' example
+  motf val storage-account key-vault    # Run validate on several modules
+  motf val -i --all -p                  # Run init and validate on all modules in parallel
+  motf val -i -s *azurerm*              # Run init and validate on matching modules`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if changedFlag {
-			if len(args) > 0 {
-				return cobra.MaximumNArgs(0)(cmd, args)
-			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
+		gitRoot, err := repoRoot()
+		if err != nil {
+			return err
+		}
+		ciRoot := annotationRoot(gitRoot)
+
+		if usesModuleSelection(args) {
+			spec := cacheSpec{command: "validate", args: append([]string{fmt.Sprintf("init=%t", initFlag), "ci=" + ciProvider}, argsFlag...)}
+			return runOnSelectedModulesWithPath(args, withCache(spec, func(ctx context.Context, moduleAbsPath string, stdout, stderr io.Writer) error {
+				tfRunner, err := moduleRunner(ctx, gitRoot, moduleAbsPath)
+				if err != nil {
+					return err
+				}
 				if initFlag {
-					if err := runner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
+					if err := tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
 						return err
 					}
 				}
-				return runner.RunValidateWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
-			})
+				if ciProvider == CIGitHub {
+					return validateForCI(ctx, tfRunner, ciRoot, moduleAbsPath, stdout, stderr)
+				}
+				return tfRunner.RunValidateWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
+			}))
+		}
+
+		if err := checkCacheFlag(); err != nil {
+			return err
 		}
 
 		targetPath, err := resolveTargetWithExample(args, exampleFlag)
@@ -40,23 +65,44 @@ Examples:
 			return err
 		}
 
+		tfRunner, err := moduleRunner(runContext, gitRoot, targetPath)
+		if err != nil {
+			return err
+		}
+
 		// Run init first if flag is set
 		if initFlag {
-			if err := runner.RunInit(targetPath); err != nil {
+			if err := tfRunner.RunInit(targetPath); err != nil {
 				return err
 			}
 		}
 
-		return runner.RunValidate(targetPath, argsFlag...)
+		return tfRunner.RunValidate(targetPath, argsFlag...)
 	},
 }
 
 func init() {
 	valCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
 	valCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	valCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	valCmd.Flags().StringVar(&typeFlag, "type", "", "Only run on modules of this type, as configured in module_kinds (default: component, base, or project)")
+	valCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Only run on modules matching a wildcard (e.g., *storage*)")
+	valCmd.Flags().StringArrayVar(&tagFlag, "tag", []string{}, "Only run on modules with this tag (can be specified multiple times)")
 	valCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
 	valCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	valCmd.Flags().BoolVar(&dependentsFlag, "dependents", false, "Also include modules that transitively depend on changed modules (requires --changed)")
 	valCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
 	valCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	valCmd.Flags().BoolVar(&failFastFlag, "fail-fast", false, "Stop after the first failed module: cancel queued modules and interrupt running ones")
+	valCmd.Flags().IntVar(&maxFailuresFlag, "max-failures", 0, "Stop after this many modules have failed (default: no limit)")
+	valCmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Interrupt each command or task running longer than this, e.g. 20m (default: execution.timeout, no timeout)")
+	valCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry commands failing with an error matching execution.retry_on up to this many times (default: execution.retries)")
+	valCmd.Flags().StringVar(&outputModeFlag, "output-mode", "", "How to print output of multiple modules: stream, grouped, or quiet (default: stream)")
+	valCmd.Flags().StringVar(&ciFlag, "ci", "", "Format output for a CI system: github, gitlab, or none (default: detected from GITHUB_ACTIONS/GITLAB_CI)")
+	valCmd.Flags().BoolVar(&dagFlag, "dag", false, "Run modules in dependency order and skip dependents of failed modules")
+	valCmd.Flags().StringArrayVar(&reportFlag, "report", []string{}, "Write a report of the run: junit=<path> or json=<path> (can be specified multiple times)")
+	valCmd.Flags().BoolVar(&cacheFlag, "cache", false, "Skip modules whose result is cached and replay their output; cache successful results (default: cache.enabled)")
+	valCmd.Flags().StringVar(&logDirFlag, "log-dir", "", "Write each module's uncolored output to <dir>/<type>/<name>.log")
+	valCmd.Flags().BoolVar(&noSummaryFlag, "no-summary", false, "Don't print the summary table after running on several modules")
 	rootCmd.AddCommand(valCmd)
 }
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CacheConfig controls the cache of command and task results
+type CacheConfig struct {
+	Enabled bool     `yaml:"enabled"` // cache results by default; --cache overrides
+	Dir     string   `yaml:"dir"`     // relative to the config file; .git/motf/cache by default
+	Env     []string `yaml:"env"`     // environment variables results depend on
+}
+
+// validateCache checks the cache settings
+func validateCache(c *CacheConfig) error {
+	if c == nil {
+		return nil
+	}
+	for _, name := range c.Env {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("invalid cache env '%s' in config: must be an environment variable name", name)
+		}
+	}
+	return nil
+}
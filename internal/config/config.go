@@ -5,8 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
 	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
 	"gopkg.in/yaml.v3"
 )
@@ -75,6 +77,73 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("invalid test engine '%s' in config: must be %s", cfg.Test.Engine, quotedJoin(ValidTestEngineNames()))
 	}
 
+	if len(cfg.ModuleKinds) == 0 {
+		cfg.ModuleKinds = DefaultModuleKinds()
+	} else if err := validateModuleKinds(cfg.ModuleKinds); err != nil {
+		return err
+	}
+	sort.SliceStable(cfg.ModuleKinds, func(i, j int) bool {
+		return cfg.ModuleKinds[i].Order < cfg.ModuleKinds[j].Order
+	})
+
+	if cfg.Discovery != nil {
+		for _, p := range append(append([]string{}, cfg.Discovery.Exclude...), cfg.Discovery.Include...) {
+			if err := finder.ValidatePattern(p); err != nil {
+				return fmt.Errorf("%w in config", err)
+			}
+		}
+	}
+
+	if err := validateCache(cfg.Cache); err != nil {
+		return err
+	}
+
+	if err := tasks.Validate(cfg.Tasks); err != nil {
+		return fmt.Errorf("invalid tasks in config: %w", err)
+	}
+
+	if err := tasks.ValidateHooks(cfg.Hooks); err != nil {
+		return fmt.Errorf("invalid hooks in config: %w", err)
+	}
+
+	return validateExecution(cfg.Execution, cfg.Tasks)
+}
+
+// validateModuleKinds checks that every module kind has a directory inside root
+// and a type name, and that no directory or type is used twice. Directories are
+// normalized to clean, slash-separated paths.
+func validateModuleKinds(kinds []ModuleKind) error {
+	dirs := make(map[string]bool)
+	types := make(map[string]bool)
+
+	for i := range kinds {
+		kind := &kinds[i]
+		if kind.Dir == "" || kind.Type == "" {
+			return fmt.Errorf("invalid module kind in config: both 'dir' and 'type' are required")
+		}
+
+		dir := filepath.ToSlash(filepath.Clean(kind.Dir))
+		if filepath.IsAbs(kind.Dir) || dir == "." || dir == ".." || strings.HasPrefix(dir, "../") {
+			return fmt.Errorf("invalid module kind dir '%s' in config: must be a relative path inside root", kind.Dir)
+		}
+		kind.Dir = dir
+
+		if dirs[kind.Dir] {
+			return fmt.Errorf("duplicate module kind dir '%s' in config", kind.Dir)
+		}
+		// A module in a nested dir would belong to both kinds
+		for dir := range dirs {
+			if strings.HasPrefix(kind.Dir, dir+"/") || strings.HasPrefix(dir, kind.Dir+"/") {
+				return fmt.Errorf("module kind dirs '%s' and '%s' overlap in config: a kind dir cannot contain another", dir, kind.Dir)
+			}
+		}
+		if types[kind.Type] {
+			return fmt.Errorf("duplicate module kind type '%s' in config", kind.Type)
+		}
+		dirs[kind.Dir] = true
+		types[kind.Type] = true
+	}
+
 	return nil
 }
 
@@ -84,6 +153,46 @@ type TestConfig struct {
 	Args   string `yaml:"args"`
 }
 
+// Directories and type names of the default module kinds
+const (
+	DirComponents = "components"
+	DirBases      = "bases"
+	DirProjects   = "projects"
+
+	TypeComponent = "component"
+	TypeBase      = "base"
+	TypeProject   = "project"
+)
+
+// ModuleKind defines a kind of module: the directory under root that holds
+// modules of this kind, the type name reported for them, and the order in
+// which types are sorted. Modules of a kind are expected to source modules of
+// lower-ordered kinds only.
+type ModuleKind struct {
+	Dir   string `yaml:"dir"`
+	Type  string `yaml:"type"`
+	Order int    `yaml:"order"`
+}
+
+// DefaultModuleKinds returns the components/bases/projects layout used when
+// no module kinds are configured.
+func DefaultModuleKinds() []ModuleKind {
+	return []ModuleKind{
+		{Dir: DirComponents, Type: TypeComponent, Order: 1},
+		{Dir: DirBases, Type: TypeBase, Order: 2},
+		{Dir: DirProjects, Type: TypeProject, Order: 3},
+	}
+}
+
+// DiscoveryConfig holds gitignore-style patterns, relative to root, that control
+// which directories are searched for modules. Exclude adds to the built-in
+// skipped directories (examples, modules, tests, .spacelift); include wins over
+// both, e.g. to discover real modules in directories named modules.
+type DiscoveryConfig struct {
+	Exclude []string `yaml:"exclude"`
+	Include []string `yaml:"include"`
+}
+
 type ParallelismConfig struct {
 	MaxJobs int `yaml:"max_jobs"`
 }
@@ -103,7 +212,12 @@ type Config struct {
 	Binary      string                       `yaml:"binary"`
 	Test        *TestConfig                  `yaml:"test"`
 	Tasks       map[string]*tasks.TaskConfig `yaml:"tasks"`
+	Hooks       tasks.Hooks                  `yaml:"hooks"` // commands run around terraform/tofu commands
 	Parallelism *ParallelismConfig           `yaml:"parallelism"`
+	ModuleKinds []ModuleKind                 `yaml:"module_kinds"`
+	Discovery   *DiscoveryConfig             `yaml:"discovery"`
+	Execution   *ExecutionConfig             `yaml:"execution"`
+	Cache       *CacheConfig                 `yaml:"cache"`
 	ConfigPath  string                       `yaml:"-"` // Path to the config file, if found
 }
 
@@ -119,6 +233,7 @@ func DefaultConfig() *Config {
 		Parallelism: &ParallelismConfig{
 			MaxJobs: 0,
 		},
+		ModuleKinds: DefaultModuleKinds(),
 	}
 }
 
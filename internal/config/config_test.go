@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -619,3 +621,170 @@ func TestLoad_ExplicitConfigPath_RelativePath(t *testing.T) {
 		t.Errorf("expected ConfigPath to be absolute, got '%s'", cfg.ConfigPath)
 	}
 }
+
+// loadConfigContent writes content to .motf.yml in a new git repo and loads it
+func loadConfigContent(t *testing.T, content string) (*Config, error) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+	return Load(tmpDir, "")
+}
+
+func TestLoad_ModuleKindsDefault(t *testing.T) {
+	cfg, err := loadConfigContent(t, "binary: terraform\n")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.ModuleKinds, DefaultModuleKinds()) {
+		t.Errorf("expected default module kinds, got %+v", cfg.ModuleKinds)
+	}
+}
+
+func TestLoad_ModuleKindsFromFile(t *testing.T) {
+	cfg, err := loadConfigContent(t, `module_kinds:
+  - dir: live/
+    type: live
+    order: 30
+  - dir: modules
+    type: module
+    order: 10
+  - dir: stacks
+    type: stack
+    order: 20
+`)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	want := []ModuleKind{
+		{Dir: "modules", Type: "module", Order: 10},
+		{Dir: "stacks", Type: "stack", Order: 20},
+		{Dir: "live", Type: "live", Order: 30},
+	}
+	if !reflect.DeepEqual(cfg.ModuleKinds, want) {
+		t.Errorf("ModuleKinds = %+v, want %+v (sorted by order, dirs cleaned)", cfg.ModuleKinds, want)
+	}
+}
+
+func TestLoad_InvalidModuleKinds(t *testing.T) {
+	tests := []struct {
+		name    string
+		kinds   string
+		wantErr string
+	}{
+		{"missing type", "  - dir: modules\n", "both 'dir' and 'type' are required"},
+		{"missing dir", "  - type: module\n", "both 'dir' and 'type' are required"},
+		{"absolute dir", "  - dir: /modules\n    type: module\n", "must be a relative path inside root"},
+		{"dir outside root", "  - dir: ../modules\n    type: module\n", "must be a relative path inside root"},
+		{"root dir", "  - dir: .\n    type: module\n", "must be a relative path inside root"},
+		{"duplicate dir", "  - dir: modules\n    type: a\n  - dir: modules/\n    type: b\n", "duplicate module kind dir 'modules'"},
+		{"duplicate type", "  - dir: a\n    type: module\n  - dir: b\n    type: module\n", "duplicate module kind type 'module'"},
+		{"nested dir", "  - dir: modules\n    type: a\n  - dir: modules/base\n    type: b\n", "module kind dirs 'modules' and 'modules/base' overlap"},
+		{"parent dir", "  - dir: live/prod\n    type: a\n  - dir: live\n    type: b\n", "module kind dirs 'live/prod' and 'live' overlap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfigContent(t, "module_kinds:\n"+tt.kinds)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_Discovery(t *testing.T) {
+	cfg, err := loadConfigContent(t, `discovery:
+  exclude:
+    - vendor/
+    - "**/.terragrunt-cache"
+  include:
+    - components/network/modules
+`)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	if cfg.Discovery == nil {
+		t.Fatal("expected discovery config to be loaded")
+	}
+	if want := []string{"vendor/", "**/.terragrunt-cache"}; !reflect.DeepEqual(cfg.Discovery.Exclude, want) {
+		t.Errorf("Discovery.Exclude = %v, want %v", cfg.Discovery.Exclude, want)
+	}
+	if want := []string{"components/network/modules"}; !reflect.DeepEqual(cfg.Discovery.Include, want) {
+		t.Errorf("Discovery.Include = %v, want %v", cfg.Discovery.Include, want)
+	}
+}
+
+func TestLoad_DiscoveryInvalidPattern(t *testing.T) {
+	_, err := loadConfigContent(t, "discovery:\n  exclude:\n    - \"vendor/[a-\"\n")
+	if err == nil || !strings.Contains(err.Error(), "invalid discovery pattern 'vendor/[a-'") {
+		t.Fatalf("expected invalid discovery pattern error, got %v", err)
+	}
+}
+
+func TestLoad_TaskDependencies(t *testing.T) {
+	cfg, err := loadConfigContent(t, `tasks:
+  docs:
+    depends_on: [init]
+    steps:
+      - terraform-docs markdown table . > README.md
+      - task: lint
+  lint:
+    command: tflint
+`)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	docs := cfg.Tasks["docs"]
+	if !reflect.DeepEqual(docs.DependsOn, []string{"init"}) {
+		t.Errorf("DependsOn = %v, want [init]", docs.DependsOn)
+	}
+	if len(docs.Steps) != 2 || docs.Steps[0].Command == "" || docs.Steps[1].Task != "lint" {
+		t.Errorf("unexpected steps: %+v", docs.Steps)
+	}
+}
+
+func TestLoad_TaskDependencyCycle(t *testing.T) {
+	_, err := loadConfigContent(t, "tasks:\n  a:\n    depends_on: [b]\n    command: 'true'\n  b:\n    depends_on: [a]\n    command: 'true'\n")
+	if err == nil || !strings.Contains(err.Error(), "invalid tasks in config: task dependency cycle: a -> b -> a") {
+		t.Fatalf("expected a cycle error, got %v", err)
+	}
+}
+
+func TestLoad_Cache(t *testing.T) {
+	cfg, err := loadConfigContent(t, "cache:\n  enabled: true\n  dir: .cache/motf\n  env: [ARM_SUBSCRIPTION_ID]\n")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	want := &CacheConfig{Enabled: true, Dir: ".cache/motf", Env: []string{"ARM_SUBSCRIPTION_ID"}}
+	if !reflect.DeepEqual(cfg.Cache, want) {
+		t.Errorf("Cache = %+v, want %+v", cfg.Cache, want)
+	}
+
+	_, err = loadConfigContent(t, "cache:\n  env: ['A=B']\n")
+	if err == nil || !strings.Contains(err.Error(), "invalid cache env 'A=B'") {
+		t.Errorf("expected an invalid cache env error, got %v", err)
+	}
+}
+
+func TestLoad_Hooks(t *testing.T) {
+	cfg, err := loadConfigContent(t, "hooks:\n  pre_init: ./inject-secrets.sh\n  post_validate:\n    - tflint\n    - checkov -d .\n")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if len(cfg.Hooks["pre_init"]) != 1 || len(cfg.Hooks["post_validate"]) != 2 || cfg.Hooks["post_validate"][1].Command != "checkov -d ." {
+		t.Errorf("unexpected hooks: %+v", cfg.Hooks)
+	}
+
+	_, err = loadConfigContent(t, "hooks:\n  before_init: 'true'\n")
+	if err == nil || !strings.Contains(err.Error(), "invalid hooks in config: unknown hook 'before_init'") {
+		t.Errorf("expected an unknown hook error, got %v", err)
+	}
+}
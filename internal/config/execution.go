@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+)
+
+// DefaultRetryBackoff is the delay before the first retry when no backoff is configured
+const DefaultRetryBackoff = 10 * time.Second
+
+// DefaultRetryOn are the retry patterns used when none are configured. They
+// match transient network failures of provider and module downloads.
+var DefaultRetryOn = []string{
+	"Failed to install provider",
+	"Failed to query available provider packages",
+	"TLS handshake timeout",
+	"connection reset by peer",
+	"i/o timeout",
+}
+
+// validExecutionCommandNames are the terraform/tofu commands that can have
+// their own timeout and retries. Drift checks use the plan settings.
+var validExecutionCommandNames = []string{"apply", "destroy", "fmt", "init", "plan", "test", "validate"}
+
+var validExecutionCommands = toSet(validExecutionCommandNames)
+
+// ExecutionConfig controls how long terraform/tofu commands and tasks may run
+// for each module and how failed ones are retried. Retries only happen for
+// failures whose output matches one of RetryOn.
+type ExecutionConfig struct {
+	Timeout  *time.Duration               `yaml:"timeout"`
+	Retries  int                          `yaml:"retries"`
+	Backoff  time.Duration                `yaml:"backoff"`
+	RetryOn  []string                     `yaml:"retry_on"`
+	Commands map[string]*CommandExecution `yaml:"commands"`
+
+	// Override takes priority over Commands and task settings. It is set from
+	// the --timeout and --retries flags.
+	Override *CommandExecution `yaml:"-"`
+}
+
+// CommandExecution overrides the timeout and retries of a single command.
+// Unset values fall back to the execution defaults.
+type CommandExecution struct {
+	Timeout *time.Duration `yaml:"timeout"`
+	Retries *int           `yaml:"retries"`
+}
+
+// explicitRetryCommands are the commands that change infrastructure. A failed
+// attempt may have made part of its changes, so they are only retried when
+// execution.commands sets their retries: neither execution.retries nor
+// --retries reach them.
+var explicitRetryCommands = toSet([]string{"apply", "destroy"})
+
+// Policy returns the timeout and retry policy of command, a terraform/tofu
+// command such as init or plan
+func (e *ExecutionConfig) Policy(command string) process.Policy {
+	if e == nil {
+		return e.policy(nil)
+	}
+	override := e.Commands[command]
+	p := e.policy(override)
+	if _, ok := explicitRetryCommands[command]; ok && (override == nil || override.Retries == nil) {
+		p.Retries = 0
+	}
+	return p
+}
+
+// TaskPolicy returns the timeout and retry policy of a custom task
+func (e *ExecutionConfig) TaskPolicy(task *tasks.TaskConfig) process.Policy {
+	var override *CommandExecution
+	if task != nil {
+		override = &CommandExecution{Timeout: task.Timeout, Retries: task.Retries}
+	}
+	return e.policy(override)
+}
+
+// policy builds the policy from the defaults, override, and e.Override in
+// increasing priority. A nil e has the default settings.
+func (e *ExecutionConfig) policy(override *CommandExecution) process.Policy {
+	if e == nil {
+		e = &ExecutionConfig{}
+	}
+	p := process.Policy{Retries: e.Retries, Backoff: e.Backoff}
+	if e.Timeout != nil {
+		p.Timeout = *e.Timeout
+	}
+	for _, o := range []*CommandExecution{override, e.Override} {
+		if o == nil {
+			continue
+		}
+		if o.Timeout != nil {
+			p.Timeout = *o.Timeout
+		}
+		if o.Retries != nil {
+			p.Retries = *o.Retries
+		}
+	}
+
+	if p.Backoff == 0 {
+		p.Backoff = DefaultRetryBackoff
+	}
+	patterns := e.RetryOn
+	if len(patterns) == 0 {
+		patterns = DefaultRetryOn
+	}
+	for _, pattern := range patterns {
+		// Patterns from .motf.yml were checked by validateExecution
+		if re, err := regexp.Compile(pattern); err == nil {
+			p.RetryOn = append(p.RetryOn, re)
+		}
+	}
+	return p
+}
+
+// validateExecution checks the execution settings and the timeout and
+// retries of tasks
+func validateExecution(e *ExecutionConfig, taskConfigs map[string]*tasks.TaskConfig) error {
+	if e != nil {
+		if err := validateCommandExecution("execution", &CommandExecution{Timeout: e.Timeout, Retries: &e.Retries}); err != nil {
+			return err
+		}
+		if e.Backoff < 0 {
+			return fmt.Errorf("invalid execution backoff '%s' in config: must not be negative", e.Backoff)
+		}
+		for _, pattern := range e.RetryOn {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid execution retry_on pattern '%s' in config: %w", pattern, err)
+			}
+		}
+
+		names := make([]string, 0, len(e.Commands))
+		for name := range e.Commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, ok := validExecutionCommands[name]; !ok {
+				return fmt.Errorf("invalid execution command '%s' in config: must be %s", name, quotedJoin(validExecutionCommandNames))
+			}
+			if err := validateCommandExecution("execution command '"+name+"'", e.Commands[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, task := range taskConfigs {
+		if task == nil {
+			continue
+		}
+		if err := validateCommandExecution("task '"+name+"'", &CommandExecution{Timeout: task.Timeout, Retries: task.Retries}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCommandExecution checks that a timeout and retries are not negative
+func validateCommandExecution(what string, c *CommandExecution) error {
+	if c == nil {
+		return nil
+	}
+	if c.Timeout != nil && *c.Timeout < 0 {
+		return fmt.Errorf("invalid %s timeout '%s' in config: must not be negative", what, *c.Timeout)
+	}
+	if c.Retries != nil && *c.Retries < 0 {
+		return fmt.Errorf("invalid %s retries '%d' in config: must not be negative", what, *c.Retries)
+	}
+	return nil
+}
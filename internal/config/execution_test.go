@@ -0,0 +1,125 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+)
+
+func TestLoad_Execution(t *testing.T) {
+	cfg, err := loadConfigContent(t, `execution:
+  timeout: 20m
+  retries: 2
+  backoff: 5s
+  retry_on:
+    - Failed to install provider
+  commands:
+    init:
+      retries: 4
+    apply:
+      timeout: 0s
+      retries: 0
+tasks:
+  lint:
+    command: tflint
+    timeout: 2m
+`)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	plan := cfg.Execution.Policy("plan")
+	if plan.Timeout != 20*time.Minute || plan.Retries != 2 || plan.Backoff != 5*time.Second {
+		t.Errorf("unexpected plan policy: %+v", plan)
+	}
+	if len(plan.RetryOn) != 1 || plan.RetryOn[0].String() != "Failed to install provider" {
+		t.Errorf("expected the configured retry pattern, got %v", plan.RetryOn)
+	}
+	if init := cfg.Execution.Policy("init"); init.Timeout != 20*time.Minute || init.Retries != 4 {
+		t.Errorf("expected init to override retries only, got %+v", init)
+	}
+	if apply := cfg.Execution.Policy("apply"); apply.Timeout != 0 || apply.Retries != 0 {
+		t.Errorf("expected apply to disable timeout and retries, got %+v", apply)
+	}
+	if lint := cfg.Execution.TaskPolicy(cfg.Tasks["lint"]); lint.Timeout != 2*time.Minute || lint.Retries != 2 {
+		t.Errorf("expected the task to override the timeout only, got %+v", lint)
+	}
+}
+
+func TestExecutionConfig_PolicyDefaults(t *testing.T) {
+	var e *ExecutionConfig
+	p := e.Policy("init")
+	if p.Timeout != 0 || p.Retries != 0 || p.Backoff != DefaultRetryBackoff || len(p.RetryOn) != len(DefaultRetryOn) {
+		t.Errorf("unexpected default policy: %+v", p)
+	}
+
+	retries := 3
+	if p := e.TaskPolicy(&tasks.TaskConfig{Retries: &retries}); p.Retries != 3 || len(p.RetryOn) != len(DefaultRetryOn) {
+		t.Errorf("expected task retries with the default patterns, got %+v", p)
+	}
+}
+
+func TestExecutionConfig_Override(t *testing.T) {
+	timeout, one, five := time.Minute, 1, 5
+	e := &ExecutionConfig{
+		Retries:  2,
+		Commands: map[string]*CommandExecution{"init": {Retries: &five}},
+		Override: &CommandExecution{Timeout: &timeout, Retries: &one},
+	}
+
+	if p := e.Policy("init"); p.Timeout != time.Minute || p.Retries != 1 {
+		t.Errorf("expected the override to win over the command, got %+v", p)
+	}
+	if p := e.TaskPolicy(&tasks.TaskConfig{Retries: &five}); p.Retries != 1 {
+		t.Errorf("expected the override to win over the task, got %+v", p)
+	}
+}
+
+func TestExecutionConfig_ApplyRetriesExplicitOnly(t *testing.T) {
+	one, three := 1, 3
+	e := &ExecutionConfig{
+		Retries:  2,
+		Override: &CommandExecution{Retries: &one},
+	}
+
+	for _, command := range []string{"apply", "destroy"} {
+		if p := e.Policy(command); p.Retries != 0 {
+			t.Errorf("expected global retries not to reach %s, got %d retries", command, p.Retries)
+		}
+	}
+	if p := e.Policy("plan"); p.Retries != 1 {
+		t.Errorf("expected plan to use the override, got %d retries", p.Retries)
+	}
+
+	e.Override = nil
+	e.Commands = map[string]*CommandExecution{"apply": {Retries: &three}}
+	if p := e.Policy("apply"); p.Retries != 3 {
+		t.Errorf("expected execution.commands.apply.retries to apply, got %d retries", p.Retries)
+	}
+}
+
+func TestLoad_InvalidExecution(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"negative retries", "execution:\n  retries: -1\n", "invalid execution retries '-1'"},
+		{"negative timeout", "execution:\n  timeout: -5m\n", "invalid execution timeout '-5m0s'"},
+		{"invalid pattern", "execution:\n  retry_on: [\"provider (\"]\n", "invalid execution retry_on pattern 'provider ('"},
+		{"unknown command", "execution:\n  commands:\n    graph:\n      retries: 1\n", "invalid execution command 'graph'"},
+		{"negative command retries", "execution:\n  commands:\n    init:\n      retries: -2\n", "invalid execution command 'init' retries '-2'"},
+		{"negative task timeout", "tasks:\n  lint:\n    command: tflint\n    timeout: -1s\n", "invalid task 'lint' timeout '-1s'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfigContent(t, tt.content)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModuleFileName is the name of the optional per-module metadata file
+const ModuleFileName = ".motf.module.yml"
+
+// Module lifecycle statuses
+const (
+	LifecycleExperimental = "experimental"
+	LifecycleStable       = "stable"
+	LifecycleDeprecated   = "deprecated"
+)
+
+// validLifecycleNames is the single source of truth for allowed lifecycle values.
+var validLifecycleNames = []string{LifecycleExperimental, LifecycleStable, LifecycleDeprecated}
+
+var validLifecycles = toSet(validLifecycleNames)
+
+// IsValidLifecycle reports whether lifecycle is an allowed module lifecycle value.
+func IsValidLifecycle(lifecycle string) bool {
+	_, ok := validLifecycles[lifecycle]
+	return ok
+}
+
+// ValidLifecycleNames returns the allowed module lifecycle values.
+func ValidLifecycleNames() []string { return append([]string(nil), validLifecycleNames...) }
+
+// ModuleConfig represents the optional .motf.module.yml file in a module directory.
+// Binary and Test override the values from .motf.yml for that module only.
+type ModuleConfig struct {
+	Description string      `yaml:"description"`
+	Tags        []string    `yaml:"tags"`
+	Owners      []string    `yaml:"owners"`
+	Lifecycle   string      `yaml:"lifecycle"`
+	Binary      string      `yaml:"binary"`
+	Test        *TestConfig `yaml:"test"`
+}
+
+// HasTag reports whether the module is tagged with tag
+func (m *ModuleConfig) HasTag(tag string) bool {
+	if m == nil {
+		return false
+	}
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// HasOverrides reports whether the module overrides the binary or test settings
+func (m *ModuleConfig) HasOverrides() bool {
+	return m != nil && (m.Binary != "" || m.Test != nil)
+}
+
+// LoadModuleConfig reads .motf.module.yml from modulePath.
+// It returns nil without an error if the module has no metadata file.
+func LoadModuleConfig(modulePath string) (*ModuleConfig, error) {
+	path := filepath.Join(modulePath, ModuleFileName)
+	data, err := os.ReadFile(path) //nolint:gosec // path is constructed from the module directory and a known file name
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	mc := &ModuleConfig{}
+	if err := yaml.Unmarshal(data, mc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if err := validateModuleConfig(mc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return mc, nil
+}
+
+// validateModuleConfig validates the values set in a parsed ModuleConfig.
+// Unlike validateConfig, empty values are kept so they fall back to .motf.yml.
+func validateModuleConfig(mc *ModuleConfig) error {
+	if mc.Lifecycle != "" && !IsValidLifecycle(mc.Lifecycle) {
+		return fmt.Errorf("invalid lifecycle '%s': must be %s", mc.Lifecycle, quotedJoin(ValidLifecycleNames()))
+	}
+	if mc.Binary != "" && !IsValidBinary(mc.Binary) {
+		return fmt.Errorf("invalid binary '%s': must be %s", mc.Binary, quotedJoin(ValidBinaryNames()))
+	}
+	if mc.Test != nil && mc.Test.Engine != "" && !IsValidTestEngine(mc.Test.Engine) {
+		return fmt.Errorf("invalid test engine '%s': must be %s", mc.Test.Engine, quotedJoin(ValidTestEngineNames()))
+	}
+	return nil
+}
+
+// ForModule returns a copy of the config with the module's binary and test
+// overrides applied. When a module sets test.engine, test.args is taken from
+// the module as well, since arguments for one engine rarely suit another.
+// The config itself is returned unchanged if the module has no overrides.
+func (c *Config) ForModule(mc *ModuleConfig) *Config {
+	if !mc.HasOverrides() {
+		return c
+	}
+
+	merged := *c
+	if mc.Binary != "" {
+		merged.Binary = mc.Binary
+	}
+
+	if mc.Test != nil {
+		test := TestConfig{}
+		if c.Test != nil {
+			test = *c.Test
+		}
+		if mc.Test.Engine != "" {
+			test.Engine = mc.Test.Engine
+			test.Args = mc.Test.Args
+		} else if mc.Test.Args != "" {
+			test.Args = mc.Test.Args
+		}
+		merged.Test = &test
+	}
+
+	return &merged
+}
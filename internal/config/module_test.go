@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeModuleConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ModuleFileName), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", ModuleFileName, err)
+	}
+}
+
+func TestLoadModuleConfig_Missing(t *testing.T) {
+	mc, err := LoadModuleConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadModuleConfig() returned error: %v", err)
+	}
+	if mc != nil {
+		t.Errorf("expected nil config for a module without %s, got %+v", ModuleFileName, mc)
+	}
+	if mc.HasTag("anything") || mc.HasOverrides() {
+		t.Error("nil ModuleConfig should have no tags and no overrides")
+	}
+}
+
+func TestLoadModuleConfig_AllFields(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleConfig(t, dir, `description: Storage account with private endpoints
+tags: [storage, tofu-ready]
+owners: ["@platform-team"]
+lifecycle: stable
+binary: tofu
+test:
+  engine: tofu
+  args: -verbose
+`)
+
+	mc, err := LoadModuleConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadModuleConfig() returned error: %v", err)
+	}
+
+	if mc.Description != "Storage account with private endpoints" {
+		t.Errorf("unexpected description %q", mc.Description)
+	}
+	if !mc.HasTag("tofu-ready") || mc.HasTag("network") {
+		t.Errorf("unexpected tags %v", mc.Tags)
+	}
+	if len(mc.Owners) != 1 || mc.Owners[0] != "@platform-team" {
+		t.Errorf("unexpected owners %v", mc.Owners)
+	}
+	if mc.Lifecycle != LifecycleStable {
+		t.Errorf("unexpected lifecycle %q", mc.Lifecycle)
+	}
+	if mc.Binary != "tofu" || mc.Test.Engine != "tofu" || mc.Test.Args != "-verbose" {
+		t.Errorf("unexpected overrides: binary=%q test=%+v", mc.Binary, mc.Test)
+	}
+	if !mc.HasOverrides() {
+		t.Error("expected HasOverrides() to be true")
+	}
+}
+
+func TestLoadModuleConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"lifecycle", "lifecycle: retired\n", "invalid lifecycle 'retired'"},
+		{"binary", "binary: terragrunt\n", "invalid binary 'terragrunt'"},
+		{"test engine", "test:\n  engine: pytest\n", "invalid test engine 'pytest'"},
+		{"yaml", "tags: [unclosed\n", "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeModuleConfig(t, dir, tt.content)
+
+			_, err := LoadModuleConfig(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(err.Error(), ModuleFileName) {
+				t.Errorf("error should name the metadata file: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_ForModule(t *testing.T) {
+	base := &Config{Binary: "terraform", Test: &TestConfig{Engine: "terratest", Args: "-timeout 30m"}}
+
+	tests := []struct {
+		name       string
+		mc         *ModuleConfig
+		wantBinary string
+		wantTest   TestConfig
+	}{
+		{"no metadata", nil, "terraform", TestConfig{Engine: "terratest", Args: "-timeout 30m"}},
+		{"metadata only", &ModuleConfig{Tags: []string{"x"}}, "terraform", TestConfig{Engine: "terratest", Args: "-timeout 30m"}},
+		{"binary", &ModuleConfig{Binary: "tofu"}, "tofu", TestConfig{Engine: "terratest", Args: "-timeout 30m"}},
+		{"engine replaces args", &ModuleConfig{Test: &TestConfig{Engine: "tofu"}}, "terraform", TestConfig{Engine: "tofu", Args: ""}},
+		{"args only", &ModuleConfig{Test: &TestConfig{Args: "-v"}}, "terraform", TestConfig{Engine: "terratest", Args: "-v"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := base.ForModule(tt.mc)
+			if got.Binary != tt.wantBinary {
+				t.Errorf("Binary = %q, want %q", got.Binary, tt.wantBinary)
+			}
+			if *got.Test != tt.wantTest {
+				t.Errorf("Test = %+v, want %+v", *got.Test, tt.wantTest)
+			}
+		})
+	}
+
+	if base.Binary != "terraform" || base.Test.Engine != "terratest" || base.Test.Args != "-timeout 30m" {
+		t.Errorf("ForModule must not modify the original config: %+v %+v", base, base.Test)
+	}
+}
@@ -17,44 +17,6 @@ var skipDirs = map[string]bool{
 	".spacelift":   true,
 }
 
-// FindModule searches for a module with the given name in the specified search path
-// It recursively searches subdirectories and returns all matching directories
-// Only directories containing .tf or .tf.json files are considered valid modules
-func FindModule(searchPath, moduleName string) ([]string, error) {
-	var matches []string
-
-	err := filepath.WalkDir(searchPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if !d.IsDir() {
-			return nil
-		}
-
-		// Skip excluded directories
-		if skipDirs[d.Name()] {
-			return filepath.SkipDir
-		}
-
-		// Check if directory name matches the module name
-		if d.Name() == moduleName {
-			// Verify it contains .tf or .tf.json files
-			if HasTerraformFiles(path) {
-				matches = append(matches, path)
-			}
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	return matches, nil
-}
-
 // HasTerraformFiles checks if a directory contains any .tf or .tf.json files
 func HasTerraformFiles(dir string) bool {
 	entries, err := os.ReadDir(dir)
@@ -67,8 +29,7 @@ func HasTerraformFiles(dir string) bool {
 			continue
 		}
 
-		name := entry.Name()
-		if filepath.Ext(name) == ".tf" || strings.HasSuffix(name, ".tf.json") {
+		if isTerraformFile(entry.Name()) {
 			return true
 		}
 	}
@@ -76,43 +37,9 @@ func HasTerraformFiles(dir string) bool {
 	return false
 }
 
-// ListAllModules finds all modules in the specified search path
-// Returns a map of module names to their paths
-func ListAllModules(searchPath string) (map[string]string, error) {
-	modules := make(map[string]string)
-
-	err := filepath.WalkDir(searchPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if !d.IsDir() {
-			return nil
-		}
-
-		// Skip excluded directories
-		if skipDirs[d.Name()] {
-			return filepath.SkipDir
-		}
-
-		// Check if this directory contains terraform files
-		if HasTerraformFiles(path) {
-			// Use the directory name as the module name
-			moduleName := d.Name()
-			// Store the path (only if not already seen or if it's a shorter path)
-			if _, exists := modules[moduleName]; !exists {
-				modules[moduleName] = path
-			}
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	return modules, nil
+// isTerraformFile reports whether name is a .tf or .tf.json file name
+func isTerraformFile(name string) bool {
+	return filepath.Ext(name) == ".tf" || strings.HasSuffix(name, ".tf.json")
 }
 
 // MatchesWildcard checks if a name matches a wildcard pattern
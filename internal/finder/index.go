@@ -0,0 +1,297 @@
+package finder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// indexVersion is bumped whenever the cached index format or discovery
+// semantics change, so older cache files are rebuilt
+const indexVersion = 1
+
+// racyWindow is how recently a directory may have been modified for the index
+// to still be cached. Directory mtimes are not precise enough to notice a
+// change made in the same instant the directory was read.
+const racyWindow = time.Second
+
+// IndexedModule is a module found while building an Index
+type IndexedModule struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	SearchPath string `json:"search_path"`
+}
+
+// Index is a snapshot of the modules below a set of search paths, built in a
+// single concurrent walk. It records the modification time of every directory
+// it walked, so a cached index can be checked for freshness with one stat per
+// directory instead of reading every directory again.
+type Index struct {
+	Key     string           `json:"key"`
+	Modules []IndexedModule  `json:"modules"`
+	Dirs    map[string]int64 `json:"dirs"`
+	Missing []string         `json:"missing,omitempty"`
+
+	paths map[string]bool
+}
+
+// BuildIndex walks searchPaths concurrently, skipping directories according to
+// r, and returns the modules found. Search paths that don't exist are recorded
+// as missing rather than returning an error.
+func (r *Rules) BuildIndex(searchPaths []string) (*Index, error) {
+	w := &indexWalker{
+		rules: r,
+		sem:   make(chan struct{}, runtime.NumCPU()*4),
+		index: &Index{Key: r.indexKey(searchPaths), Dirs: make(map[string]int64)},
+	}
+
+	for _, searchPath := range searchPaths {
+		if _, err := os.Stat(searchPath); os.IsNotExist(err) {
+			w.index.Missing = append(w.index.Missing, searchPath)
+			continue
+		}
+		w.wg.Add(1)
+		go w.walk(searchPath, searchPath)
+	}
+	w.wg.Wait()
+
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	sortIndexedModules(w.index.Modules, searchPaths)
+	w.index.buildLookup()
+	return w.index, nil
+}
+
+// CachedIndex returns the index cached at cachePath if it was built for the
+// same rules and search paths and no walked directory changed since, and
+// otherwise builds a new index and caches it. An empty cachePath disables
+// caching. Failing to write the cache is not an error.
+func (r *Rules) CachedIndex(cachePath string, searchPaths []string) (*Index, error) {
+	if cachePath == "" {
+		return r.BuildIndex(searchPaths)
+	}
+
+	key := r.indexKey(searchPaths)
+	if idx := loadIndex(cachePath, key); idx != nil && idx.fresh() {
+		return idx, nil
+	}
+
+	started := time.Now()
+	idx, err := r.BuildIndex(searchPaths)
+	if err != nil {
+		return nil, err
+	}
+	if !idx.racy(started) {
+		_ = idx.save(cachePath)
+	}
+	return idx, nil
+}
+
+// ModulesIn returns the modules found below searchPath, in walk order
+func (idx *Index) ModulesIn(searchPath string) []IndexedModule {
+	var modules []IndexedModule
+	for _, m := range idx.Modules {
+		if m.SearchPath == searchPath {
+			modules = append(modules, m)
+		}
+	}
+	return modules
+}
+
+// Lookup returns all modules named name, in walk order. A name containing a
+// slash (e.g. "azurerm/storage-account") is matched against the end of the
+// module path instead.
+func (idx *Index) Lookup(name string) []IndexedModule {
+	var modules []IndexedModule
+	for _, m := range idx.Modules {
+		if MatchesQualifiedName(m.Path, name) {
+			modules = append(modules, m)
+		}
+	}
+	return modules
+}
+
+// Has reports whether path is an indexed module
+func (idx *Index) Has(path string) bool {
+	return idx.paths[path]
+}
+
+// buildLookup indexes the module paths for Has
+func (idx *Index) buildLookup() {
+	idx.paths = make(map[string]bool, len(idx.Modules))
+	for _, m := range idx.Modules {
+		idx.paths[m.Path] = true
+	}
+}
+
+// fresh reports whether every walked directory still has the recorded mtime
+// and every missing search path is still missing
+func (idx *Index) fresh() bool {
+	for dir, mtime := range idx.Dirs {
+		info, err := os.Stat(dir)
+		if err != nil || info.ModTime().UnixNano() != mtime {
+			return false
+		}
+	}
+	for _, path := range idx.Missing {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// racy reports whether a walked directory was modified so close to the start
+// of the walk that a later change might not alter its mtime
+func (idx *Index) racy(started time.Time) bool {
+	limit := started.Add(-racyWindow).UnixNano()
+	for _, mtime := range idx.Dirs {
+		if mtime >= limit {
+			return true
+		}
+	}
+	return false
+}
+
+// loadIndex reads the index cached at path, returning nil if it is missing,
+// unreadable or was built with a different key
+func loadIndex(path, key string) *Index {
+	data, err := os.ReadFile(path) //nolint:gosec // path is the motf cache file
+	if err != nil {
+		return nil
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Key != key {
+		return nil
+	}
+	idx.buildLookup()
+	return &idx
+}
+
+// save writes the index to path, replacing any previous cache atomically
+func (idx *Index) save(path string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal module index: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write module index: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write module index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write module index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write module index: %w", err)
+	}
+	return nil
+}
+
+// indexKey identifies the rules and search paths an index was built for
+func (r *Rules) indexKey(searchPaths []string) string {
+	return fmt.Sprintf("v%d\x00%s\x00%s", indexVersion, r.key, strings.Join(searchPaths, "\x00"))
+}
+
+// indexWalker builds an Index, reading directories concurrently
+type indexWalker struct {
+	rules *Rules
+	sem   chan struct{}
+	wg    sync.WaitGroup
+
+	mu    sync.Mutex
+	index *Index
+	err   error
+}
+
+// walk reads dir once, records it and its module (if it contains terraform
+// files), and walks its subdirectories that are not skipped
+func (w *indexWalker) walk(searchPath, dir string) {
+	defer w.wg.Done()
+
+	w.sem <- struct{}{}
+	info, err := os.Stat(dir)
+	var entries []os.DirEntry
+	if err == nil {
+		entries, err = os.ReadDir(dir)
+	}
+	<-w.sem
+
+	if err != nil {
+		w.mu.Lock()
+		if w.err == nil {
+			w.err = err
+		}
+		w.mu.Unlock()
+		return
+	}
+
+	hasTerraform := false
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			hasTerraform = hasTerraform || isTerraformFile(entry.Name())
+			continue
+		}
+
+		// Skip excluded directories
+		child := filepath.Join(dir, entry.Name())
+		if w.rules.Skips(child) {
+			continue
+		}
+		w.wg.Add(1)
+		go w.walk(searchPath, child)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.index.Dirs[dir] = info.ModTime().UnixNano()
+	if hasTerraform {
+		w.index.Modules = append(w.index.Modules, IndexedModule{
+			Name:       filepath.Base(dir),
+			Path:       dir,
+			SearchPath: searchPath,
+		})
+	}
+}
+
+// sortIndexedModules sorts modules by search path in the given order, then in
+// the order filepath.WalkDir visits them: depth-first, entries in lexical order
+func sortIndexedModules(modules []IndexedModule, searchPaths []string) {
+	order := make(map[string]int, len(searchPaths))
+	for i, searchPath := range searchPaths {
+		order[searchPath] = i
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		a, b := modules[i], modules[j]
+		if a.SearchPath != b.SearchPath {
+			return order[a.SearchPath] < order[b.SearchPath]
+		}
+		ap := strings.Split(filepath.ToSlash(a.Path), "/")
+		bp := strings.Split(filepath.ToSlash(b.Path), "/")
+		for k := 0; k < len(ap) && k < len(bp); k++ {
+			if ap[k] != bp[k] {
+				return ap[k] < bp[k]
+			}
+		}
+		return len(ap) < len(bp)
+	})
+}
@@ -0,0 +1,181 @@
+package finder
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// backdate sets the mtime of every directory below root to an hour ago, so an
+// index built from it is not considered racy
+func backdate(t *testing.T, root string) {
+	t.Helper()
+	past := time.Now().Add(-time.Hour)
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		return os.Chtimes(path, past, past)
+	})
+	if err != nil {
+		t.Fatalf("failed to backdate %s: %v", root, err)
+	}
+}
+
+// indexedPaths returns the module paths of idx relative to root
+func indexedPaths(t *testing.T, idx *Index, root string) []string {
+	t.Helper()
+	var paths []string
+	for _, m := range idx.Modules {
+		rel, err := filepath.Rel(root, m.Path)
+		if err != nil {
+			t.Fatalf("failed to make %s relative: %v", m.Path, err)
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	return paths
+}
+
+func TestRules_BuildIndex(t *testing.T) {
+	root := createRulesTree(t)
+	for _, dir := range []string{"bases/app", "bases/app-db"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create module directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dir, "main.tf.json"), []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to create .tf.json file: %v", err)
+		}
+	}
+
+	rules, err := NewRules(root, []string{"vendor"}, nil)
+	if err != nil {
+		t.Fatalf("NewRules returned error: %v", err)
+	}
+
+	searchPaths := []string{
+		filepath.Join(root, "components"),
+		filepath.Join(root, "bases"),
+		filepath.Join(root, "projects"),
+	}
+	idx, err := rules.BuildIndex(searchPaths)
+	if err != nil {
+		t.Fatalf("BuildIndex returned error: %v", err)
+	}
+
+	// Search path order first, then walk order
+	want := []string{
+		"components/legacy/old-app",
+		"components/legacy/old-app/.terragrunt-cache/copy",
+		"components/storage-account",
+		"bases/app",
+		"bases/app-db",
+	}
+	if got := indexedPaths(t, idx, root); !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildIndex() modules = %v, want %v", got, want)
+	}
+
+	if got := idx.ModulesIn(searchPaths[1]); len(got) != 2 || got[0].Name != "app" {
+		t.Errorf("ModulesIn(bases) = %v, want app and app-db", got)
+	}
+	if got := idx.Lookup("storage-account"); len(got) != 1 || got[0].Path != filepath.Join(root, "components", "storage-account") {
+		t.Errorf("Lookup(storage-account) = %v", got)
+	}
+	if !idx.Has(filepath.Join(root, "bases", "app")) || idx.Has(filepath.Join(root, "components", "vendor", "acme")) {
+		t.Error("Has() should report indexed modules only")
+	}
+	if !reflect.DeepEqual(idx.Missing, []string{searchPaths[2]}) {
+		t.Errorf("Missing = %v, want [%s]", idx.Missing, searchPaths[2])
+	}
+}
+
+func TestRules_BuildIndex_SkipsToolDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		"storage-account",
+		"storage-account/.terraform/modules/storage-account",
+		".git/storage-account",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(dir))
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatalf("failed to create module directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "main.tf"), []byte("# terraform"), 0644); err != nil {
+			t.Fatalf("failed to create .tf file: %v", err)
+		}
+	}
+
+	idx, err := DefaultRules().BuildIndex([]string{root})
+	if err != nil {
+		t.Fatalf("BuildIndex returned error: %v", err)
+	}
+	if got := indexedPaths(t, idx, root); !reflect.DeepEqual(got, []string{"storage-account"}) {
+		t.Errorf("BuildIndex() modules = %v, want only storage-account", got)
+	}
+}
+
+func TestRules_CachedIndex(t *testing.T) {
+	root := createRulesTree(t)
+	backdate(t, root)
+
+	cachePath := filepath.Join(t.TempDir(), "motf", "modules.json")
+	searchPaths := []string{filepath.Join(root, "components")}
+	rules := DefaultRules()
+
+	idx, err := rules.CachedIndex(cachePath, searchPaths)
+	if err != nil {
+		t.Fatalf("CachedIndex returned error: %v", err)
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("expected index to be cached: %v", err)
+	}
+
+	// An unchanged tree is served from the cache
+	cached := loadIndex(cachePath, rules.indexKey(searchPaths))
+	if cached == nil || !cached.fresh() {
+		t.Fatal("expected cached index to be fresh")
+	}
+	if !reflect.DeepEqual(cached.Modules, idx.Modules) {
+		t.Errorf("cached modules = %v, want %v", cached.Modules, idx.Modules)
+	}
+
+	// Adding a module changes the mtime of its parent directory
+	newModule := filepath.Join(root, "components", "key-vault")
+	if err := os.Mkdir(newModule, 0755); err != nil {
+		t.Fatalf("failed to create module directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(newModule, "main.tf"), []byte("# terraform"), 0644); err != nil {
+		t.Fatalf("failed to create .tf file: %v", err)
+	}
+
+	idx, err = rules.CachedIndex(cachePath, searchPaths)
+	if err != nil {
+		t.Fatalf("CachedIndex returned error: %v", err)
+	}
+	if !idx.Has(newModule) {
+		t.Error("expected a changed tree to be indexed again")
+	}
+
+	// Different rules do not reuse the cache
+	other, err := NewRules(root, []string{"legacy"}, nil)
+	if err != nil {
+		t.Fatalf("NewRules returned error: %v", err)
+	}
+	if loadIndex(cachePath, other.indexKey(searchPaths)) != nil {
+		t.Error("expected cache built with other rules to be ignored")
+	}
+}
+
+func TestRules_CachedIndex_SkipsRacyTree(t *testing.T) {
+	root := createRulesTree(t)
+	cachePath := filepath.Join(t.TempDir(), "modules.json")
+
+	// The tree was just created, so a change right after could go unnoticed
+	if _, err := DefaultRules().CachedIndex(cachePath, []string{filepath.Join(root, "components")}); err != nil {
+		t.Fatalf("CachedIndex returned error: %v", err)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("expected no cache for a freshly modified tree, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package finder
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MatchesQualifiedName reports whether the module at path is named name. A
+// bare name matches the last path component; a name with slashes, such as
+// "azurerm/storage-account", must match the last components of the path.
+func MatchesQualifiedName(path, name string) bool {
+	name = strings.Trim(filepath.ToSlash(name), "/")
+	if name == "" {
+		return false
+	}
+	path = filepath.ToSlash(path)
+	return path == name || strings.HasSuffix(path, "/"+name)
+}
+
+// QualifiedNames returns the shortest path suffix that tells each of paths
+// apart from the others, e.g. "azurerm/naming" and "aws/naming".
+func QualifiedNames(paths []string) []string {
+	parts := make([][]string, len(paths))
+	for i, path := range paths {
+		parts[i] = strings.Split(filepath.ToSlash(path), "/")
+	}
+
+	names := make([]string, len(paths))
+	for i := range paths {
+		for n := 1; n <= len(parts[i]); n++ {
+			names[i] = strings.Join(parts[i][len(parts[i])-n:], "/")
+			unique := true
+			for j := range paths {
+				if j != i && MatchesQualifiedName(paths[j], names[i]) {
+					unique = false
+					break
+				}
+			}
+			if unique {
+				break
+			}
+		}
+	}
+	return names
+}
+
+// Suggest returns up to limit candidates that are close to name by edit
+// distance, closest first. Candidates further than a third of the name's
+// length (and at least two edits) away are not suggested.
+func Suggest(name string, candidates []string, limit int) []string {
+	maxDistance := len(name) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	var suggestions []suggestion
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		if d := editDistance(name, candidate); d <= maxDistance {
+			suggestions = append(suggestions, suggestion{candidate, d})
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return suggestions[i].name < suggestions[j].name
+	})
+
+	var names []string
+	for i := 0; i < len(suggestions) && i < limit; i++ {
+		names = append(names, suggestions[i].name)
+	}
+	return names
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
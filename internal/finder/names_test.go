@@ -0,0 +1,89 @@
+package finder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchesQualifiedName(t *testing.T) {
+	tests := []struct {
+		path string
+		name string
+		want bool
+	}{
+		{"/repo/components/azurerm/naming", "naming", true},
+		{"/repo/components/azurerm/naming", "azurerm/naming", true},
+		{"/repo/components/azurerm/naming", "components/azurerm/naming", true},
+		{"/repo/components/azurerm/naming", "/azurerm/naming/", true},
+		{"/repo/components/azurerm/naming", "rm/naming", false},
+		{"/repo/components/azurerm/naming", "aws/naming", false},
+		{"/repo/components/azurerm/naming", "nam", false},
+		{"/repo/components/azurerm/naming", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesQualifiedName(tt.path, tt.name); got != tt.want {
+				t.Errorf("MatchesQualifiedName(%q, %q) = %v, want %v", tt.path, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualifiedNames(t *testing.T) {
+	paths := []string{
+		"/repo/components/azurerm/naming",
+		"/repo/components/aws/naming",
+		"/repo/bases/naming",
+	}
+	want := []string{"azurerm/naming", "aws/naming", "bases/naming"}
+
+	if got := QualifiedNames(paths); !reflect.DeepEqual(got, want) {
+		t.Errorf("QualifiedNames() = %v, want %v", got, want)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"storage-account", "storage-queue", "key-vault", "storage-account"}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"storage-acount", []string{"storage-account"}},
+		{"storage-queu", []string{"storage-queue"}},
+		{"keyvault", []string{"key-vault"}},
+		{"network", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Suggest(tt.name, candidates, 3); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if got := Suggest("storage-x", []string{"storage-a", "storage-b", "storage-c"}, 2); len(got) != 2 {
+		t.Errorf("expected suggestions limited to 2, got %v", got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"naming", "naming", 0},
+		{"naming", "namng", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package finder
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// IgnoreFileName is the name of the optional file with gitignore-style discovery exclusions
+const IgnoreFileName = ".motfignore"
+
+// Rules decides which directories are skipped during module discovery.
+//
+// The exclude patterns and the .motfignore patterns are evaluated with gitignore
+// semantics, the last matching pattern deciding (so "!modules" re-includes
+// directories named modules). Directories no pattern decides on are skipped if
+// their name is one of the built-in skipDirs. A directory that matches an
+// include pattern is never skipped. All patterns are relative to the root.
+type Rules struct {
+	root     string
+	patterns []gitignore.Pattern
+	include  gitignore.Matcher
+	key      string
+}
+
+// DefaultRules returns rules that only skip the built-in skipDirs
+func DefaultRules() *Rules {
+	return &Rules{}
+}
+
+// NewRules returns rules for discovery under root, with exclude and include
+// patterns (e.g. from .motf.yml) and the patterns of root/.motfignore, if present.
+func NewRules(root string, exclude, include []string) (*Rules, error) {
+	for _, p := range append(append([]string{}, exclude...), include...) {
+		if err := ValidatePattern(p); err != nil {
+			return nil, err
+		}
+	}
+
+	ignored, err := readIgnoreFile(filepath.Join(root, IgnoreFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	excludes := append(append([]string{}, exclude...), ignored...)
+	rules := &Rules{
+		root:     root,
+		patterns: parsePatterns(excludes),
+		key:      strings.Join([]string{root, strings.Join(excludes, "\n"), strings.Join(include, "\n")}, "\x00"),
+	}
+	if len(include) > 0 {
+		rules.include = gitignore.NewMatcher(parsePatterns(include))
+	}
+	return rules, nil
+}
+
+// ValidatePattern returns an error if p is not a valid discovery glob pattern
+func ValidatePattern(p string) error {
+	glob := strings.Trim(strings.TrimPrefix(p, "!"), "/")
+	if glob == "" {
+		return fmt.Errorf("invalid discovery pattern '%s': pattern is empty", p)
+	}
+	if _, err := filepath.Match(glob, ""); err != nil {
+		return fmt.Errorf("invalid discovery pattern '%s': %w", p, err)
+	}
+	return nil
+}
+
+// MatchesPath reports whether path, relative to the root and slash-separated,
+// matches the gitignore-style patterns, the last matching pattern deciding
+func MatchesPath(path string, patterns []string) bool {
+	return gitignore.NewMatcher(parsePatterns(patterns)).Match(strings.Split(path, "/"), true)
+}
+
+// Skips reports whether the directory at path is excluded from discovery
+func (r *Rules) Skips(path string) bool {
+	parts := r.relativeParts(path)
+	if parts == nil {
+		return skipDirs[filepath.Base(path)]
+	}
+
+	if r.include != nil && r.include.Match(parts, true) {
+		return false
+	}
+	for i := len(r.patterns) - 1; i >= 0; i-- {
+		switch r.patterns[i].Match(parts, true) {
+		case gitignore.Exclude:
+			return true
+		case gitignore.Include:
+			return false
+		}
+	}
+	return skipDirs[parts[len(parts)-1]]
+}
+
+// relativeParts splits path relative to the root, or returns nil when there is
+// no root or path is not below it.
+func (r *Rules) relativeParts(path string) []string {
+	if r.root == "" {
+		return nil
+	}
+	rel, err := filepath.Rel(r.root, path)
+	if err != nil {
+		return nil
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return nil
+	}
+	return strings.Split(rel, "/")
+}
+
+// parsePatterns parses gitignore-style patterns relative to the root
+func parsePatterns(lines []string) []gitignore.Pattern {
+	var patterns []gitignore.Pattern
+	for _, line := range lines {
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	return patterns
+}
+
+// readIgnoreFile returns the patterns in a .motfignore file, skipping blank
+// lines and comments. A missing file has no patterns.
+func readIgnoreFile(path string) ([]string, error) {
+	f, err := os.Open(path) //nolint:gosec // path is the root directory joined with a known file name
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,185 @@
+package finder
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// createRulesTree creates modules under root/components, including ones in
+// directories that are skipped by default or commonly excluded
+func createRulesTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	for _, dir := range []string{
+		"components/storage-account",
+		"components/storage-account/examples/basic",
+		"components/network/modules/subnet",
+		"components/vendor/acme",
+		"components/legacy/old-app",
+		"components/legacy/old-app/.terragrunt-cache/copy",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(dir))
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatalf("failed to create module directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "main.tf"), []byte("# terraform"), 0644); err != nil {
+			t.Fatalf("failed to create .tf file: %v", err)
+		}
+	}
+	return root
+}
+
+// listNames returns the sorted names of the modules rules index under root/components
+func listNames(t *testing.T, rules *Rules, root string) []string {
+	t.Helper()
+	idx, err := rules.BuildIndex([]string{filepath.Join(root, "components")})
+	if err != nil {
+		t.Fatalf("BuildIndex returned error: %v", err)
+	}
+	var names []string
+	for _, m := range idx.Modules {
+		names = append(names, m.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRules_Discovery(t *testing.T) {
+	tests := []struct {
+		name       string
+		exclude    []string
+		include    []string
+		motfignore string
+		want       []string
+	}{
+		{
+			name: "built-in skipped directories only",
+			want: []string{"acme", "copy", "old-app", "storage-account"},
+		},
+		{
+			name:    "exclude by name and by path",
+			exclude: []string{"vendor", ".terragrunt-cache", "components/legacy/**"},
+			want:    []string{"storage-account"},
+		},
+		{
+			name:    "include discovers modules in directories named modules",
+			exclude: []string{"vendor"},
+			include: []string{"components/network/modules"},
+			want:    []string{"copy", "old-app", "storage-account", "subnet"},
+		},
+		{
+			name:       "motfignore with comments and negation",
+			motfignore: "# vendored code\nvendor/\n.terragrunt-cache\n!modules\n",
+			want:       []string{"old-app", "storage-account", "subnet"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := createRulesTree(t)
+			if tt.motfignore != "" {
+				if err := os.WriteFile(filepath.Join(root, IgnoreFileName), []byte(tt.motfignore), 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", IgnoreFileName, err)
+				}
+			}
+
+			rules, err := NewRules(root, tt.exclude, tt.include)
+			if err != nil {
+				t.Fatalf("NewRules returned error: %v", err)
+			}
+
+			got := listNames(t, rules, root)
+			if len(got) != len(tt.want) {
+				t.Fatalf("modules = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("modules = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestRules_SearchPathNamedLikeSkippedDir(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"modules/network", "modules/network/modules/subnet"} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0755); err != nil {
+			t.Fatalf("failed to create module directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(dir), "main.tf"), []byte("# terraform"), 0644); err != nil {
+			t.Fatalf("failed to create .tf file: %v", err)
+		}
+	}
+
+	rules, err := NewRules(root, nil, nil)
+	if err != nil {
+		t.Fatalf("NewRules returned error: %v", err)
+	}
+
+	idx, err := rules.BuildIndex([]string{filepath.Join(root, "modules")})
+	if err != nil {
+		t.Fatalf("BuildIndex returned error: %v", err)
+	}
+	if len(idx.Modules) != 1 || idx.Modules[0].Name != "network" {
+		t.Errorf("expected only the network module, got %v", idx.Modules)
+	}
+}
+
+func TestRules_IncludeSkippedDir(t *testing.T) {
+	root := createRulesTree(t)
+	searchPaths := []string{filepath.Join(root, "components")}
+
+	rules, err := NewRules(root, nil, []string{"modules"})
+	if err != nil {
+		t.Fatalf("NewRules returned error: %v", err)
+	}
+	idx, err := rules.BuildIndex(searchPaths)
+	if err != nil {
+		t.Fatalf("BuildIndex returned error: %v", err)
+	}
+	if matches := idx.Lookup("subnet"); len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %v", matches)
+	}
+
+	idx, err = DefaultRules().BuildIndex(searchPaths)
+	if err != nil {
+		t.Fatalf("BuildIndex returned error: %v", err)
+	}
+	if matches := idx.Lookup("subnet"); len(matches) != 0 {
+		t.Errorf("expected the default rules to skip modules directories, got %v", matches)
+	}
+}
+
+func TestNewRules_InvalidPattern(t *testing.T) {
+	if _, err := NewRules(t.TempDir(), []string{"vendor/[a-"}, nil); err == nil {
+		t.Error("expected error for invalid exclude pattern, got nil")
+	}
+	if _, err := NewRules(t.TempDir(), nil, []string{"!"}); err == nil {
+		t.Error("expected error for empty include pattern, got nil")
+	}
+}
+
+func TestMatchesPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		patterns []string
+		want     bool
+	}{
+		{"components/azurerm/storage", []string{"components/azurerm/**"}, true},
+		{"components/aws/storage", []string{"components/azurerm/**"}, false},
+		{"components/azurerm/storage", []string{"storage"}, true},
+		{"components/azurerm/storage", []string{"*storage*"}, true},
+		{"components/azurerm/legacy", []string{"components/**", "!components/azurerm/legacy"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchesPath(tt.path, tt.patterns); got != tt.want {
+			t.Errorf("MatchesPath(%q, %v) = %v, want %v", tt.path, tt.patterns, got, tt.want)
+		}
+	}
+}
@@ -130,6 +130,9 @@ func getUncommittedChanges(repo *git.Repository) ([]string, error) {
 	return files, nil
 }
 
+// ErrNotRepository is wrapped by the errors of functions run outside a git repository
+var ErrNotRepository = git.ErrRepositoryNotExists
+
 // GetRepoRoot returns the root directory of the git repository.
 func GetRepoRoot() (string, error) {
 	// Start from current directory and walk up to find .git
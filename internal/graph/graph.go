@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Graph is a directed dependency graph keyed by node ID.
+// An edge from A to B means "A depends on B".
+type Graph struct {
+	nodes      map[string]bool
+	deps       map[string]map[string]bool // node -> nodes it depends on
+	dependents map[string]map[string]bool // node -> nodes that depend on it
+}
+
+// New creates an empty Graph
+func New() *Graph {
+	return &Graph{
+		nodes:      make(map[string]bool),
+		deps:       make(map[string]map[string]bool),
+		dependents: make(map[string]map[string]bool),
+	}
+}
+
+// AddNode adds a node to the graph. Adding an existing node is a no-op.
+func (g *Graph) AddNode(id string) {
+	g.nodes[id] = true
+}
+
+// AddEdge records that from depends on to. Both nodes are added if missing.
+// Self-edges are ignored.
+func (g *Graph) AddEdge(from, to string) {
+	if from == to {
+		return
+	}
+	g.AddNode(from)
+	g.AddNode(to)
+
+	if g.deps[from] == nil {
+		g.deps[from] = make(map[string]bool)
+	}
+	g.deps[from][to] = true
+
+	if g.dependents[to] == nil {
+		g.dependents[to] = make(map[string]bool)
+	}
+	g.dependents[to][from] = true
+}
+
+// HasNode reports whether id is a node in the graph
+func (g *Graph) HasNode(id string) bool {
+	return g.nodes[id]
+}
+
+// Nodes returns all node IDs in sorted order
+func (g *Graph) Nodes() []string {
+	return sortedKeys(g.nodes)
+}
+
+// Dependencies returns the direct dependencies of id in sorted order
+func (g *Graph) Dependencies(id string) []string {
+	return sortedKeys(g.deps[id])
+}
+
+// Dependents returns the nodes that directly depend on id in sorted order
+func (g *Graph) Dependents(id string) []string {
+	return sortedKeys(g.dependents[id])
+}
+
+// TransitiveDependencies returns every node reachable from ids by following
+// dependency edges. The starting nodes are not included unless they are
+// reachable from another starting node.
+func (g *Graph) TransitiveDependencies(ids ...string) []string {
+	return g.walk(ids, g.deps)
+}
+
+// TransitiveDependents returns every node that directly or indirectly depends
+// on any of ids. The starting nodes are not included unless they depend on
+// another starting node.
+func (g *Graph) TransitiveDependents(ids ...string) []string {
+	return g.walk(ids, g.dependents)
+}
+
+// walk performs a breadth-first traversal over edges starting from ids
+func (g *Graph) walk(ids []string, edges map[string]map[string]bool) []string {
+	visited := make(map[string]bool)
+	queue := append([]string(nil), ids...)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for next := range edges[current] {
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			queue = append(queue, next)
+		}
+	}
+
+	return sortedKeys(visited)
+}
+
+// CycleError is returned when the graph contains a dependency cycle
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dependency cycle detected: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// TopologicalSort returns the nodes ordered so that every node appears after
+// all of its dependencies. Ties are broken alphabetically for stable output.
+// Returns a *CycleError if the graph contains a cycle.
+func (g *Graph) TopologicalSort() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make(map[string]int, len(g.nodes))
+	order := make([]string, 0, len(g.nodes))
+	var stack []string
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			// Extract the cycle from the current stack
+			start := 0
+			for i, n := range stack {
+				if n == id {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string(nil), stack[start:]...), id)
+			return &CycleError{Cycle: cycle}
+		}
+
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, dep := range g.Dependencies(id) {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+		order = append(order, id)
+		return nil
+	}
+
+	for _, id := range g.Nodes() {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// newTestGraph builds the graph: project -> base -> component, project -> other
+func newTestGraph() *Graph {
+	g := New()
+	g.AddEdge("project", "base")
+	g.AddEdge("base", "component")
+	g.AddEdge("project", "other")
+	g.AddNode("lonely")
+	return g
+}
+
+func TestGraph_Nodes(t *testing.T) {
+	g := newTestGraph()
+
+	want := []string{"base", "component", "lonely", "other", "project"}
+	if got := g.Nodes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Nodes() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_DirectEdges(t *testing.T) {
+	g := newTestGraph()
+
+	if got, want := g.Dependencies("project"), []string{"base", "other"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies(project) = %v, want %v", got, want)
+	}
+	if got, want := g.Dependents("component"), []string{"base"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependents(component) = %v, want %v", got, want)
+	}
+	if got := g.Dependencies("lonely"); len(got) != 0 {
+		t.Errorf("Dependencies(lonely) = %v, want empty", got)
+	}
+}
+
+func TestGraph_AddEdgeIgnoresSelfEdges(t *testing.T) {
+	g := New()
+	g.AddEdge("a", "a")
+
+	if g.HasNode("a") {
+		t.Error("self-edge should not add a node")
+	}
+}
+
+func TestGraph_TransitiveDependents(t *testing.T) {
+	g := newTestGraph()
+
+	tests := []struct {
+		name string
+		ids  []string
+		want []string
+	}{
+		{
+			name: "leaf component reaches project through base",
+			ids:  []string{"component"},
+			want: []string{"base", "project"},
+		},
+		{
+			name: "multiple starting nodes",
+			ids:  []string{"component", "other"},
+			want: []string{"base", "project"},
+		},
+		{
+			name: "node without dependents",
+			ids:  []string{"project"},
+			want: []string{},
+		},
+		{
+			name: "unknown node",
+			ids:  []string{"missing"},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := g.TransitiveDependents(tt.ids...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TransitiveDependents(%v) = %v, want %v", tt.ids, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_TransitiveDependencies(t *testing.T) {
+	g := newTestGraph()
+
+	want := []string{"base", "component", "other"}
+	if got := g.TransitiveDependencies("project"); !reflect.DeepEqual(got, want) {
+		t.Errorf("TransitiveDependencies(project) = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_TopologicalSort(t *testing.T) {
+	g := newTestGraph()
+
+	order, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort() returned error: %v", err)
+	}
+
+	position := make(map[string]int)
+	for i, id := range order {
+		position[id] = i
+	}
+
+	if len(order) != 5 {
+		t.Fatalf("expected 5 nodes in order, got %v", order)
+	}
+	if position["component"] > position["base"] {
+		t.Errorf("component should come before base: %v", order)
+	}
+	if position["base"] > position["project"] {
+		t.Errorf("base should come before project: %v", order)
+	}
+	if position["other"] > position["project"] {
+		t.Errorf("other should come before project: %v", order)
+	}
+}
+
+func TestGraph_TopologicalSort_Cycle(t *testing.T) {
+	g := New()
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "a")
+
+	_, err := g.TopologicalSort()
+	if err == nil {
+		t.Fatal("expected cycle error, got nil")
+	}
+
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected *CycleError, got %T", err)
+	}
+
+	want := []string{"a", "b", "c", "a"}
+	if !reflect.DeepEqual(cycleErr.Cycle, want) {
+		t.Errorf("Cycle = %v, want %v", cycleErr.Cycle, want)
+	}
+}
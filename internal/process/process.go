@@ -0,0 +1,46 @@
+// Package process starts the external commands run by motf, such as
+// terraform/tofu and task commands, and stops them gracefully when motf is
+// interrupted.
+package process
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// killDelay is how long an interrupted command may take to stop before it is killed
+var killDelay = 15 * time.Second
+
+// Command returns an exec.Cmd that runs name with args and is interrupted when
+// ctx is done. The process is sent SIGINT rather than killed, so terraform/tofu
+// can stop gracefully and release state locks. Where interrupts are not
+// supported (Windows), the process is killed instead.
+//
+// On Unix the command runs in its own process group, so a Ctrl-C in the
+// terminal reaches only motf, which forwards a single SIGINT to the whole
+// group. If ctx comes from NotifyContext, a second signal kills the group.
+// A group still running 15 seconds after the interrupt is killed as well.
+func Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		if k, ok := ctx.Value(killerKey{}).(*killer); ok {
+			k.add(cmd.Process)
+		}
+		p := cmd.Process
+		time.AfterFunc(killDelay, func() { _ = kill(p) })
+		return interrupt(p)
+	}
+	// Stop waiting for output held open by processes that outlive the group leader
+	cmd.WaitDelay = killDelay
+	return cmd
+}
+
+// Interactive returns an exec.Cmd for a command that reads from the terminal,
+// such as an apply prompting for approval. It stays in motf's process group to
+// be able to read from the terminal, so it receives Ctrl-C from the terminal
+// directly and is not signalled by motf.
+func Interactive(name string, args ...string) *exec.Cmd {
+	return exec.Command(name, args...)
+}
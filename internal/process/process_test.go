@@ -0,0 +1,122 @@
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommand_InterruptsOnCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts are not supported on Windows")
+	}
+
+	script := filepath.Join(t.TempDir(), "wait.sh")
+	content := "#!/bin/sh\ntrap 'echo interrupted; exit 130' INT\necho started\nsleep 10 >/dev/null 2>&1 &\nwait\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A file rather than a buffer, so it can be read while the command runs
+	outPath := filepath.Join(t.TempDir(), "out")
+	outFile, err := os.Create(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = outFile.Close() }()
+	output := func() string {
+		data, _ := os.ReadFile(outPath)
+		return string(data)
+	}
+
+	cmd := Command(ctx, script)
+	cmd.Stdout = outFile
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the trap to be installed before cancelling
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(output(), "started") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	if err := cmd.Wait(); err == nil {
+		t.Fatal("expected an error from the interrupted command")
+	}
+	if !strings.Contains(output(), "interrupted") {
+		t.Errorf("expected the command to receive SIGINT, got output: %q", output())
+	}
+	if code := cmd.ProcessState.ExitCode(); code != 130 {
+		t.Errorf("expected exit code 130 from the trap, got %d", code)
+	}
+}
+
+func TestCommand_KillsWhenInterruptIsIgnored(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts are not supported on Windows")
+	}
+
+	oldDelay := killDelay
+	killDelay = 100 * time.Millisecond
+	defer func() { killDelay = oldDelay }()
+
+	script := filepath.Join(t.TempDir(), "stubborn.sh")
+	content := "#!/bin/sh\ntrap 'echo interrupted' INT\necho started\nwhile true; do sleep 0.1; done\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(t.TempDir(), "out")
+	outFile, err := os.Create(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = outFile.Close() }()
+	output := func() string {
+		data, _ := os.ReadFile(outPath)
+		return string(data)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := Command(ctx, script)
+	cmd.Stdout = outFile
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitErr := make(chan error, 1)
+	go func() { waitErr <- cmd.Wait() }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(output(), "started") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case err := <-waitErr:
+		if err == nil {
+			t.Fatal("expected an error from the killed command")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the command to be killed after ignoring SIGINT")
+	}
+	if !strings.Contains(output(), "interrupted") {
+		t.Errorf("expected the command to receive SIGINT first, got output: %q", output())
+	}
+}
+
+func TestCommand_NotCancelled(t *testing.T) {
+	cmd := Command(context.Background(), "go", "version")
+	if err := cmd.Run(); err != nil {
+		t.Errorf("expected command to succeed, got %v", err)
+	}
+}
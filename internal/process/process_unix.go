@@ -0,0 +1,34 @@
+//go:build !windows
+
+package process
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup makes cmd the leader of a new process group
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interrupt sends SIGINT to the process group led by p
+func interrupt(p *os.Process) error {
+	return signalGroup(p, syscall.SIGINT)
+}
+
+// kill sends SIGKILL to the process group led by p
+func kill(p *os.Process) error {
+	return signalGroup(p, syscall.SIGKILL)
+}
+
+// signalGroup sends sig to the process group led by p, so that processes it
+// started (such as terraform started by a task's shell) receive it too.
+// Returns os.ErrProcessDone if p has already exited.
+func signalGroup(p *os.Process, sig syscall.Signal) error {
+	if err := p.Signal(syscall.Signal(0)); err != nil {
+		return err
+	}
+	return syscall.Kill(-p.Pid, sig)
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package process
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on Windows
+func setProcessGroup(*exec.Cmd) {}
+
+// interrupt kills p, as Windows does not support sending interrupts
+func interrupt(p *os.Process) error {
+	err := p.Signal(os.Interrupt)
+	if err == nil || errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	return p.Kill()
+}
+
+// kill kills p
+func kill(p *os.Process) error {
+	return p.Kill()
+}
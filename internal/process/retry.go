@@ -0,0 +1,191 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// retryOutputBytes is how much of an attempt's output is kept to match
+// against the retry patterns
+const retryOutputBytes = 64 * 1024
+
+// Policy controls how long a command may run and how it is retried when it
+// fails with output matching one of RetryOn
+type Policy struct {
+	Timeout time.Duration    // per attempt; zero means no timeout
+	Retries int              // number of retries after the first attempt
+	Backoff time.Duration    // delay before the first retry, doubled for each further retry
+	RetryOn []*regexp.Regexp // output patterns of failures worth retrying
+
+	// Accept lists exit codes that report a result rather than a failure, such
+	// as 2 of plan -detailed-exitcode. They are returned without retrying.
+	Accept []int
+}
+
+// TimeoutError is returned for a command that was interrupted because it ran
+// longer than its timeout
+type TimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s (%v)", e.Timeout, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Run runs the command returned by newCmd, interrupting each attempt after
+// p.Timeout. A failed attempt whose stdout or stderr matches one of p.RetryOn
+// is retried up to p.Retries times with exponential backoff, announcing each
+// retry on log. Timed out attempts are not retried, and neither is anything
+// once ctx is done.
+func (p Policy) Run(ctx context.Context, log io.Writer, newCmd func(ctx context.Context) *exec.Cmd) error {
+	for attempt := 0; ; attempt++ {
+		var output *outputTail
+		if p.Retries > 0 && len(p.RetryOn) > 0 {
+			output = &outputTail{}
+		}
+
+		err := p.runAttempt(ctx, newCmd, output)
+		if err == nil || attempt >= p.Retries || output == nil || ctx.Err() != nil {
+			return err
+		}
+		var timeout *TimeoutError
+		if errors.As(err, &timeout) || p.accepts(err) {
+			return err
+		}
+		pattern := p.match(output.Bytes())
+		if pattern == nil {
+			return err
+		}
+
+		delay := p.Backoff << attempt
+		_, _ = fmt.Fprintf(log, "Retrying in %s (attempt %d of %d): output matched %q\n", delay, attempt+2, p.Retries+1, pattern.String())
+		if a, ok := ctx.Value(attemptsKey{}).(*Attempts); ok {
+			a.addRetry()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// accepts reports whether err is an exit with one of the codes in p.Accept
+func (p Policy) accepts(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && slices.Contains(p.Accept, exitErr.ExitCode())
+}
+
+// runAttempt runs a single attempt, copying its output to output if set
+func (p Policy) runAttempt(ctx context.Context, newCmd func(ctx context.Context) *exec.Cmd, output *outputTail) error {
+	attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+	if p.Timeout > 0 {
+		attemptCtx, cancel = context.WithTimeout(ctx, p.Timeout)
+	}
+	defer cancel()
+
+	cmd := newCmd(attemptCtx)
+	if output != nil {
+		// Keep a shared stdout/stderr writer shared, so that exec still
+		// serializes writes to it
+		sameWriter := cmd.Stdout == cmd.Stderr
+		cmd.Stdout = teeWriter(cmd.Stdout, output)
+		if sameWriter {
+			cmd.Stderr = cmd.Stdout
+		} else {
+			cmd.Stderr = teeWriter(cmd.Stderr, output)
+		}
+	}
+
+	err := cmd.Run()
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Timeout: p.Timeout, Err: err}
+	}
+	return err
+}
+
+// match returns the first retry pattern found in output, or nil
+func (p Policy) match(output []byte) *regexp.Regexp {
+	for _, re := range p.RetryOn {
+		if re.Match(output) {
+			return re
+		}
+	}
+	return nil
+}
+
+// teeWriter returns a writer that writes to both w, which may be nil, and output
+func teeWriter(w io.Writer, output *outputTail) io.Writer {
+	if w == nil {
+		return output
+	}
+	return io.MultiWriter(w, output)
+}
+
+// outputTail keeps the last retryOutputBytes written to it. It is safe for
+// concurrent use, since stdout and stderr are copied by separate goroutines.
+type outputTail struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (t *outputTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - retryOutputBytes; over > 0 {
+		t.buf = t.buf[over:]
+	}
+	return len(p), nil
+}
+
+// Bytes returns the kept output
+func (t *outputTail) Bytes() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.buf
+}
+
+// attemptsKey is the context key of the Attempts recorded by Policy.Run
+type attemptsKey struct{}
+
+// Attempts counts the retries made by Policy.Run for commands run with a
+// context returned by WithAttempts. It is safe for concurrent use.
+type Attempts struct {
+	mu      sync.Mutex
+	retries int
+}
+
+// WithAttempts returns a copy of ctx that records retries in the returned Attempts
+func WithAttempts(ctx context.Context) (context.Context, *Attempts) {
+	a := &Attempts{}
+	return context.WithValue(ctx, attemptsKey{}, a), a
+}
+
+// Retries returns the number of retries recorded so far
+func (a *Attempts) Retries() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.retries
+}
+
+func (a *Attempts) addRetry() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.retries++
+}
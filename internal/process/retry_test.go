@@ -0,0 +1,199 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flakyScript writes a script that fails with output until it has run
+// failures times, counting runs in a file next to it
+func flakyScript(t *testing.T, failures int, output string) string {
+	t.Helper()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "flaky.sh")
+	content := "#!/bin/sh\necho run >> \"" + filepath.Join(dir, "runs") + "\"\n" +
+		"if [ \"$(wc -l < \"" + filepath.Join(dir, "runs") + "\")\" -le " + strconv.Itoa(failures) + " ]; then\n" +
+		"  echo '" + output + "' >&2\n  exit 1\nfi\necho done\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestPolicy_RetriesMatchingFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	script := flakyScript(t, 2, "Error: Failed to install provider")
+	policy := Policy{Retries: 2, Backoff: time.Millisecond, RetryOn: []*regexp.Regexp{regexp.MustCompile("Failed to install provider")}}
+	ctx, attempts := WithAttempts(context.Background())
+
+	var out, log bytes.Buffer
+	err := policy.Run(ctx, &log, func(ctx context.Context) *exec.Cmd {
+		cmd := Command(ctx, script)
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		return cmd
+	})
+	if err != nil {
+		t.Fatalf("expected the third attempt to succeed, got %v", err)
+	}
+	if attempts.Retries() != 2 {
+		t.Errorf("Retries() = %d, want 2", attempts.Retries())
+	}
+	if !strings.Contains(log.String(), `Retrying in 2ms (attempt 3 of 3): output matched "Failed to install provider"`) {
+		t.Errorf("expected retries to be announced, got: %s", log.String())
+	}
+	if !strings.HasSuffix(out.String(), "done\n") {
+		t.Errorf("expected the output of every attempt, got: %s", out.String())
+	}
+}
+
+func TestPolicy_DoesNotRetryOtherFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+	}{
+		{"output does not match", Policy{Retries: 2, RetryOn: []*regexp.Regexp{regexp.MustCompile("TLS handshake timeout")}}},
+		{"no retries", Policy{RetryOn: []*regexp.Regexp{regexp.MustCompile("Unsupported argument")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := flakyScript(t, 1, "Error: Unsupported argument")
+			ctx, attempts := WithAttempts(context.Background())
+
+			var log bytes.Buffer
+			err := tt.policy.Run(ctx, &log, func(ctx context.Context) *exec.Cmd {
+				return Command(ctx, script)
+			})
+			if err == nil {
+				t.Fatal("expected the failure to be returned")
+			}
+			if attempts.Retries() != 0 || log.Len() != 0 {
+				t.Errorf("expected no retry, got %d: %s", attempts.Retries(), log.String())
+			}
+		})
+	}
+}
+
+func TestPolicy_DoesNotRetryAcceptedExitCodes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "drift.sh")
+	content := "#!/bin/sh\necho run >> \"" + filepath.Join(dir, "runs") + "\"\necho 'Error: Failed to install provider' >&2\nexit 2\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	policy := Policy{Retries: 2, Backoff: time.Millisecond, RetryOn: []*regexp.Regexp{regexp.MustCompile("Failed to install provider")}, Accept: []int{2}}
+
+	err := policy.Run(context.Background(), io.Discard, func(ctx context.Context) *exec.Cmd {
+		return Command(ctx, script)
+	})
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2 to be returned, got %v", err)
+	}
+	if runs, _ := os.ReadFile(filepath.Join(dir, "runs")); strings.Count(string(runs), "\n") != 1 {
+		t.Errorf("expected an accepted exit code not to be retried, ran %d times", strings.Count(string(runs), "\n"))
+	}
+}
+
+func TestPolicy_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts are not supported on Windows")
+	}
+
+	policy := Policy{Timeout: 100 * time.Millisecond, Retries: 2, RetryOn: []*regexp.Regexp{regexp.MustCompile(".")}}
+	ctx, attempts := WithAttempts(context.Background())
+
+	start := time.Now()
+	err := policy.Run(ctx, &bytes.Buffer{}, func(ctx context.Context) *exec.Cmd {
+		return Command(ctx, "sh", "-c", "echo waiting; sleep 10")
+	})
+
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || timeout.Timeout != 100*time.Millisecond {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "timed out after 100ms") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+	if attempts.Retries() != 0 {
+		t.Errorf("expected timed out commands not to be retried, got %d retries", attempts.Retries())
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the command to be interrupted, took %s", elapsed)
+	}
+}
+
+func TestPolicy_TimeoutKillsCommandIgnoringInterrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts are not supported on Windows")
+	}
+
+	oldDelay := killDelay
+	killDelay = 100 * time.Millisecond
+	defer func() { killDelay = oldDelay }()
+
+	policy := Policy{Timeout: 100 * time.Millisecond}
+	start := time.Now()
+	err := policy.Run(context.Background(), &bytes.Buffer{}, func(ctx context.Context) *exec.Cmd {
+		return Command(ctx, "sh", "-c", "trap '' INT; while true; do sleep 0.1; done")
+	})
+
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the command to be killed after ignoring SIGINT, took %s", elapsed)
+	}
+}
+
+func TestPolicy_StopsRetryingWhenCancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	script := flakyScript(t, 1, "TLS handshake timeout")
+	policy := Policy{Retries: 1, Backoff: time.Hour, RetryOn: []*regexp.Regexp{regexp.MustCompile("TLS handshake timeout")}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- policy.Run(ctx, &bytes.Buffer{}, func(ctx context.Context) *exec.Cmd {
+			return Command(ctx, script)
+		})
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected the failed attempt's error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the backoff to end when the context is cancelled")
+	}
+}
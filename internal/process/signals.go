@@ -0,0 +1,109 @@
+package process
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// SignalError is the cause of a context cancelled by NotifyContext
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return "received " + SignalName(e.Signal)
+}
+
+// SignalName returns the conventional name of sig, such as SIGINT
+func SignalName(sig os.Signal) string {
+	switch sig {
+	case os.Interrupt:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	}
+	return sig.String()
+}
+
+// NotifyContext returns a copy of parent that is cancelled with a
+// *SignalError cause on the first SIGINT or SIGTERM, which interrupts every
+// command started with it. A second signal kills those commands. onSignal, if
+// not nil, is called for both signals, with force set for the second one.
+// stop releases the signal handler.
+func NotifyContext(parent context.Context, onSignal func(sig os.Signal, force bool)) (ctx context.Context, stop func()) {
+	k := &killer{}
+	ctx, cancel := context.WithCancelCause(context.WithValue(parent, killerKey{}, k))
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		var sig os.Signal
+		select {
+		case sig = <-signals:
+		case <-done:
+			return
+		}
+		if onSignal != nil {
+			onSignal(sig, false)
+		}
+		cancel(&SignalError{Signal: sig})
+
+		select {
+		case sig = <-signals:
+		case <-done:
+			return
+		}
+		if onSignal != nil {
+			onSignal(sig, true)
+		}
+		k.killAll()
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			cancel(context.Canceled)
+		})
+	}
+	return ctx, stop
+}
+
+// killerKey is the context key of the killer of a NotifyContext context
+type killerKey struct{}
+
+// killer kills interrupted processes on the second signal
+type killer struct {
+	mu     sync.Mutex
+	procs  []*os.Process
+	killed bool
+}
+
+// add records an interrupted process, killing it right away if the second
+// signal was already received
+func (k *killer) add(p *os.Process) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.killed {
+		_ = kill(p)
+		return
+	}
+	k.procs = append(k.procs, p)
+}
+
+// killAll kills every recorded process and any added later
+func (k *killer) killAll() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.killed = true
+	for _, p := range k.procs {
+		_ = kill(p)
+	}
+	k.procs = nil
+}
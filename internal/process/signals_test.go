@@ -0,0 +1,32 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestNotifyContext_Stop(t *testing.T) {
+	ctx, stop := NotifyContext(context.Background(), nil)
+	stop()
+	stop()
+
+	if ctx.Err() == nil {
+		t.Error("expected stop to cancel the context")
+	}
+	var sigErr *SignalError
+	if errors.As(context.Cause(ctx), &sigErr) {
+		t.Error("expected no signal cause after stop")
+	}
+}
+
+func TestSignalName(t *testing.T) {
+	if got := SignalName(os.Interrupt); got != "SIGINT" {
+		t.Errorf("SignalName(os.Interrupt) = %q, want SIGINT", got)
+	}
+	if got := SignalName(syscall.SIGTERM); got != "SIGTERM" {
+		t.Errorf("SignalName(SIGTERM) = %q, want SIGTERM", got)
+	}
+}
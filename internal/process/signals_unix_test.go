@@ -0,0 +1,87 @@
+//go:build !windows
+
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyContext_InterruptThenKill(t *testing.T) {
+	var mu sync.Mutex
+	var received []bool
+	ctx, stop := NotifyContext(context.Background(), func(sig os.Signal, force bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, force)
+	})
+	defer stop()
+
+	// The script survives SIGINT, so only the second signal stops it
+	script := filepath.Join(t.TempDir(), "stubborn.sh")
+	content := "#!/bin/sh\ntrap 'echo interrupted' INT\necho started\nwhile true; do sleep 0.1; done\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(t.TempDir(), "out")
+	outFile, err := os.Create(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = outFile.Close() }()
+	waitForOutput := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if data, _ := os.ReadFile(outPath); strings.Contains(string(data), want) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %q", want)
+	}
+
+	cmd := Command(ctx, script)
+	cmd.Stdout = outFile
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitErr := make(chan error, 1)
+	go func() { waitErr <- cmd.Wait() }()
+	waitForOutput("started")
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+	<-ctx.Done()
+	var sigErr *SignalError
+	if cause := context.Cause(ctx); !errors.As(cause, &sigErr) || sigErr.Error() != "received SIGINT" {
+		t.Fatalf("expected SIGINT cause, got %v", cause)
+	}
+	waitForOutput("interrupted")
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-waitErr:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the second signal to kill the command")
+	}
+	if status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); !ok || status.Signal() != syscall.SIGKILL {
+		t.Errorf("expected the command to be killed, got %v", cmd.ProcessState)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[0] || !received[1] {
+		t.Errorf("expected an interrupt then a forced signal, got %v", received)
+	}
+}
@@ -12,6 +12,7 @@ const (
 	EnvModuleName = "MOTF_MODULE_NAME"
 	EnvConfigPath = "MOTF_CONFIG_PATH"
 	EnvBinary     = "MOTF_BINARY"
+	EnvHook       = "MOTF_HOOK" // name of the running hook, e.g. pre_init
 )
 
 // EnvBuilder constructs environment variables for task execution.
@@ -0,0 +1,182 @@
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BuiltinNames are the terraform/tofu commands tasks can reference in
+// depends_on and steps. A task with the same name takes precedence.
+var BuiltinNames = []string{"fmt", "init", "plan", "test", "validate"}
+
+// Builtin runs a terraform/tofu command in workDir for a task that depends on it
+type Builtin func(workDir string, stdout, stderr io.Writer) error
+
+// Step is one step of a composite task: either a shell command or a
+// reference to another task or built-in
+type Step struct {
+	Command string `yaml:"command"`
+	Task    string `yaml:"task"`
+}
+
+// UnmarshalYAML accepts a step written as a plain command string as well as
+// a mapping with 'command' or 'task'
+func (s *Step) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&s.Command)
+	}
+	type plain Step
+	return value.Decode((*plain)(s))
+}
+
+// action is a single thing to run for a task: a shell command or a built-in
+type action struct {
+	task    string
+	first   bool // first action of its task, which prints the task header
+	builtin Builtin
+	command string
+	shell   string
+	skip    string // why the task does not apply to the target; nothing runs
+}
+
+// Validate checks that every task has something to run, that depends_on and
+// steps only reference defined tasks or built-ins, that there are no
+// dependency cycles, and that params, when conditions, and inputs and outputs
+// are declared correctly
+func Validate(taskConfigs map[string]*TaskConfig) error {
+	// Only the names of built-ins matter here, nothing is run
+	builtins := make(map[string]Builtin, len(BuiltinNames))
+	for _, name := range BuiltinNames {
+		builtins[name] = nil
+	}
+	r := &Runner{Tasks: taskConfigs, builtins: builtins}
+
+	names := make([]string, 0, len(taskConfigs))
+	for name := range taskConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := r.resolve(name); err != nil {
+			return err
+		}
+		if err := validateParams(name, taskConfigs[name]); err != nil {
+			return err
+		}
+		if err := validateWhen(name, taskConfigs[name].When); err != nil {
+			return err
+		}
+		if err := validatePatterns("inputs", name, taskConfigs[name].Inputs); err != nil {
+			return err
+		}
+		if err := validatePatterns("outputs", name, taskConfigs[name].Outputs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipReason returns why task, referenced by another task, is skipped, or ""
+// if it runs
+func (r *Runner) skipReason(task *TaskConfig) string {
+	switch {
+	case r.target != nil:
+		return task.SkipReason(*r.target)
+	case r.anyTarget && task.When != nil:
+		return "it does not apply to every module"
+	}
+	return ""
+}
+
+// resolve returns the actions of taskName in the order they run: the
+// dependencies of each task before its command or steps, and the tasks
+// referenced by steps in place. Every task and built-in runs once, however
+// often it is referenced. With a target, a dependency or step whose when
+// conditions exclude the target is skipped along with what only it needs.
+func (r *Runner) resolve(taskName string) ([]action, error) {
+	var actions []action
+	done := make(map[string]bool)
+	var visiting []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		for i, v := range visiting {
+			if v == name {
+				return fmt.Errorf("task dependency cycle: %s", strings.Join(append(visiting[i:], name), " -> "))
+			}
+		}
+		if done[name] {
+			return nil
+		}
+
+		task := r.GetTask(name)
+		if task == nil {
+			builtin, ok := r.builtins[name]
+			if !ok {
+				if len(visiting) > 0 {
+					return fmt.Errorf("task '%s' references unknown task '%s'", visiting[len(visiting)-1], name)
+				}
+				return fmt.Errorf("task '%s' not found", name)
+			}
+			actions = append(actions, action{task: name, first: true, builtin: builtin})
+			done[name] = true
+			return nil
+		}
+
+		if task.Command != "" && len(task.Steps) > 0 {
+			return fmt.Errorf("task '%s' cannot have both command and steps", name)
+		}
+		if task.Command == "" && len(task.Steps) == 0 && len(task.DependsOn) == 0 {
+			return fmt.Errorf("task '%s' has no command defined", name)
+		}
+		if len(visiting) > 0 {
+			if reason := r.skipReason(task); reason != "" {
+				actions = append(actions, action{task: name, first: true, skip: reason})
+				done[name] = true
+				return nil
+			}
+		}
+
+		visiting = append(visiting, name)
+		for _, dep := range task.DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		steps := task.Steps
+		if task.Command != "" {
+			steps = []Step{{Command: task.Command}}
+		}
+		first := true
+		for i, step := range steps {
+			switch {
+			case (step.Command == "") == (step.Task == ""):
+				return fmt.Errorf("step %d of task '%s' must have either a command or a task", i+1, name)
+			case step.Task != "":
+				if err := visit(step.Task); err != nil {
+					return err
+				}
+			default:
+				if _, _, err := GetShellArgs(task.Shell, step.Command); err != nil {
+					return fmt.Errorf("task '%s': %w", name, err)
+				}
+				actions = append(actions, action{task: name, first: first, command: step.Command, shell: task.Shell})
+				first = false
+			}
+		}
+		visiting = visiting[:len(visiting)-1]
+
+		done[name] = true
+		return nil
+	}
+
+	if err := visit(taskName); err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
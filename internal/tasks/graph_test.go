@@ -0,0 +1,138 @@
+package tasks
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestStep_UnmarshalYAML(t *testing.T) {
+	var task TaskConfig
+	content := "steps:\n  - terraform-docs .\n  - task: lint\n  - command: echo done\n"
+	if err := yaml.Unmarshal([]byte(content), &task); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	want := []Step{{Command: "terraform-docs ."}, {Task: "lint"}, {Command: "echo done"}}
+	if len(task.Steps) != len(want) {
+		t.Fatalf("Steps = %+v, want %+v", task.Steps, want)
+	}
+	for i := range want {
+		if task.Steps[i] != want[i] {
+			t.Errorf("Steps[%d] = %+v, want %+v", i, task.Steps[i], want[i])
+		}
+	}
+}
+
+func TestRunner_RunsDependenciesOnce(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"docs":  {DependsOn: []string{"init", "lint"}, Steps: []Step{{Command: "echo docs"}, {Task: "check"}}},
+		"lint":  {DependsOn: []string{"init"}, Command: "echo lint"},
+		"check": {DependsOn: []string{"lint"}, Command: "echo check"},
+	}, nil)
+
+	var inits int
+	r = r.WithBuiltins(map[string]Builtin{
+		"init": func(_ string, stdout, _ io.Writer) error {
+			inits++
+			_, _ = io.WriteString(stdout, "init\n")
+			return nil
+		},
+	})
+
+	var out bytes.Buffer
+	if err := r.RunWithOutput("docs", t.TempDir(), &out, &out); err != nil {
+		t.Fatalf("RunWithOutput() returned error: %v\n%s", err, out.String())
+	}
+	if inits != 1 {
+		t.Errorf("expected init to run once, ran %d times", inits)
+	}
+
+	var ran []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if !strings.HasPrefix(line, "Running") && !strings.HasPrefix(line, "$") {
+			ran = append(ran, line)
+		}
+	}
+	if got := strings.Join(ran, ","); got != "init,lint,docs,check" {
+		t.Errorf("ran %s, want init,lint,docs,check", got)
+	}
+	if strings.Count(out.String(), "Running task 'docs'") != 1 {
+		t.Errorf("expected one header for the steps of docs, got: %s", out.String())
+	}
+}
+
+func TestRunner_DependencyFailureStopsTask(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"lint": {Command: "exit 3"},
+		"ci":   {DependsOn: []string{"lint"}, Command: "echo should-not-run"},
+	}, nil)
+
+	var out bytes.Buffer
+	err := r.RunWithOutput("ci", t.TempDir(), &out, &out)
+	if err == nil || !strings.HasPrefix(err.Error(), "task 'lint': ") {
+		t.Fatalf("expected the failing dependency to be named, got %v", err)
+	}
+	if strings.Contains(out.String(), "Running task 'ci'") {
+		t.Errorf("expected ci not to start after lint failed, got: %s", out.String())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks map[string]*TaskConfig
+		want  string
+	}{
+		{
+			name: "valid",
+			tasks: map[string]*TaskConfig{
+				"ci":   {DependsOn: []string{"fmt", "validate", "lint"}},
+				"lint": {DependsOn: []string{"init"}, Steps: []Step{{Command: "tflint --init"}, {Command: "tflint"}}},
+			},
+		},
+		{
+			name:  "cycle",
+			tasks: map[string]*TaskConfig{"a": {DependsOn: []string{"b"}, Command: "true"}, "b": {Steps: []Step{{Task: "a"}}}},
+			want:  "task dependency cycle: a -> b -> a",
+		},
+		{
+			name:  "unknown reference",
+			tasks: map[string]*TaskConfig{"docs": {DependsOn: []string{"generate"}, Command: "true"}},
+			want:  "task 'docs' references unknown task 'generate'",
+		},
+		{
+			name:  "command and steps",
+			tasks: map[string]*TaskConfig{"docs": {Command: "true", Steps: []Step{{Command: "true"}}}},
+			want:  "task 'docs' cannot have both command and steps",
+		},
+		{
+			name:  "empty step",
+			tasks: map[string]*TaskConfig{"docs": {Steps: []Step{{Command: "true"}, {}}}},
+			want:  "step 2 of task 'docs' must have either a command or a task",
+		},
+		{
+			name:  "nothing to run",
+			tasks: map[string]*TaskConfig{"docs": {Description: "Generate docs"}},
+			want:  "task 'docs' has no command defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.tasks)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Validate() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Validate() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
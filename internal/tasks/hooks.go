@@ -0,0 +1,172 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
+	"gopkg.in/yaml.v3"
+)
+
+// Hook stages: pre hooks run before a command, post hooks after it succeeded
+const (
+	HookPre  = "pre"
+	HookPost = "post"
+)
+
+// HookCommands are the terraform/tofu commands hooks can run around
+var HookCommands = []string{"init", "fmt", "validate", "plan", "apply", "destroy", "test"}
+
+// Hook is a shell command run before or after a terraform/tofu command
+type Hook struct {
+	Shell   string `yaml:"shell"`
+	Command string `yaml:"command"`
+}
+
+// UnmarshalYAML accepts a hook written as a plain command string as well as
+// a mapping with 'command' and 'shell'
+func (h *Hook) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&h.Command)
+	}
+	type plain Hook
+	return value.Decode((*plain)(h))
+}
+
+// HookList is the hooks of one name, run in order
+type HookList []Hook
+
+// UnmarshalYAML accepts a single hook as well as a list of hooks
+func (l *HookList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.SequenceNode {
+		return value.Decode((*[]Hook)(l))
+	}
+	var hook Hook
+	if err := value.Decode(&hook); err != nil {
+		return err
+	}
+	*l = HookList{hook}
+	return nil
+}
+
+// Hooks are the hooks of .motf.yml, keyed by names such as pre_init or
+// post_validate
+type Hooks map[string]HookList
+
+// HookName returns the name of the hooks run at stage around command, e.g. pre_init
+func HookName(stage, command string) string {
+	return stage + "_" + command
+}
+
+// HookNames returns the valid hook names: pre_ and post_ of each command in HookCommands
+func HookNames() []string {
+	names := make([]string, 0, 2*len(HookCommands))
+	for _, command := range HookCommands {
+		names = append(names, HookName(HookPre, command), HookName(HookPost, command))
+	}
+	return names
+}
+
+// ValidateHooks checks that every hook has a known name, a command, and a
+// supported shell
+func ValidateHooks(hooks Hooks) error {
+	valid := HookNames()
+	for _, name := range sortedParamNames(hooks) {
+		if !contains(valid, name) {
+			return fmt.Errorf("unknown hook '%s': must be pre_ or post_ followed by one of %s", name, strings.Join(HookCommands, ", "))
+		}
+		for i, hook := range hooks[name] {
+			if strings.TrimSpace(hook.Command) == "" {
+				return fmt.Errorf("hook %d of '%s' has no command defined", i+1, name)
+			}
+			if _, _, err := GetShellArgs(hook.Shell, hook.Command); err != nil {
+				return fmt.Errorf("hook '%s': %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RunHooks runs the hooks of name in workDir, in order, with the environment
+// of r plus MOTF_HOOK. It stops at the first failing hook. Each hook run is
+// recorded in the HookRuns of r's context, if any.
+func (r *Runner) RunHooks(name string, hooks HookList, workDir string, stdout, stderr io.Writer) error {
+	env := append(append([]string(nil), r.Env...), EnvHook+"="+name)
+	runs := hookRunsFrom(r.context())
+
+	for i, hook := range hooks {
+		binary, args, err := GetShellArgs(hook.Shell, hook.Command)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			_, _ = fmt.Fprintf(stdout, "Running hook '%s' in %s\n", name, workDir)
+		}
+		_, _ = fmt.Fprintf(stdout, "$ %s\n", hook.Command)
+
+		start := time.Now()
+		err = r.policy.Run(r.context(), stderr, func(ctx context.Context) *exec.Cmd {
+			cmd := process.Command(ctx, binary, args...) //nolint:gosec // binary and args are from user-defined hook configuration
+			cmd.Dir = workDir
+			cmd.Stdout = stdout
+			cmd.Stderr = stderr
+			cmd.Env = env
+			return cmd
+		})
+		if runs != nil {
+			runs.add(HookRun{Name: name, Command: hook.Command, Duration: time.Since(start), Err: err})
+		}
+		if err != nil {
+			return fmt.Errorf("hook '%s' failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// HookRun records one hook that ran
+type HookRun struct {
+	Name     string
+	Command  string
+	Duration time.Duration
+	Err      error
+}
+
+// hookRunsKey is the context key of the HookRuns recorded by RunHooks
+type hookRunsKey struct{}
+
+// HookRuns records the hooks run by RunHooks with a context returned by
+// WithHookRuns. It is safe for concurrent use.
+type HookRuns struct {
+	mu   sync.Mutex
+	runs []HookRun
+}
+
+// WithHookRuns returns a copy of ctx that records hook runs in the returned HookRuns
+func WithHookRuns(ctx context.Context) (context.Context, *HookRuns) {
+	h := &HookRuns{}
+	return context.WithValue(ctx, hookRunsKey{}, h), h
+}
+
+// Runs returns the hook runs recorded so far, in the order they ran
+func (h *HookRuns) Runs() []HookRun {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]HookRun(nil), h.runs...)
+}
+
+func (h *HookRuns) add(run HookRun) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs = append(h.runs, run)
+}
+
+// hookRunsFrom returns the HookRuns of ctx, or nil
+func hookRunsFrom(ctx context.Context) *HookRuns {
+	h, _ := ctx.Value(hookRunsKey{}).(*HookRuns)
+	return h
+}
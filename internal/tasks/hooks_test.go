@@ -0,0 +1,88 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestHooks_UnmarshalYAML(t *testing.T) {
+	var hooks Hooks
+	err := yaml.Unmarshal([]byte(`
+pre_init: ./inject-secrets.sh
+post_validate:
+  - tflint
+  - shell: bash
+    command: checkov -d .
+`), &hooks)
+	if err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	want := Hooks{
+		"pre_init":      {{Command: "./inject-secrets.sh"}},
+		"post_validate": {{Command: "tflint"}, {Shell: "bash", Command: "checkov -d ."}},
+	}
+	if !reflect.DeepEqual(hooks, want) {
+		t.Errorf("hooks = %+v, want %+v", hooks, want)
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	if err := ValidateHooks(Hooks{"pre_init": {{Command: "true"}}, "post_test": {{Shell: "bash", Command: "true"}}}); err != nil {
+		t.Errorf("ValidateHooks returned error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		hooks Hooks
+		want  string
+	}{
+		{"unknown command", Hooks{"pre_show": {{Command: "true"}}}, "unknown hook 'pre_show': must be pre_ or post_ followed by one of init, fmt"},
+		{"unknown stage", Hooks{"after_init": {{Command: "true"}}}, "unknown hook 'after_init'"},
+		{"no command", Hooks{"pre_plan": {{Command: "true"}, {Shell: "bash"}}}, "hook 2 of 'pre_plan' has no command defined"},
+		{"unknown shell", Hooks{"pre_plan": {{Shell: "fish", Command: "true"}}}, "hook 'pre_plan': unknown shell 'fish'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateHooks(tt.hooks); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ValidateHooks() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunner_RunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir := t.TempDir()
+	ctx, runs := WithHookRuns(context.Background())
+	r := NewRunner(nil, append(os.Environ(), EnvModuleName+"=storage")).WithContext(ctx)
+
+	var stdout, stderr bytes.Buffer
+	hooks := HookList{{Command: `echo "$MOTF_HOOK $MOTF_MODULE_NAME"`}, {Command: "exit 3"}, {Command: "echo never"}}
+	err := r.RunHooks("pre_init", hooks, dir, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "hook 'pre_init' failed: exit status 3") {
+		t.Fatalf("expected the second hook to fail, got %v", err)
+	}
+
+	out := stdout.String()
+	if !strings.Contains(out, "Running hook 'pre_init' in "+dir) || !strings.Contains(out, "pre_init storage\n") {
+		t.Errorf("unexpected output: %s", out)
+	}
+	if strings.Contains(out, "never\n") {
+		t.Errorf("expected hooks to stop at the first failure, got: %s", out)
+	}
+
+	recorded := runs.Runs()
+	if len(recorded) != 2 || recorded[0].Err != nil || recorded[1].Err == nil || recorded[1].Command != "exit 3" {
+		t.Errorf("unexpected hook runs: %+v", recorded)
+	}
+}
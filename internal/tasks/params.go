@@ -0,0 +1,299 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// EnvParamPrefix is the prefix of the environment variables that hold task
+// params, e.g. MOTF_PARAM_LEVEL for the param 'level'
+const EnvParamPrefix = "MOTF_PARAM_"
+
+// paramName matches valid param names, which are usable as template fields
+// and in environment variable names
+var paramName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParamConfig declares a named input of a task
+type ParamConfig struct {
+	Description string   `yaml:"description"`
+	Type        string   `yaml:"type"` // string (default), int, or bool
+	Default     string   `yaml:"default"`
+	Required    bool     `yaml:"required"`
+	Allowed     []string `yaml:"allowed"`
+}
+
+// ParamTypes are the supported param types
+var ParamTypes = []string{"bool", "int", "string"}
+
+// ParseParams parses --param values of the form name=value
+func ParseParams(values []string) (map[string]string, error) {
+	params := make(map[string]string, len(values))
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --param '%s': must be name=value", v)
+		}
+		params[name] = value
+	}
+	return params, nil
+}
+
+// WithParams returns a copy of r that runs tasks with the given param values
+func (r *Runner) WithParams(values map[string]string) *Runner {
+	copied := *r
+	copied.params = values
+	return &copied
+}
+
+// CheckParams validates the param values of r against the params declared by
+// taskName and the tasks it depends on, without running anything
+func (r *Runner) CheckParams(taskName string) error {
+	actions, err := r.resolve(taskName)
+	if err != nil {
+		return err
+	}
+	_, err = r.taskParams(taskName, actions)
+	return err
+}
+
+// taskParams returns the params of each task of actions that runs, with
+// defaults applied. Params of tasks that may be skipped by their when
+// conditions are not required. Every value must be declared by taskName or a
+// task it references, whether or not that task runs.
+func (r *Runner) taskParams(taskName string, actions []action) (map[string]map[string]string, error) {
+	conditional, err := r.conditionalTasks(taskName, actions)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]map[string]string)
+	for _, a := range actions {
+		task := r.GetTask(a.task)
+		if task == nil || a.skip != "" || params[a.task] != nil {
+			continue
+		}
+		values := make(map[string]string, len(task.Params))
+		for _, name := range sortedParamNames(task.Params) {
+			p := task.Params[name]
+			if p == nil {
+				p = &ParamConfig{}
+			}
+			if conditional[a.task] && p.Required {
+				optional := *p
+				optional.Required = false
+				p = &optional
+			}
+			value, err := p.resolve(a.task, name, r.params)
+			if err != nil {
+				return nil, err
+			}
+			values[name] = value
+		}
+		params[a.task] = values
+	}
+
+	declared := r.declaredParams(taskName)
+	for _, name := range sortedParamNames(r.params) {
+		if !declared[name] {
+			return nil, fmt.Errorf("unknown param '%s' for task '%s'", name, taskName)
+		}
+	}
+	return params, nil
+}
+
+// conditionalTasks returns the tasks of actions that only run on modules their
+// when conditions, or those of a task referencing them, apply to. With a
+// target, the tasks that do not apply are skipped instead, so there are none.
+func (r *Runner) conditionalTasks(taskName string, actions []action) (map[string]bool, error) {
+	if r.target != nil {
+		return nil, nil
+	}
+	copied := *r
+	copied.anyTarget = true
+	unconditional, err := copied.resolve(taskName)
+	if err != nil {
+		return nil, err
+	}
+
+	always := make(map[string]bool, len(unconditional))
+	for _, a := range unconditional {
+		if a.skip == "" {
+			always[a.task] = true
+		}
+	}
+	conditional := make(map[string]bool)
+	for _, a := range actions {
+		if !always[a.task] {
+			conditional[a.task] = true
+		}
+	}
+	return conditional, nil
+}
+
+// declaredParams returns the names of the params declared by taskName and
+// every task it references through depends_on and steps
+func (r *Runner) declaredParams(taskName string) map[string]bool {
+	declared := make(map[string]bool)
+	visited := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		task := r.GetTask(name)
+		if task == nil || visited[name] {
+			return
+		}
+		visited[name] = true
+		for param := range task.Params {
+			declared[param] = true
+		}
+		for _, dep := range task.DependsOn {
+			visit(dep)
+		}
+		for _, step := range task.Steps {
+			visit(step.Task)
+		}
+	}
+	visit(taskName)
+	return declared
+}
+
+// resolve returns the value of the param name of task: the given value or
+// the default, checked against the type and allowed values
+func (p *ParamConfig) resolve(task, name string, values map[string]string) (string, error) {
+	value, ok := values[name]
+	if !ok {
+		if p.Required {
+			return "", fmt.Errorf("task '%s' requires param '%s' (--param %s=<value>)", task, name, name)
+		}
+		value = p.Default
+		if value == "" {
+			return "", nil
+		}
+	}
+	if err := p.check(value); err != nil {
+		return "", fmt.Errorf("invalid param '%s' of task '%s': %w", name, task, err)
+	}
+	return value, nil
+}
+
+// check validates a value against the type and allowed values of p
+func (p *ParamConfig) check(value string) error {
+	switch p.Type {
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("'%s' is not an int", value)
+		}
+	case "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("'%s' is not a bool", value)
+		}
+	}
+	if len(p.Allowed) > 0 {
+		for _, allowed := range p.Allowed {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("'%s' must be one of %s", value, strings.Join(p.Allowed, ", "))
+	}
+	return nil
+}
+
+// validateParams checks the param declarations of a task and that its
+// commands are valid templates of them
+func validateParams(name string, task *TaskConfig) error {
+	if len(task.Params) == 0 {
+		return nil
+	}
+
+	defaults := make(map[string]string, len(task.Params))
+	envNames := make(map[string]string, len(task.Params))
+	for _, param := range sortedParamNames(task.Params) {
+		p := task.Params[param]
+		if !paramName.MatchString(param) {
+			return fmt.Errorf("invalid param name '%s' of task '%s': must contain only letters, digits and underscores", param, name)
+		}
+		envName := EnvParamPrefix + strings.ToUpper(param)
+		if other, ok := envNames[envName]; ok {
+			return fmt.Errorf("params '%s' and '%s' of task '%s' both set %s: names must differ in more than case", other, param, name, envName)
+		}
+		envNames[envName] = param
+		if p == nil {
+			p = &ParamConfig{}
+		}
+		switch p.Type {
+		case "", "bool", "int", "string":
+		default:
+			return fmt.Errorf("invalid type '%s' of param '%s' of task '%s': must be %s", p.Type, param, name, strings.Join(ParamTypes, ", "))
+		}
+		for _, allowed := range p.Allowed {
+			if err := (&ParamConfig{Type: p.Type}).check(allowed); err != nil {
+				return fmt.Errorf("invalid allowed value of param '%s' of task '%s': %w", param, name, err)
+			}
+		}
+		if p.Default != "" {
+			if err := p.check(p.Default); err != nil {
+				return fmt.Errorf("invalid default of param '%s' of task '%s': %w", param, name, err)
+			}
+		}
+		defaults[param] = p.Default
+	}
+
+	commands := []string{task.Command}
+	for _, step := range task.Steps {
+		commands = append(commands, step.Command)
+	}
+	for _, command := range commands {
+		if _, err := renderCommand(command, defaults); err != nil {
+			return fmt.Errorf("task '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// paramsAction matches a template action referencing .Params, e.g. {{ .Params.level }}
+var paramsAction = regexp.MustCompile(`\{\{[^}]*\.Params\b`)
+
+// renderCommand substitutes {{ .Params.<name> }} in a command. Commands that
+// do not reference .Params are left as is, so that they may contain {{ for
+// other tools, such as jq or gotemplate.
+func renderCommand(command string, params map[string]string) (string, error) {
+	if !paramsAction.MatchString(command) {
+		return command, nil
+	}
+	tmpl, err := template.New("command").Option("missingkey=error").Parse(command)
+	if err != nil {
+		return "", fmt.Errorf("invalid command template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, struct{ Params map[string]string }{params}); err != nil {
+		return "", fmt.Errorf("invalid command template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// paramEnv returns the MOTF_PARAM_* environment variables of params
+func paramEnv(env []string, params map[string]string) []string {
+	if len(env) == 0 {
+		env = os.Environ()
+	}
+	env = append([]string{}, env...)
+	for _, name := range sortedParamNames(params) {
+		env = append(env, EnvParamPrefix+strings.ToUpper(name)+"="+params[name])
+	}
+	return env
+}
+
+// sortedParamNames returns the keys of a params map in order
+func sortedParamNames[V any](params map[string]V) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,177 @@
+package tasks
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseParams(t *testing.T) {
+	params, err := ParseParams([]string{"level=minor", "message=a=b", "empty="})
+	if err != nil {
+		t.Fatalf("ParseParams() returned error: %v", err)
+	}
+	if params["level"] != "minor" || params["message"] != "a=b" || params["empty"] != "" {
+		t.Errorf("unexpected params: %v", params)
+	}
+
+	if _, err := ParseParams([]string{"level"}); err == nil || !strings.Contains(err.Error(), "invalid --param 'level': must be name=value") {
+		t.Errorf("expected a format error, got %v", err)
+	}
+}
+
+func TestRunner_Params(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"bump": {
+			DependsOn: []string{"check"},
+			Command:   "echo bump {{ .Params.level }} $MOTF_PARAM_LEVEL $MOTF_PARAM_DRY_RUN",
+			Params: map[string]*ParamConfig{
+				"level":   {Default: "patch", Allowed: []string{"patch", "minor", "major"}},
+				"dry_run": {Type: "bool", Default: "false"},
+			},
+		},
+		"check": {
+			Command: "echo check {{ .Params.count }}",
+			Params:  map[string]*ParamConfig{"count": {Type: "int", Required: true}},
+		},
+	}, nil)
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		want    string
+		wantErr string
+	}{
+		{name: "defaults", params: map[string]string{"count": "2"}, want: "check 2\nbump patch patch false\n"},
+		{name: "values", params: map[string]string{"count": "1", "level": "major", "dry_run": "true"}, want: "check 1\nbump major major true\n"},
+		{name: "missing required", params: nil, wantErr: "task 'check' requires param 'count' (--param count=<value>)"},
+		{name: "not allowed", params: map[string]string{"count": "1", "level": "huge"}, wantErr: "invalid param 'level' of task 'bump': 'huge' must be one of patch, minor, major"},
+		{name: "wrong type", params: map[string]string{"count": "many"}, wantErr: "invalid param 'count' of task 'check': 'many' is not an int"},
+		{name: "unknown", params: map[string]string{"count": "1", "force": "true"}, wantErr: "unknown param 'force' for task 'bump'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := r.WithParams(tt.params)
+			if err := runner.CheckParams("bump"); tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("CheckParams() = %v, want %q", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("CheckParams() returned error: %v", err)
+			}
+
+			var out bytes.Buffer
+			if err := runner.RunWithOutput("bump", t.TempDir(), &out, &out); err != nil {
+				t.Fatalf("RunWithOutput() returned error: %v\n%s", err, out.String())
+			}
+			var got strings.Builder
+			for _, line := range strings.SplitAfter(out.String(), "\n") {
+				if !strings.HasPrefix(line, "Running") && !strings.HasPrefix(line, "$") {
+					got.WriteString(line)
+				}
+			}
+			if got.String() != tt.want {
+				t.Errorf("output = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunner_ParamsOfSkippedDependencies(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"release": {DependsOn: []string{"publish"}, Command: "echo release"},
+		"publish": {
+			Command: "echo publish {{ .Params.registry }}",
+			Params:  map[string]*ParamConfig{"registry": {Required: true}},
+			When:    &WhenConfig{Types: []string{"base"}},
+		},
+	}, nil)
+
+	// Before there is a module, publish may not run, so registry is not required
+	if err := r.CheckParams("release"); err != nil {
+		t.Fatalf("CheckParams() returned error: %v", err)
+	}
+	if _, err := r.Fingerprint("release"); err != nil {
+		t.Fatalf("Fingerprint() returned error: %v", err)
+	}
+
+	var out bytes.Buffer
+	component := r.WithTarget(Target{Dir: t.TempDir(), Path: "components/app", Type: "component"})
+	if err := component.RunWithOutput("release", t.TempDir(), &out, &out); err != nil {
+		t.Fatalf("RunWithOutput() on a component returned error: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Skipped: task 'publish' does not apply") {
+		t.Errorf("expected publish to be skipped, got: %s", out.String())
+	}
+
+	// A value for the params of a skipped dependency is not unknown
+	if err := component.WithParams(map[string]string{"registry": "acme"}).RunWithOutput("release", t.TempDir(), &out, &out); err != nil {
+		t.Errorf("RunWithOutput() with the param of a skipped dependency returned error: %v", err)
+	}
+
+	base := r.WithTarget(Target{Dir: t.TempDir(), Path: "bases/app", Type: "base"})
+	err := base.RunWithOutput("release", t.TempDir(), &out, &out)
+	if err == nil || err.Error() != "task 'publish' requires param 'registry' (--param registry=<value>)" {
+		t.Errorf("RunWithOutput() on a base = %v, want the missing param", err)
+	}
+}
+
+func TestRunner_ParamsLiteralBraces(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"render": {
+			Steps: []Step{
+				{Command: "echo '{{ .name }}' $MOTF_PARAM_LEVEL"},
+				{Command: `echo {{ .Params.level }} '{{ "{{" }} .name }}'`},
+			},
+			Params: map[string]*ParamConfig{"level": {Default: "patch"}},
+		},
+	}, nil)
+	if err := Validate(r.Tasks); err != nil {
+		t.Fatalf("Validate() returned error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := r.RunWithOutput("render", t.TempDir(), &out, &out); err != nil {
+		t.Fatalf("RunWithOutput() returned error: %v\n%s", err, out.String())
+	}
+	for _, want := range []string{"\n{{ .name }} patch\n", "\npatch {{ .name }}\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got: %s", want, out.String())
+		}
+	}
+}
+
+func TestValidate_Params(t *testing.T) {
+	tests := []struct {
+		name  string
+		param *ParamConfig
+		cmd   string
+		want  string
+	}{
+		{"unknown type", &ParamConfig{Type: "float"}, "echo", "invalid type 'float' of param 'level' of task 'bump': must be bool, int, string"},
+		{"default not allowed", &ParamConfig{Default: "huge", Allowed: []string{"patch"}}, "echo", "invalid default of param 'level' of task 'bump': 'huge' must be one of patch"},
+		{"allowed of wrong type", &ParamConfig{Type: "int", Allowed: []string{"1", "two"}}, "echo", "invalid allowed value of param 'level' of task 'bump': 'two' is not an int"},
+		{"undeclared param in template", &ParamConfig{}, "echo {{ .Params.lvl }}", "task 'bump': invalid command template"},
+		{"broken template", &ParamConfig{}, "echo {{ .Params.level", "task 'bump': invalid command template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(map[string]*TaskConfig{"bump": {Command: tt.cmd, Params: map[string]*ParamConfig{"level": tt.param}}})
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := Validate(map[string]*TaskConfig{"bump": {Command: "echo", Params: map[string]*ParamConfig{"dry-run": {}}}}); err == nil || !strings.Contains(err.Error(), "invalid param name 'dry-run'") {
+		t.Errorf("expected an invalid name error, got %v", err)
+	}
+
+	err := Validate(map[string]*TaskConfig{"bump": {Command: "echo", Params: map[string]*ParamConfig{"level": {}, "Level": {}}}})
+	if err == nil || !strings.Contains(err.Error(), "params 'Level' and 'level' of task 'bump' both set MOTF_PARAM_LEVEL") {
+		t.Errorf("expected a param env collision error, got %v", err)
+	}
+}
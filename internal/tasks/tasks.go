@@ -1,18 +1,30 @@
 package tasks
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
 )
 
 // TaskConfig represents a custom task definition
 type TaskConfig struct {
-	Description string `yaml:"description"`
-	Shell       string `yaml:"shell"`
-	Command     string `yaml:"command"`
+	Description string                  `yaml:"description"`
+	Shell       string                  `yaml:"shell"`
+	Command     string                  `yaml:"command"`
+	DependsOn   []string                `yaml:"depends_on"` // tasks or built-ins that run first
+	Steps       []Step                  `yaml:"steps"`      // ordered commands or task references, instead of command
+	Params      map[string]*ParamConfig `yaml:"params"`     // named inputs, set with --param
+	When        *WhenConfig             `yaml:"when"`       // modules the task applies to; all when nil
+	Inputs      []string                `yaml:"inputs"`     // module files the result depends on, for the cache; all when empty
+	Outputs     []string                `yaml:"outputs"`    // files the task generates, restored from the cache
+	Timeout     *time.Duration          `yaml:"timeout"`    // overrides the execution timeout for this task
+	Retries     *int                    `yaml:"retries"`    // overrides the execution retries for this task
 }
 
 // ShellConfig defines how to invoke a shell
@@ -57,6 +69,14 @@ func GetShellArgs(shell, script string) (binary string, args []string, err error
 type Runner struct {
 	Tasks map[string]*TaskConfig
 	Env   []string // Environment variables for task execution (includes MOTF_* built-ins)
+
+	ctx        context.Context
+	policy     process.Policy
+	taskPolicy func(*TaskConfig) process.Policy
+	builtins   map[string]Builtin
+	params     map[string]string
+	target     *Target
+	anyTarget  bool // resolve what runs on every target: skip dependencies and steps with when conditions
 }
 
 // NewRunner creates a new task runner with the given task definitions
@@ -67,6 +87,54 @@ func NewRunner(tasks map[string]*TaskConfig, env []string) *Runner {
 	return &Runner{Tasks: tasks, Env: env}
 }
 
+// WithContext returns a copy of r whose tasks are interrupted with SIGINT
+// when ctx is done
+func (r *Runner) WithContext(ctx context.Context) *Runner {
+	copied := *r
+	copied.ctx = ctx
+	return &copied
+}
+
+// WithPolicy returns a copy of r that runs tasks with the timeout and retries
+// of policy
+func (r *Runner) WithPolicy(policy process.Policy) *Runner {
+	copied := *r
+	copied.policy = policy
+	return &copied
+}
+
+// WithTaskPolicy returns a copy of r that runs each task, including the
+// tasks it depends on, with the timeout and retries policy returns for it
+func (r *Runner) WithTaskPolicy(policy func(*TaskConfig) process.Policy) *Runner {
+	copied := *r
+	copied.taskPolicy = policy
+	return &copied
+}
+
+// WithBuiltins returns a copy of r whose tasks can depend on builtins,
+// keyed by the names in BuiltinNames
+func (r *Runner) WithBuiltins(builtins map[string]Builtin) *Runner {
+	copied := *r
+	copied.builtins = builtins
+	return &copied
+}
+
+// WithTarget returns a copy of r that skips the dependencies and steps of a
+// task whose when conditions exclude target
+func (r *Runner) WithTarget(target Target) *Runner {
+	copied := *r
+	copied.target = &target
+	return &copied
+}
+
+// context returns the context tasks run with
+func (r *Runner) context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
 // GetTask returns the task config for the given name, or nil if not found
 func (r *Runner) GetTask(name string) *TaskConfig {
 	return r.Tasks[name]
@@ -86,34 +154,111 @@ func (r *Runner) Run(taskName, workDir string) error {
 	return r.RunWithOutput(taskName, workDir, os.Stdout, os.Stderr)
 }
 
-// RunWithOutput executes a task with custom output writers
+// RunWithOutput executes a task with custom output writers. The tasks and
+// built-ins it depends on run first, each once.
 func (r *Runner) RunWithOutput(taskName, workDir string, stdout, stderr io.Writer) error {
-	task := r.GetTask(taskName)
-	if task == nil {
-		return fmt.Errorf("task '%s' not found", taskName)
+	actions, err := r.resolve(taskName)
+	if err != nil {
+		return err
+	}
+	params, err := r.taskParams(taskName, actions)
+	if err != nil {
+		return err
 	}
 
-	if task.Command == "" {
-		return fmt.Errorf("task '%s' has no command defined", taskName)
+	for _, a := range actions {
+		if err := r.runAction(a, params[a.task], workDir, stdout, stderr); err != nil {
+			if a.task != taskName {
+				return fmt.Errorf("task '%s': %w", a.task, err)
+			}
+			return err
+		}
 	}
+	return nil
+}
 
-	binary, args, err := GetShellArgs(task.Shell, task.Command)
+// Fingerprint describes everything that runs for taskName with the params of
+// r: the built-ins, and the shells and rendered commands of the tasks. Cache
+// keys of task results include it.
+func (r *Runner) Fingerprint(taskName string) ([]string, error) {
+	actions, err := r.resolve(taskName)
+	if err != nil {
+		return nil, err
+	}
+	params, err := r.taskParams(taskName, actions)
 	if err != nil {
-		return fmt.Errorf("task '%s': %w", taskName, err)
+		return nil, err
 	}
 
-	_, _ = fmt.Fprintf(stdout, "Running task '%s' in %s\n", taskName, workDir)
-	_, _ = fmt.Fprintf(stdout, "$ %s\n", task.Command)
+	var fingerprint []string
+	for _, a := range actions {
+		if a.skip != "" {
+			fingerprint = append(fingerprint, "skipped "+a.task)
+			continue
+		}
+		if a.builtin != nil || r.GetTask(a.task) == nil {
+			fingerprint = append(fingerprint, "builtin "+a.task)
+			continue
+		}
+		command := a.command
+		if len(r.GetTask(a.task).Params) > 0 {
+			if command, err = renderCommand(command, params[a.task]); err != nil {
+				return nil, err
+			}
+		}
+		fingerprint = append(fingerprint, "task "+a.task, a.shell, command)
+		for _, name := range sortedParamNames(params[a.task]) {
+			fingerprint = append(fingerprint, EnvParamPrefix+strings.ToUpper(name)+"="+params[a.task][name])
+		}
+	}
+	return fingerprint, nil
+}
 
-	cmd := exec.Command(binary, args...) //nolint:gosec // binary and args are from user-defined task configuration
-	cmd.Dir = workDir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
+// runAction runs a shell command or built-in of a task. Commands of tasks
+// with params are rendered as templates and get the params as environment
+// variables.
+func (r *Runner) runAction(a action, params map[string]string, workDir string, stdout, stderr io.Writer) error {
+	if a.skip != "" {
+		_, _ = fmt.Fprintf(stdout, "Skipped: task '%s' does not apply: %s\n", a.task, a.skip)
+		return nil
+	}
+	if a.builtin != nil {
+		_, _ = fmt.Fprintf(stdout, "Running %s in %s\n", a.task, workDir)
+		return a.builtin(workDir, stdout, stderr)
+	}
+
+	command, env := a.command, r.Env
+	if len(r.GetTask(a.task).Params) > 0 {
+		var err error
+		if command, err = renderCommand(command, params); err != nil {
+			return err
+		}
+		env = paramEnv(env, params)
+	}
+	binary, args, err := GetShellArgs(a.shell, command)
+	if err != nil {
+		return err
+	}
+
+	if a.first {
+		_, _ = fmt.Fprintf(stdout, "Running task '%s' in %s\n", a.task, workDir)
+	}
+	_, _ = fmt.Fprintf(stdout, "$ %s\n", command)
 
-	// Set environment if provided (includes MOTF_* built-in variables)
-	if len(r.Env) > 0 {
-		cmd.Env = r.Env
+	policy := r.policy
+	if r.taskPolicy != nil {
+		policy = r.taskPolicy(r.GetTask(a.task))
 	}
+	return policy.Run(r.context(), stderr, func(ctx context.Context) *exec.Cmd {
+		cmd := process.Command(ctx, binary, args...) //nolint:gosec // binary and args are from user-defined task configuration
+		cmd.Dir = workDir
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
 
-	return cmd.Run()
+		// Set environment if provided (includes MOTF_* built-in and param variables)
+		if len(env) > 0 {
+			cmd.Env = env
+		}
+		return cmd
+	})
 }
@@ -1,7 +1,15 @@
 package tasks
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
 )
 
 func TestGetShellArgs(t *testing.T) {
@@ -218,3 +226,39 @@ func TestRunner_Run_Errors(t *testing.T) {
 		}
 	})
 }
+
+func TestRunner_WithContext(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"hello": {Command: "echo hello"},
+	}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	if err := r.WithContext(ctx).RunWithOutput("hello", t.TempDir(), &out, &out); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled from a cancelled runner, got %v", err)
+	}
+	if err := r.RunWithOutput("hello", t.TempDir(), &out, &out); err != nil {
+		t.Errorf("WithContext must not change the original runner, got %v", err)
+	}
+}
+
+func TestRunner_WithPolicy(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"flaky": {Command: "if [ ! -f attempted ]; then touch attempted; echo 'TLS handshake timeout' >&2; exit 1; fi; echo ok"},
+	}, nil)
+	policy := process.Policy{Retries: 1, Backoff: time.Millisecond, RetryOn: []*regexp.Regexp{regexp.MustCompile("TLS handshake timeout")}}
+
+	var out bytes.Buffer
+	if err := r.WithPolicy(policy).RunWithOutput("flaky", t.TempDir(), &out, &out); err != nil {
+		t.Fatalf("expected the retried task to succeed, got %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Retrying in 1ms (attempt 2 of 2)") || !strings.HasSuffix(out.String(), "ok\n") {
+		t.Errorf("expected a retry followed by success, got: %s", out.String())
+	}
+
+	if err := r.RunWithOutput("flaky", t.TempDir(), &out, &out); err == nil {
+		t.Error("WithPolicy must not change the original runner")
+	}
+}
@@ -0,0 +1,102 @@
+package tasks
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+)
+
+// Scopes a task can be limited to with when.scope
+const (
+	ScopeModule  = "module"
+	ScopeExample = "example"
+)
+
+// WhenConfig limits the modules a task applies to. All conditions that are
+// set must hold.
+type WhenConfig struct {
+	Types []string `yaml:"types"` // module types, any of
+	Tags  []string `yaml:"tags"`  // module tags, all of
+	Paths []string `yaml:"paths"` // gitignore-style patterns on the module path, relative to the root
+	Files []string `yaml:"files"` // globs relative to the module, each matching at least one file
+	Scope string   `yaml:"scope"` // module or example; both when empty
+}
+
+// Target describes the module or example a task would run on
+type Target struct {
+	Dir     string   // absolute path the task runs in
+	Path    string   // module path relative to the root, slash-separated
+	Type    string   // module type
+	Tags    []string // module tags from .motf.module.yml
+	Example bool     // Dir is an example of the module
+}
+
+// SkipReason returns why the task does not apply to target, or "" if it
+// applies. A task without when conditions applies everywhere.
+func (t *TaskConfig) SkipReason(target Target) string {
+	w := t.When
+	if w == nil {
+		return ""
+	}
+
+	switch {
+	case w.Scope == ScopeModule && target.Example:
+		return "it runs on modules only, not examples"
+	case w.Scope == ScopeExample && !target.Example:
+		return "it runs on examples only"
+	}
+	if len(w.Types) > 0 && !contains(w.Types, target.Type) {
+		return fmt.Sprintf("module type '%s' is not %s", target.Type, strings.Join(w.Types, " or "))
+	}
+	for _, tag := range w.Tags {
+		if !contains(target.Tags, tag) {
+			return fmt.Sprintf("module is not tagged '%s'", tag)
+		}
+	}
+	if len(w.Paths) > 0 && !finder.MatchesPath(target.Path, w.Paths) {
+		return fmt.Sprintf("path '%s' does not match %s", target.Path, strings.Join(w.Paths, ", "))
+	}
+	for _, pattern := range w.Files {
+		if matches, _ := filepath.Glob(filepath.Join(target.Dir, filepath.FromSlash(pattern))); len(matches) == 0 {
+			return fmt.Sprintf("no file matches '%s'", pattern)
+		}
+	}
+	return ""
+}
+
+// validateWhen checks the when conditions of a task
+func validateWhen(name string, w *WhenConfig) error {
+	if w == nil {
+		return nil
+	}
+	if w.Scope != "" && w.Scope != ScopeModule && w.Scope != ScopeExample {
+		return fmt.Errorf("invalid when scope '%s' of task '%s': must be '%s' or '%s'", w.Scope, name, ScopeModule, ScopeExample)
+	}
+	return validatePatterns("when", name, append(append([]string{}, w.Paths...), w.Files...))
+}
+
+// validatePatterns checks the glob patterns of a task setting
+func validatePatterns(setting, name string, patterns []string) error {
+	for _, pattern := range patterns {
+		glob := strings.Trim(strings.TrimPrefix(pattern, "!"), "/")
+		if glob == "" {
+			return fmt.Errorf("invalid %s pattern '%s' of task '%s': pattern is empty", setting, pattern, name)
+		}
+		if _, err := filepath.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid %s pattern '%s' of task '%s': %w", setting, pattern, name, err)
+		}
+	}
+	return nil
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package tasks
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTaskConfig_SkipReason(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "tests"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tests", "storage_test.go"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	module := Target{Dir: dir, Path: "components/azurerm/storage", Type: "component", Tags: []string{"storage", "azure"}}
+	example := module
+	example.Example = true
+
+	tests := []struct {
+		name   string
+		when   *WhenConfig
+		target Target
+		want   string
+	}{
+		{"no conditions", nil, module, ""},
+		{"all conditions hold", &WhenConfig{Types: []string{"base", "component"}, Tags: []string{"azure"}, Paths: []string{"components/azurerm/**"}, Files: []string{"tests/*_test.go"}, Scope: ScopeModule}, module, ""},
+		{"type", &WhenConfig{Types: []string{"base", "project"}}, module, "module type 'component' is not base or project"},
+		{"tag", &WhenConfig{Tags: []string{"azure", "network"}}, module, "module is not tagged 'network'"},
+		{"path", &WhenConfig{Paths: []string{"components/aws/**"}}, module, "path 'components/azurerm/storage' does not match components/aws/**"},
+		{"file", &WhenConfig{Files: []string{"tests/*_test.go", ".tflint.hcl"}}, module, "no file matches '.tflint.hcl'"},
+		{"module only", &WhenConfig{Scope: ScopeModule}, example, "it runs on modules only, not examples"},
+		{"example only", &WhenConfig{Scope: ScopeExample}, module, "it runs on examples only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &TaskConfig{Command: "true", When: tt.when}
+			if got := task.SkipReason(tt.target); got != tt.want {
+				t.Errorf("SkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunner_SkipsExcludedDependencies(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"ci":     {DependsOn: []string{"tflint", "docs"}, Command: "echo ci"},
+		"tflint": {DependsOn: []string{"setup"}, When: &WhenConfig{Types: []string{"component"}}, Command: "echo tflint"},
+		"docs":   {When: &WhenConfig{Types: []string{"base"}}, Command: "echo docs"},
+		"setup":  {Command: "echo setup"},
+	}, nil).WithTarget(Target{Dir: t.TempDir(), Path: "bases/app", Type: "base"})
+
+	var out bytes.Buffer
+	if err := r.RunWithOutput("ci", t.TempDir(), &out, &out); err != nil {
+		t.Fatalf("RunWithOutput() returned error: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Skipped: task 'tflint' does not apply: module type 'base' is not component\n") {
+		t.Errorf("expected tflint to be reported as skipped, got: %s", out.String())
+	}
+	for _, task := range []string{"tflint", "setup"} {
+		if strings.Contains(out.String(), "Running task '"+task+"'") {
+			t.Errorf("expected %s not to run, got: %s", task, out.String())
+		}
+	}
+	for _, task := range []string{"docs", "ci"} {
+		if !strings.Contains(out.String(), "Running task '"+task+"'") {
+			t.Errorf("expected %s to run, got: %s", task, out.String())
+		}
+	}
+}
+
+func TestValidate_When(t *testing.T) {
+	tests := []struct {
+		name string
+		when *WhenConfig
+		want string
+	}{
+		{"scope", &WhenConfig{Scope: "examples"}, "invalid when scope 'examples' of task 'lint': must be 'module' or 'example'"},
+		{"path pattern", &WhenConfig{Paths: []string{"components/[a-"}}, "invalid when pattern 'components/[a-' of task 'lint'"},
+		{"empty file pattern", &WhenConfig{Files: []string{"/"}}, "invalid when pattern '/' of task 'lint': pattern is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(map[string]*TaskConfig{"lint": {Command: "tflint", When: tt.when}})
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
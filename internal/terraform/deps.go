@@ -0,0 +1,54 @@
+package terraform
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+)
+
+// IsLocalSource reports whether a module source refers to a local directory.
+// Terraform treats sources starting with "./" or "../" as local paths.
+func IsLocalSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") ||
+		strings.HasPrefix(source, ".\\") || strings.HasPrefix(source, "..\\")
+}
+
+// LoadLocalModuleCalls parses the module at modulePath and returns the absolute
+// paths of every local module it calls (sources starting with "./" or "../").
+// Registry and remote sources are ignored. The result is sorted and deduplicated.
+//
+// Parsing is best-effort: if some files contain errors, the calls that could be
+// parsed are returned together with the parse error.
+func LoadLocalModuleCalls(modulePath string) ([]string, error) {
+	module, diags := tfconfig.LoadModule(modulePath)
+	paths := localModuleCallPaths(module, modulePath)
+	if diags.HasErrors() {
+		return paths, diags.Err()
+	}
+
+	return paths, nil
+}
+
+// localModuleCallPaths resolves the local module calls of module relative to modulePath
+func localModuleCallPaths(module *tfconfig.Module, modulePath string) []string {
+	seen := make(map[string]bool)
+	var paths []string
+
+	for _, call := range module.ModuleCalls {
+		if !IsLocalSource(call.Source) {
+			continue
+		}
+
+		target := filepath.Clean(filepath.Join(modulePath, filepath.FromSlash(call.Source)))
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+		paths = append(paths, target)
+	}
+
+	sort.Strings(paths)
+	return paths
+}
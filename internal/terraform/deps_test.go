@@ -0,0 +1,87 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIsLocalSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"./modules/network", true},
+		{"../../components/azurerm/storage-account", true},
+		{"..\\components\\key-vault", true},
+		{"Azure/naming/azurerm", false},
+		{"git::https://example.com/vpc.git", false},
+		{"registry.terraform.io/hashicorp/consul/aws", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsLocalSource(tt.source); got != tt.want {
+			t.Errorf("IsLocalSource(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestLoadLocalModuleCalls(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "projects", "prod")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := `
+module "storage" {
+  source = "../../components/storage"
+}
+
+module "storage_again" {
+  source = "../../components/storage"
+}
+
+module "base" {
+  source = "../../bases/app"
+}
+
+module "naming" {
+  source  = "Azure/naming/azurerm"
+  version = "0.4.3"
+}
+`
+	if err := os.WriteFile(filepath.Join(projectDir, "main.tf"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadLocalModuleCalls(projectDir)
+	if err != nil {
+		t.Fatalf("LoadLocalModuleCalls returned error: %v", err)
+	}
+
+	want := []string{
+		filepath.Join(tmpDir, "bases", "app"),
+		filepath.Join(tmpDir, "components", "storage"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadLocalModuleCalls() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadLocalModuleCalls_NoCalls(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.tf"), []byte(`variable "x" {}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadLocalModuleCalls(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadLocalModuleCalls returned error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no module calls, got %v", got)
+	}
+}
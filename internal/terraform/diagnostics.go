@@ -0,0 +1,134 @@
+package terraform
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
+)
+
+// Diagnostic is an error or warning reported by terraform/tofu
+type Diagnostic struct {
+	Severity string           `json:"severity"`
+	Summary  string           `json:"summary"`
+	Detail   string           `json:"detail"`
+	Range    *DiagnosticRange `json:"range,omitempty"`
+}
+
+// DiagnosticRange is the source location of a diagnostic
+type DiagnosticRange struct {
+	Filename string             `json:"filename"`
+	Start    DiagnosticPosition `json:"start"`
+}
+
+// DiagnosticPosition is a line and column in a source file
+type DiagnosticPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// ValidateResult is the subset of `validate -json` output used by motf
+type ValidateResult struct {
+	Valid        bool         `json:"valid"`
+	ErrorCount   int          `json:"error_count"`
+	WarningCount int          `json:"warning_count"`
+	Diagnostics  []Diagnostic `json:"diagnostics"`
+}
+
+// ParseValidateJSON parses the output of `validate -json`
+func ParseValidateJSON(data []byte) (*ValidateResult, error) {
+	var result ValidateResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse validate JSON: %w", err)
+	}
+	return &result, nil
+}
+
+// RunValidateJSON executes terraform/tofu validate -json in dir and parses its
+// diagnostics. When the configuration is invalid, the parsed result is returned
+// together with the error from the binary.
+func (r *Runner) RunValidateJSON(dir string, stdout, stderr io.Writer, extraArgs ...string) (*ValidateResult, error) {
+	var result *ValidateResult
+	err := r.withHooks("validate", dir, stdout, stderr, func() error {
+		args := append([]string{"validate", "-json"}, extraArgs...)
+		var out bytes.Buffer
+		_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
+		runErr := r.config.Execution.Policy("validate").Run(r.context(), stderr, func(ctx context.Context) *exec.Cmd {
+			// Only the JSON of the last attempt is parsed
+			out.Reset()
+			cmd := process.Command(ctx, r.config.Binary, args...) //nolint:gosec // Binary is validated to be terraform or tofu
+			cmd.Dir = dir
+			cmd.Stdout = &out
+			cmd.Stderr = stderr
+			return cmd
+		})
+
+		var err error
+		result, err = ParseValidateJSON(out.Bytes())
+		if err != nil && runErr == nil {
+			return err
+		}
+		return runErr
+	})
+	return result, err
+}
+
+// FmtDiff is a file reported by `fmt -check -diff` as not formatted, with the
+// line of the original file where each changed hunk starts
+type FmtDiff struct {
+	File  string
+	Lines []int
+}
+
+// ParseFmtDiff parses the unified diffs printed by `fmt -check -diff`.
+// File names are relative to the directory fmt ran in.
+func ParseFmtDiff(output []byte) []FmtDiff {
+	var diffs []FmtDiff
+	var current *FmtDiff
+	hunkLine := 0 // original line of the current hunk; 0 once its first change was recorded
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			file := strings.TrimPrefix(line, "--- ")
+			file = strings.TrimPrefix(strings.TrimPrefix(file, "old/"), "a/")
+			diffs = append(diffs, FmtDiff{File: file})
+			current = &diffs[len(diffs)-1]
+			hunkLine = 0
+		case strings.HasPrefix(line, "+++ "):
+			// New file name; the original name is recorded from the --- line
+		case strings.HasPrefix(line, "@@ "):
+			hunkLine = parseHunkStart(line)
+		case current != nil && hunkLine > 0 && strings.HasPrefix(line, " "):
+			hunkLine++
+		case current != nil && hunkLine > 0 && (strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+")):
+			current.Lines = append(current.Lines, hunkLine)
+			hunkLine = 0
+		}
+	}
+	return diffs
+}
+
+// parseHunkStart returns the original start line of a hunk header such as
+// "@@ -3,7 +3,7 @@", or 0 if it can't be parsed
+func parseHunkStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 2 || !strings.HasPrefix(fields[1], "-") {
+		return 0
+	}
+	start, _, _ := strings.Cut(strings.TrimPrefix(fields[1], "-"), ",")
+	line, err := strconv.Atoi(start)
+	if err != nil || line < 1 {
+		return 1
+	}
+	return line
+}
@@ -0,0 +1,120 @@
+package terraform
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+const testValidateJSON = `{
+  "format_version": "1.0",
+  "valid": false,
+  "error_count": 1,
+  "warning_count": 1,
+  "diagnostics": [
+    {
+      "severity": "error",
+      "summary": "Unsupported argument",
+      "detail": "An argument named \"foo\" is not expected here.",
+      "range": {"filename": "main.tf", "start": {"line": 3, "column": 3, "byte": 40}, "end": {"line": 3, "column": 6, "byte": 43}}
+    },
+    {
+      "severity": "warning",
+      "summary": "Deprecated attribute",
+      "detail": ""
+    }
+  ]
+}`
+
+func TestParseValidateJSON(t *testing.T) {
+	result, err := ParseValidateJSON([]byte(testValidateJSON))
+	if err != nil {
+		t.Fatalf("ParseValidateJSON returned error: %v", err)
+	}
+	if result.Valid || result.ErrorCount != 1 || result.WarningCount != 1 || len(result.Diagnostics) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	d := result.Diagnostics[0]
+	if d.Severity != "error" || d.Summary != "Unsupported argument" || d.Range == nil {
+		t.Fatalf("unexpected diagnostic: %+v", d)
+	}
+	if d.Range.Filename != "main.tf" || d.Range.Start != (DiagnosticPosition{Line: 3, Column: 3}) {
+		t.Errorf("unexpected range: %+v", d.Range)
+	}
+	if result.Diagnostics[1].Range != nil {
+		t.Error("expected diagnostic without range")
+	}
+
+	if _, err := ParseValidateJSON([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestRunner_RunValidateJSON(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "fake-terraform")
+	script := "#!/bin/sh\ncat <<'EOF'\n" + testValidateJSON + "\nEOF\nexit 1\n"
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewRunner(&config.Config{Binary: binary})
+
+	var out bytes.Buffer
+	result, err := runner.RunValidateJSON(t.TempDir(), &out, &out)
+	if err == nil {
+		t.Error("expected error from failing validate")
+	}
+	if result == nil || len(result.Diagnostics) != 2 {
+		t.Fatalf("expected parsed diagnostics, got %+v", result)
+	}
+	if !strings.Contains(out.String(), "validate -json") || strings.Contains(out.String(), "diagnostics") {
+		t.Errorf("expected only the command line in output, got: %s", out.String())
+	}
+
+	// Output that isn't JSON returns the binary's error
+	runner = NewRunner(&config.Config{Binary: writeFakeBinary(t, "1")})
+	if result, err := runner.RunValidateJSON(t.TempDir(), &out, &out); result != nil || err == nil {
+		t.Errorf("expected only an error, got %+v, %v", result, err)
+	}
+}
+
+func TestParseFmtDiff(t *testing.T) {
+	output := `main.tf
+--- old/main.tf
++++ new/main.tf
+@@ -1,5 +1,5 @@
+ resource "null_resource" "a" {
+-  triggers={
++  triggers = {
+     a = 1
+   }
+ }
+@@ -10,3 +10,3 @@
+-variable "x" {}
++variable "x" {
+ }
+modules/sub/variables.tf
+--- old/modules/sub/variables.tf
++++ new/modules/sub/variables.tf
+@@ -4,2 +4,2 @@
+-  type=string
++  type = string
+`
+
+	want := []FmtDiff{
+		{File: "main.tf", Lines: []int{2, 10}},
+		{File: "modules/sub/variables.tf", Lines: []int{4}},
+	}
+	if got := ParseFmtDiff([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFmtDiff() = %+v, want %+v", got, want)
+	}
+
+	if got := ParseFmtDiff([]byte("")); got != nil {
+		t.Errorf("expected no diffs for empty output, got %+v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// Drift check results
+const (
+	DriftClean   = "clean"   // Infrastructure matches the configuration and state
+	DriftDrifted = "drifted" // Plan reported changes
+	DriftErrored = "errored" // Plan failed, so drift is unknown
+)
+
+// RunDriftCheckWithOutput runs terraform/tofu plan -detailed-exitcode in dir and
+// classifies the result by exit code: 0 is clean, 2 is drifted, anything else
+// is errored (returned together with the error). When refreshOnly is set, the
+// plan only compares state with real infrastructure (-refresh-only).
+func (r *Runner) RunDriftCheckWithOutput(dir string, refreshOnly bool, stdout, stderr io.Writer, extraArgs ...string) (string, error) {
+	args := []string{"plan", "-detailed-exitcode", "-input=false"}
+	if refreshOnly {
+		args = append(args, "-refresh-only")
+	}
+	args = append(args, extraArgs...)
+
+	// Drift is a successful check, so the post_plan hooks run on drifted
+	// projects as well
+	result := DriftErrored
+	err := r.withHooks("plan", dir, stdout, stderr, func() error {
+		_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
+		// Drift is a result, not a failure worth retrying
+		policy := r.config.Execution.Policy("plan")
+		policy.Accept = []int{2}
+		err := r.runWithPolicy(policy, dir, stdout, stderr, r.config.Binary, args...)
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			result = DriftClean
+		case errors.As(err, &exitErr) && exitErr.ExitCode() == 2:
+			result = DriftDrifted
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return DriftErrored, err
+	}
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package terraform
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// writeFakeBinary writes a shell script that prints its arguments and exits with exitCode
+func writeFakeBinary(t *testing.T, exitCode string) string {
+	t.Helper()
+	binary := filepath.Join(t.TempDir(), "fake-terraform")
+	script := "#!/bin/sh\necho \"args: $*\"\nexit " + exitCode + "\n"
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return binary
+}
+
+func TestRunner_RunDriftCheckWithOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		exitCode   string
+		wantStatus string
+		wantErr    bool
+	}{
+		{"clean", "0", DriftClean, false},
+		{"drifted", "2", DriftDrifted, false},
+		{"errored", "1", DriftErrored, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := NewRunner(&config.Config{Binary: writeFakeBinary(t, tt.exitCode)})
+
+			var out bytes.Buffer
+			status, err := runner.RunDriftCheckWithOutput(t.TempDir(), true, &out, &out)
+			if status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status, tt.wantStatus)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunner_RunDriftCheckWithOutput_Args(t *testing.T) {
+	runner := NewRunner(&config.Config{Binary: writeFakeBinary(t, "0")})
+
+	var out bytes.Buffer
+	if _, err := runner.RunDriftCheckWithOutput(t.TempDir(), true, &out, &out, "-lock=false"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "args: plan -detailed-exitcode -input=false -refresh-only -lock=false") {
+		t.Errorf("unexpected arguments in output: %s", out.String())
+	}
+
+	out.Reset()
+	if _, err := runner.RunDriftCheckWithOutput(t.TempDir(), false, &out, &out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "-refresh-only") {
+		t.Errorf("did not expect -refresh-only for a normal plan: %s", out.String())
+	}
+}
+
+func TestRunner_RunDriftCheckWithOutput_DriftNotRetried(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	binary := filepath.Join(dir, "fake-terraform")
+	script := "#!/bin/sh\necho run >> \"" + runs + "\"\necho 'Warning: Failed to install provider'\nexit 2\n"
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewRunner(&config.Config{Binary: binary, Execution: &config.ExecutionConfig{
+		Retries: 2,
+		Backoff: time.Millisecond,
+		RetryOn: []string{"Failed to install provider"},
+	}})
+
+	var out bytes.Buffer
+	status, err := runner.RunDriftCheckWithOutput(t.TempDir(), true, &out, &out)
+	if err != nil || status != DriftDrifted {
+		t.Fatalf("RunDriftCheckWithOutput() = %q, %v, want drifted", status, err)
+	}
+	if data, _ := os.ReadFile(runs); strings.Count(string(data), "\n") != 1 {
+		t.Errorf("expected drift not to be retried, ran %d times", strings.Count(string(data), "\n"))
+	}
+}
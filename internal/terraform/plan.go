@@ -0,0 +1,110 @@
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
+)
+
+// PlanSummary counts the resource changes in a saved plan
+type PlanSummary struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+	Replace int `json:"replace"`
+
+	// Addresses of resources that will be destroyed or replaced
+	Destroyed []string `json:"destroyed,omitempty"`
+	Replaced  []string `json:"replaced,omitempty"`
+}
+
+// HasChanges reports whether the plan changes any resource
+func (s PlanSummary) HasChanges() bool {
+	return s.Add+s.Change+s.Destroy+s.Replace > 0
+}
+
+// Plus returns the sum of two summaries (counts only)
+func (s PlanSummary) Plus(other PlanSummary) PlanSummary {
+	return PlanSummary{
+		Add:     s.Add + other.Add,
+		Change:  s.Change + other.Change,
+		Destroy: s.Destroy + other.Destroy,
+		Replace: s.Replace + other.Replace,
+	}
+}
+
+// planJSON is the subset of the `show -json` plan representation used for summaries
+type planJSON struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// ParsePlanSummary summarizes the resource changes in `show -json` plan output.
+// A delete combined with a create (in either order) counts as a replace;
+// no-op and read actions are ignored.
+func ParsePlanSummary(data []byte) (*PlanSummary, error) {
+	var plan planJSON
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan JSON: %w", err)
+	}
+
+	summary := &PlanSummary{}
+	for _, rc := range plan.ResourceChanges {
+		switch strings.Join(rc.Change.Actions, ",") {
+		case "create":
+			summary.Add++
+		case "update":
+			summary.Change++
+		case "delete":
+			summary.Destroy++
+			summary.Destroyed = append(summary.Destroyed, rc.Address)
+		case "delete,create", "create,delete":
+			summary.Replace++
+			summary.Replaced = append(summary.Replaced, rc.Address)
+		}
+	}
+
+	sort.Strings(summary.Destroyed)
+	sort.Strings(summary.Replaced)
+	return summary, nil
+}
+
+// RunShowJSON executes terraform/tofu show -json on a saved plan file in dir
+// and returns its stdout. Errors from the binary are written to stderr. It
+// runs with the timeout and retries of plan, which it is part of.
+func (r *Runner) RunShowJSON(dir, planFile string, stderr io.Writer) ([]byte, error) {
+	var stdout bytes.Buffer
+	err := r.config.Execution.Policy("plan").Run(r.context(), stderr, func(ctx context.Context) *exec.Cmd {
+		// Only the JSON of the last attempt is returned
+		stdout.Reset()
+		cmd := process.Command(ctx, r.config.Binary, "show", "-json", planFile) //nolint:gosec // Binary is validated to be terraform or tofu
+		cmd.Dir = dir
+		cmd.Stdout = &stdout
+		cmd.Stderr = stderr
+		return cmd
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s show -json %s failed: %w", r.config.Binary, planFile, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// SummarizePlan runs show -json on a saved plan file in dir and summarizes it
+func (r *Runner) SummarizePlan(dir, planFile string, stderr io.Writer) (*PlanSummary, error) {
+	data, err := r.RunShowJSON(dir, planFile, stderr)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePlanSummary(data)
+}
@@ -0,0 +1,141 @@
+package terraform
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+const testPlanJSON = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "azurerm_resource_group.this", "change": {"actions": ["no-op"]}},
+    {"address": "azurerm_storage_account.this", "change": {"actions": ["create"]}},
+    {"address": "azurerm_storage_container.logs", "change": {"actions": ["create"]}},
+    {"address": "azurerm_key_vault.this", "change": {"actions": ["update"]}},
+    {"address": "azurerm_storage_container.old", "change": {"actions": ["delete"]}},
+    {"address": "azurerm_role_assignment.b", "change": {"actions": ["delete", "create"]}},
+    {"address": "azurerm_role_assignment.a", "change": {"actions": ["create", "delete"]}},
+    {"address": "data.azurerm_client_config.current", "change": {"actions": ["read"]}}
+  ]
+}`
+
+func TestParsePlanSummary(t *testing.T) {
+	summary, err := ParsePlanSummary([]byte(testPlanJSON))
+	if err != nil {
+		t.Fatalf("ParsePlanSummary returned error: %v", err)
+	}
+
+	want := &PlanSummary{
+		Add:       2,
+		Change:    1,
+		Destroy:   1,
+		Replace:   2,
+		Destroyed: []string{"azurerm_storage_container.old"},
+		Replaced:  []string{"azurerm_role_assignment.a", "azurerm_role_assignment.b"},
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("ParsePlanSummary() = %+v, want %+v", summary, want)
+	}
+	if !summary.HasChanges() {
+		t.Error("HasChanges() should be true")
+	}
+}
+
+func TestParsePlanSummary_NoChanges(t *testing.T) {
+	summary, err := ParsePlanSummary([]byte(`{"format_version": "1.2"}`))
+	if err != nil {
+		t.Fatalf("ParsePlanSummary returned error: %v", err)
+	}
+	if summary.HasChanges() {
+		t.Errorf("expected no changes, got %+v", summary)
+	}
+}
+
+func TestParsePlanSummary_InvalidJSON(t *testing.T) {
+	if _, err := ParsePlanSummary([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestPlanSummary_Plus(t *testing.T) {
+	a := PlanSummary{Add: 1, Destroy: 2, Destroyed: []string{"x"}}
+	b := PlanSummary{Add: 3, Change: 1, Replace: 1}
+
+	got := a.Plus(b)
+	want := PlanSummary{Add: 4, Change: 1, Destroy: 2, Replace: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Plus() = %+v, want %+v", got, want)
+	}
+}
+
+// TestRunner_SummarizePlan uses a fake binary that prints plan JSON for "show -json"
+func TestRunner_SummarizePlan(t *testing.T) {
+	binDir := t.TempDir()
+	script := "#!/bin/sh\ncat \"$3\"\n"
+	binary := filepath.Join(binDir, "fake-terraform")
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	moduleDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(moduleDir, "tfplan"), []byte(testPlanJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(&config.Config{Binary: binary})
+	var stderr bytes.Buffer
+	summary, err := runner.SummarizePlan(moduleDir, "tfplan", &stderr)
+	if err != nil {
+		t.Fatalf("SummarizePlan returned error: %v (stderr: %s)", err, stderr.String())
+	}
+	if summary.Add != 2 || summary.Destroy != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+// TestRunner_SummarizePlan_Retried verifies show -json is retried like plan
+// and only the output of the last attempt is parsed
+func TestRunner_SummarizePlan_Retried(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	binDir := t.TempDir()
+	runs := filepath.Join(binDir, "runs")
+	script := "#!/bin/sh\necho run >> \"" + runs + "\"\n" +
+		"if [ \"$(wc -l < \"" + runs + "\")\" -le 1 ]; then\n  echo '{\"partial\":'\n  echo 'Error: TLS handshake timeout' >&2\n  exit 1\nfi\ncat \"$3\"\n"
+	binary := filepath.Join(binDir, "fake-terraform")
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	moduleDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(moduleDir, "tfplan"), []byte(testPlanJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(&config.Config{Binary: binary, Execution: &config.ExecutionConfig{
+		Retries: 1,
+		Backoff: time.Millisecond,
+		RetryOn: []string{"TLS handshake timeout"},
+	}})
+	var stderr bytes.Buffer
+	summary, err := runner.SummarizePlan(moduleDir, "tfplan", &stderr)
+	if err != nil {
+		t.Fatalf("SummarizePlan returned error: %v (stderr: %s)", err, stderr.String())
+	}
+	if summary.Add != 2 || summary.Destroy != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if !strings.Contains(stderr.String(), "Retrying in") {
+		t.Errorf("expected show to be retried, got: %s", stderr.String())
+	}
+}
@@ -1,6 +1,7 @@
 package terraform
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -8,13 +9,19 @@ import (
 	"strings"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/process"
 )
 
 // Runner executes terraform/tofu commands using configuration
 type Runner struct {
 	config *config.Config
+	ctx    context.Context
+	hooks  Hooks
 }
 
+// Hooks runs the hooks of stage ("pre" or "post") around command in dir
+type Hooks func(stage, command, dir string, stdout, stderr io.Writer) error
+
 // NewRunner creates a new Runner with the given configuration
 func NewRunner(cfg *config.Config) *Runner {
 	return &Runner{config: cfg}
@@ -25,6 +32,53 @@ func (r *Runner) Binary() string {
 	return r.config.Binary
 }
 
+// ForModule returns a Runner that applies the binary and test overrides from
+// a module's .motf.module.yml. It returns r itself when there are none.
+func (r *Runner) ForModule(mc *config.ModuleConfig) *Runner {
+	if !mc.HasOverrides() {
+		return r
+	}
+	return &Runner{config: r.config.ForModule(mc), ctx: r.ctx, hooks: r.hooks}
+}
+
+// WithContext returns a copy of r whose commands are interrupted with SIGINT
+// when ctx is done
+func (r *Runner) WithContext(ctx context.Context) *Runner {
+	copied := *r
+	copied.ctx = ctx
+	return &copied
+}
+
+// WithHooks returns a copy of r that runs hooks before and after each command
+func (r *Runner) WithHooks(hooks Hooks) *Runner {
+	copied := *r
+	copied.hooks = hooks
+	return &copied
+}
+
+// withHooks runs the pre hooks of command, then fn and, when fn succeeds, the
+// post hooks. A failing pre hook aborts the command.
+func (r *Runner) withHooks(command, dir string, stdout, stderr io.Writer, fn func() error) error {
+	if r.hooks == nil {
+		return fn()
+	}
+	if err := r.hooks("pre", command, dir, stdout, stderr); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return r.hooks("post", command, dir, stdout, stderr)
+}
+
+// context returns the context commands run with
+func (r *Runner) context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
 // RunInit executes terraform/tofu init in the specified directory
 func (r *Runner) RunInit(dir string, extraArgs ...string) error {
 	return r.RunInitWithOutput(dir, os.Stdout, os.Stderr, extraArgs...)
@@ -32,14 +86,11 @@ func (r *Runner) RunInit(dir string, extraArgs ...string) error {
 
 // RunInitWithOutput executes terraform/tofu init with custom output writers
 func (r *Runner) RunInitWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
-	args := append([]string{"init"}, extraArgs...)
-	cmd := exec.Command(r.config.Binary, args...) //nolint:gosec // Binary is validated to be terraform or tofu
-	cmd.Dir = dir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-
-	_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
-	return cmd.Run()
+	return r.withHooks("init", dir, stdout, stderr, func() error {
+		args := append([]string{"init"}, extraArgs...)
+		_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
+		return r.run("init", dir, stdout, stderr, r.config.Binary, args...)
+	})
 }
 
 // RunFmt executes terraform/tofu fmt in the specified directory
@@ -49,14 +100,11 @@ func (r *Runner) RunFmt(dir string, extraArgs ...string) error {
 
 // RunFmtWithOutput executes terraform/tofu fmt with custom output writers
 func (r *Runner) RunFmtWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
-	args := append([]string{"fmt"}, extraArgs...)
-	cmd := exec.Command(r.config.Binary, args...) //nolint:gosec // Binary is validated to be terraform or tofu
-	cmd.Dir = dir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-
-	_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
-	return cmd.Run()
+	return r.withHooks("fmt", dir, stdout, stderr, func() error {
+		args := append([]string{"fmt"}, extraArgs...)
+		_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
+		return r.run("fmt", dir, stdout, stderr, r.config.Binary, args...)
+	})
 }
 
 // RunValidate executes terraform/tofu validate in the specified directory
@@ -66,14 +114,11 @@ func (r *Runner) RunValidate(dir string, extraArgs ...string) error {
 
 // RunValidateWithOutput executes terraform/tofu validate with custom output writers
 func (r *Runner) RunValidateWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
-	args := append([]string{"validate"}, extraArgs...)
-	cmd := exec.Command(r.config.Binary, args...) //nolint:gosec // Binary is validated to be terraform or tofu
-	cmd.Dir = dir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-
-	_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
-	return cmd.Run()
+	return r.withHooks("validate", dir, stdout, stderr, func() error {
+		args := append([]string{"validate"}, extraArgs...)
+		_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
+		return r.run("validate", dir, stdout, stderr, r.config.Binary, args...)
+	})
 }
 
 // RunPlan executes terraform/tofu plan in the specified directory
@@ -83,14 +128,73 @@ func (r *Runner) RunPlan(dir string, extraArgs ...string) error {
 
 // RunPlanWithOutput executes terraform/tofu plan with custom output writers
 func (r *Runner) RunPlanWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
-	args := append([]string{"plan"}, extraArgs...)
-	cmd := exec.Command(r.config.Binary, args...) //nolint:gosec // Binary is validated to be terraform or tofu
-	cmd.Dir = dir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
+	return r.withHooks("plan", dir, stdout, stderr, func() error {
+		args := append([]string{"plan"}, extraArgs...)
+		_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
+		return r.run("plan", dir, stdout, stderr, r.config.Binary, args...)
+	})
+}
+
+// RunApply executes terraform/tofu apply in the specified directory.
+// Stdin is connected so terraform/tofu can prompt for approval.
+func (r *Runner) RunApply(dir string, extraArgs ...string) error {
+	return r.runWithInput(dir, os.Stdin, os.Stdout, os.Stderr, "apply", extraArgs...)
+}
+
+// RunApplyWithOutput executes terraform/tofu apply with custom output writers.
+// Stdin is not connected, so extraArgs should include -auto-approve or a saved plan file.
+func (r *Runner) RunApplyWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
+	return r.runWithInput(dir, nil, stdout, stderr, "apply", extraArgs...)
+}
+
+// RunDestroy executes terraform/tofu destroy in the specified directory.
+// Stdin is connected so terraform/tofu can prompt for approval.
+func (r *Runner) RunDestroy(dir string, extraArgs ...string) error {
+	return r.runWithInput(dir, os.Stdin, os.Stdout, os.Stderr, "destroy", extraArgs...)
+}
+
+// RunDestroyWithOutput executes terraform/tofu destroy with custom output writers.
+// Stdin is not connected, so extraArgs should include -auto-approve.
+func (r *Runner) RunDestroyWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
+	return r.runWithInput(dir, nil, stdout, stderr, "destroy", extraArgs...)
+}
 
-	_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
-	return cmd.Run()
+// runWithInput executes a terraform/tofu subcommand with the given stdin and output writers.
+// With stdin, the command can prompt on the terminal and receives Ctrl-C from it directly.
+func (r *Runner) runWithInput(dir string, stdin io.Reader, stdout, stderr io.Writer, subcommand string, extraArgs ...string) error {
+	return r.withHooks(subcommand, dir, stdout, stderr, func() error {
+		args := append([]string{subcommand}, extraArgs...)
+		_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
+		if stdin == nil {
+			return r.run(subcommand, dir, stdout, stderr, r.config.Binary, args...)
+		}
+
+		// Interactive commands are run once, without a timeout, like any command
+		// waiting on the user
+		cmd := process.Interactive(r.config.Binary, args...) //nolint:gosec // Binary is validated to be terraform or tofu
+		cmd.Dir = dir
+		cmd.Stdin = stdin
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd.Run()
+	})
+}
+
+// run executes name with args in dir, with the timeout and retries configured
+// for command
+func (r *Runner) run(command, dir string, stdout, stderr io.Writer, name string, args ...string) error {
+	return r.runWithPolicy(r.config.Execution.Policy(command), dir, stdout, stderr, name, args...)
+}
+
+// runWithPolicy executes name with args in dir, with the timeout and retries of policy
+func (r *Runner) runWithPolicy(policy process.Policy, dir string, stdout, stderr io.Writer, name string, args ...string) error {
+	return policy.Run(r.context(), stderr, func(ctx context.Context) *exec.Cmd {
+		cmd := process.Command(ctx, name, args...) //nolint:gosec // name is the validated terraform/tofu binary or go
+		cmd.Dir = dir
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd
+	})
 }
 
 // RunTest executes tests based on the configured test engine
@@ -100,7 +204,7 @@ func (r *Runner) RunTest(dir string, extraArgs ...string) error {
 
 // RunTestWithOutput executes tests with custom output writers
 func (r *Runner) RunTestWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
-	var cmd *exec.Cmd
+	var binary string
 	var cmdArgs []string
 
 	if !config.IsValidTestEngine(r.config.Test.Engine) {
@@ -121,8 +225,7 @@ func (r *Runner) RunTestWithOutput(dir string, stdout, stderr io.Writer, extraAr
 		// Add extra args from command line
 		cmdArgs = append(cmdArgs, extraArgs...)
 
-		cmd = exec.Command("go", cmdArgs...) //nolint:gosec // cmdArgs are constructed from validated config
-		_, _ = fmt.Fprintf(stdout, "Running go %s in %s\n", strings.Join(cmdArgs, " "), dir)
+		binary = "go"
 	case "terraform", "tofu":
 		// Terraform/Tofu native test command
 		cmdArgs = []string{"test"}
@@ -136,14 +239,11 @@ func (r *Runner) RunTestWithOutput(dir string, stdout, stderr io.Writer, extraAr
 		// Add extra args from command line
 		cmdArgs = append(cmdArgs, extraArgs...)
 
-		binary := r.config.Test.Engine
-		cmd = exec.Command(binary, cmdArgs...) //nolint:gosec // binary is validated to be terraform or tofu
-		_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", binary, strings.Join(cmdArgs, " "), dir)
+		binary = r.config.Test.Engine
 	}
 
-	cmd.Dir = dir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-
-	return cmd.Run()
+	return r.withHooks("test", dir, stdout, stderr, func() error {
+		_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", binary, strings.Join(cmdArgs, " "), dir)
+		return r.run("test", dir, stdout, stderr, binary, cmdArgs...)
+	})
 }
@@ -1,7 +1,17 @@
 package terraform
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 )
@@ -76,6 +86,41 @@ func TestRunner_InheritsConfigBinary(t *testing.T) {
 	}
 }
 
+func TestRunner_ForModule(t *testing.T) {
+	runner := NewRunner(config.DefaultConfig())
+
+	if got := runner.ForModule(nil); got != runner {
+		t.Error("expected the same runner for a module without metadata")
+	}
+	if got := runner.ForModule(&config.ModuleConfig{Tags: []string{"storage"}}); got != runner {
+		t.Error("expected the same runner for a module without overrides")
+	}
+
+	moduleRunner := runner.ForModule(&config.ModuleConfig{Binary: "tofu"})
+	if moduleRunner.Binary() != "tofu" {
+		t.Errorf("expected module runner Binary to be 'tofu', got '%s'", moduleRunner.Binary())
+	}
+	if runner.Binary() != "terraform" {
+		t.Errorf("ForModule must not change the original runner, got '%s'", runner.Binary())
+	}
+}
+
+func TestRunner_WithContext(t *testing.T) {
+	runner := NewRunner(&config.Config{Binary: "echo"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cancelled := runner.WithContext(ctx).ForModule(&config.ModuleConfig{Binary: "echo"})
+
+	var stdout bytes.Buffer
+	if err := cancelled.RunInitWithOutput(t.TempDir(), &stdout, &stdout); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled from a cancelled runner, got %v", err)
+	}
+	if err := runner.RunInitWithOutput(t.TempDir(), &stdout, &stdout); err != nil {
+		t.Errorf("WithContext must not change the original runner, got %v", err)
+	}
+}
+
 func TestRunner_WithDefaultConfig(t *testing.T) {
 	// Test that Runner works with default config values
 	cfg := config.DefaultConfig()
@@ -200,3 +245,110 @@ func TestRunner_RunTest_TofuEngine(t *testing.T) {
 
 	// The actual command would be: tofu test
 }
+
+// TestRunner_RunApplyWithOutput verifies the apply subcommand and arguments are passed to the binary
+func TestRunner_RunApplyWithOutput(t *testing.T) {
+	runner := NewRunner(&config.Config{Binary: "echo"})
+
+	var stdout bytes.Buffer
+	if err := runner.RunApplyWithOutput(t.TempDir(), &stdout, &stdout, "-auto-approve", "tfplan"); err != nil {
+		t.Fatalf("RunApplyWithOutput returned error: %v", err)
+	}
+
+	// "echo" prints its arguments, so the command line shows up after the "Running" line
+	if !strings.Contains(stdout.String(), "Running echo apply -auto-approve tfplan in") {
+		t.Errorf("expected running line in output, got: %s", stdout.String())
+	}
+	if !strings.HasSuffix(stdout.String(), "apply -auto-approve tfplan\n") {
+		t.Errorf("expected echoed arguments in output, got: %s", stdout.String())
+	}
+}
+
+// TestRunner_RunDestroyWithOutput verifies the destroy subcommand and arguments are passed to the binary
+func TestRunner_RunDestroyWithOutput(t *testing.T) {
+	runner := NewRunner(&config.Config{Binary: "echo"})
+
+	var stdout bytes.Buffer
+	if err := runner.RunDestroyWithOutput(t.TempDir(), &stdout, &stdout, "-auto-approve"); err != nil {
+		t.Fatalf("RunDestroyWithOutput returned error: %v", err)
+	}
+
+	if !strings.HasSuffix(stdout.String(), "destroy -auto-approve\n") {
+		t.Errorf("expected echoed arguments in output, got: %s", stdout.String())
+	}
+}
+
+// TestRunner_ApplyNotRetriedByDefault verifies global retries reach init but not apply
+func TestRunner_ApplyNotRetriedByDefault(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	binary := filepath.Join(dir, "fake-terraform")
+	script := "#!/bin/sh\necho \"$1\" >> \"" + runs + "\"\necho 'Error: Failed to install provider' >&2\nexit 1\n"
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	two := 2
+	runner := NewRunner(&config.Config{Binary: binary, Execution: &config.ExecutionConfig{
+		Retries:  2,
+		Backoff:  time.Millisecond,
+		Override: &config.CommandExecution{Retries: &two},
+	}})
+
+	var stdout bytes.Buffer
+	if err := runner.RunApplyWithOutput(t.TempDir(), &stdout, &stdout, "-auto-approve"); err == nil {
+		t.Fatal("expected apply to fail")
+	}
+	if err := runner.RunInitWithOutput(t.TempDir(), &stdout, &stdout); err == nil {
+		t.Fatal("expected init to fail")
+	}
+
+	data, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Fields(string(data)); !reflect.DeepEqual(got, []string{"apply", "init", "init", "init"}) {
+		t.Errorf("runs = %v, want apply once and init three times", got)
+	}
+}
+
+// TestRunner_WithHooks verifies hooks run around commands and a failing pre hook aborts the command
+func TestRunner_WithHooks(t *testing.T) {
+	var calls []string
+	hooks := func(stage, command, dir string, stdout, stderr io.Writer) error {
+		calls = append(calls, stage+"_"+command)
+		if stage == "pre" && command == "plan" {
+			return errors.New("hook failed")
+		}
+		return nil
+	}
+	runner := NewRunner(&config.Config{Binary: "echo"}).WithHooks(hooks)
+
+	var stdout bytes.Buffer
+	if err := runner.RunInitWithOutput(t.TempDir(), &stdout, &stdout); err != nil {
+		t.Fatalf("RunInitWithOutput returned error: %v", err)
+	}
+	if err := runner.RunPlanWithOutput(t.TempDir(), &stdout, &stdout); err == nil {
+		t.Fatal("expected the failing pre_plan hook to fail plan")
+	}
+
+	want := []string{"pre_init", "post_init", "pre_plan"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("hook calls = %v, want %v", calls, want)
+	}
+	if strings.Contains(stdout.String(), "Running echo plan") {
+		t.Errorf("expected plan not to run, got: %s", stdout.String())
+	}
+
+	// Module overrides keep the hooks
+	calls = nil
+	if err := runner.ForModule(&config.ModuleConfig{Binary: "echo"}).RunFmtWithOutput(t.TempDir(), &stdout, &stdout); err != nil {
+		t.Fatalf("RunFmtWithOutput returned error: %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"pre_fmt", "post_fmt"}) {
+		t.Errorf("hook calls = %v, want [pre_fmt post_fmt]", calls)
+	}
+}